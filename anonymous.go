@@ -0,0 +1,123 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogMatch is a plausible decoding of a log returned by ResolveLog.
+type LogMatch struct {
+	Event  *Fragment
+	Values []any // the values of all event parameters, as returned by DecodeLog
+
+	// TopicMatch is set when the event is not anonymous, so that the
+	// first topic of the log is its topic.
+	TopicMatch bool
+	// Exact is set when the topics and data of the log are exactly the
+	// encoding of the decoded values, without dirty padding, non-standard
+	// offsets nor trailing data bytes.
+	Exact bool
+}
+
+// ResolveLog decodes a log with each of the candidate event signatures,
+// which must be human-readable with their indexed parameters marked
+// (i.e. `event LogNote(bytes4 indexed sig, address indexed usr, bytes32
+// indexed arg1, bytes32 indexed arg2, bytes data) anonymous`), returning
+// all plausible decodings ranked from the most to the least likely.
+//
+// It is meant for anonymous events, which have no topic0 telling them
+// apart: candidates are ruled out when their number of indexed
+// parameters doesn't match the topics of the log or when the log can't
+// be decoded with them. Non-anonymous candidates whose topic matches the
+// first topic of the log rank first, then candidates exactly matching
+// the log topics and data bytes. Other candidates keep their given
+// order. An error is returned when no candidate matches.
+func ResolveLog(log types.Log, eventSigs ...string) ([]LogMatch, error) {
+	events := make([]*Fragment, len(eventSigs))
+	for i, eventSig := range eventSigs {
+		event, err := ParseFragment(eventSig)
+		if err != nil {
+			return nil, fmt.Errorf("[ResolveLog] %w", err)
+		}
+		if event.Type != "event" {
+			return nil, fmt.Errorf("[ResolveLog] %s is not an event", eventSig)
+		}
+		events[i] = event
+	}
+
+	return resolveLog(log, events)
+}
+
+// ResolveLog decodes a log with each event of the contract, including
+// anonymous ones, returning all plausible decodings ranked as done by
+// the ResolveLog function.
+func (c *Contract) ResolveLog(log types.Log) ([]LogMatch, error) {
+	return resolveLog(log, c.Events)
+}
+
+// resolveLog decodes a log with each candidate event and ranks the
+// plausible decodings.
+func resolveLog(log types.Log, events []*Fragment) ([]LogMatch, error) {
+	var matches []LogMatch
+	for _, event := range events {
+		values, err := DecodeLog(log, event)
+		if err != nil {
+			continue
+		}
+
+		matches = append(matches, LogMatch{
+			Event:      event,
+			Values:     values,
+			TopicMatch: !event.Anonymous,
+			Exact:      isExactLog(log, event, values),
+		})
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("[ResolveLog] no candidate event matches log with %d topics and %d data bytes", len(log.Topics), len(log.Data))
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].TopicMatch != matches[j].TopicMatch {
+			return matches[i].TopicMatch
+		}
+		return matches[i].Exact && !matches[j].Exact
+	})
+
+	return matches, nil
+}
+
+// isExactLog checks whether the topics and data of a log are exactly
+// the encoding of the values decoded with given event.
+func isExactLog(log types.Log, event *Fragment, values []any) bool {
+	topics := log.Topics
+	if !event.Anonymous {
+		topics = topics[1:]
+	}
+
+	var dataTypes []string
+	var dataValues []any
+	var topicIndex int
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			dataTypes = append(dataTypes, input.CanonicalType())
+			dataValues = append(dataValues, values[i])
+			continue
+		}
+
+		topic, err := encodeTopic(input.CanonicalType(), values[i])
+		if err != nil || topic != topics[topicIndex] {
+			return false
+		}
+		topicIndex++
+	}
+
+	if len(dataTypes) == 0 {
+		return len(log.Data) == 0
+	}
+	encoded, err := Encode(dataTypes, dataValues...)
+
+	return err == nil && bytes.Equal(encoded, log.Data)
+}
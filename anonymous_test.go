@@ -0,0 +1,52 @@
+package abi_test
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleResolveLog() {
+	// MakerDAO's DSS contracts log calls with an anonymous LogNote event,
+	// whose first topic is the selector of the called function.
+	usr := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	calldata, err := abi.EncodeWithSignature("rely(address)", usr)
+	if err != nil {
+		fmt.Println(err)
+	}
+	data, err := abi.Encode([]string{"bytes"}, calldata)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	log := types.Log{
+		Topics: []common.Hash{
+			common.BytesToHash(common.RightPadBytes(calldata[:4], 32)),
+			common.HexToHash("0x000000000000000000000000be8e3e3618f7474f8cb1d074a26affef007e98fb"),
+			common.BytesToHash(usr.Bytes()),
+			{},
+		},
+		Data: data,
+	}
+
+	matches, err := abi.ResolveLog(log,
+		"event LogNote(bytes4 indexed sig, address indexed guy, bytes32 indexed foo, bytes32 indexed bar, uint256 wad) anonymous",
+		"event LogNote(bytes4 indexed sig, address indexed usr, bytes32 indexed arg1, bytes32 indexed arg2, bytes data) anonymous",
+		"event Transfer(address indexed from, address indexed to, uint256 indexed tokenId)",
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	for _, match := range matches {
+		fmt.Println(match.Event.Signature(), match.Exact)
+	}
+	fmt.Printf("0x%x\n", matches[0].Values[4])
+
+	// Output:
+	// LogNote(bytes4,address,bytes32,bytes32,bytes) true
+	// LogNote(bytes4,address,bytes32,bytes32,uint256) false
+	// 0x65fae35e0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d2789
+}
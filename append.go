@@ -0,0 +1,521 @@
+package abi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// encodingType is a type string parsed once for EncodeAppend, so that
+// encoding values of the same type again doesn't split type strings.
+type encodingType struct {
+	typeStr    string
+	dynamic    bool
+	headSize   int
+	elem       *encodingType   // element type of arrays
+	arraySize  int             // length of bounded arrays
+	components []*encodingType // member types of tuples
+	bits       int             // bit size of integers
+	isUint     bool
+	bytesSize  int // size of fixed bytes
+}
+
+// cachedEncoding is the result of newEncodingType held by the type
+// cache.
+type cachedEncoding struct {
+	t   *encodingType
+	err error
+}
+
+// encodingTypeFor returns the cached encoding type of given type string.
+func encodingTypeFor(typeStr string) (*encodingType, error) {
+	e := typeCached(cachedEncodingType, typeStr, func() cachedEncoding {
+		t, err := newEncodingType(typeStr)
+		return cachedEncoding{t: t, err: err}
+	})
+
+	return e.t, e.err
+}
+
+// newEncodingType parses given type string.
+func newEncodingType(typeStr string) (*encodingType, error) {
+	isTypeTuple, splitedTypes, err := tupleType(typeStr)
+	if err != nil {
+		return nil, err
+	}
+	isTypeArray, arraySize, err := IsArray(typeStr)
+	if err != nil {
+		return nil, err
+	}
+	size, err := headSize(typeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &encodingType{
+		typeStr:  typeStr,
+		dynamic:  IsDynamic(typeStr, isTypeTuple),
+		headSize: size,
+	}
+
+	switch {
+	case isTypeArray:
+		t.arraySize = arraySize
+		t.elem, err = newEncodingType(typeStr[:strings.LastIndex(typeStr, "[")])
+		if err != nil {
+			return nil, err
+		}
+	case isTypeTuple:
+		t.components = make([]*encodingType, len(splitedTypes))
+		for i, splitedType := range splitedTypes {
+			t.components[i], err = newEncodingType(splitedType)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case strings.HasPrefix(typeStr, "bytes") && typeStr != "bytes":
+		if size, err := strconv.Atoi(typeStr[len("bytes"):]); err == nil && size >= 1 && size <= 32 {
+			t.bytesSize = size
+		}
+	default:
+		t.bits, t.isUint = integerBits(typeStr)
+	}
+
+	return t, nil
+}
+
+// isTuple checks whether t is a tuple, which may have no members.
+func (t *encodingType) isTuple() bool {
+	return t.components != nil
+}
+
+// EncodeAppend encodes given arguments based on provided types, like
+// Encode, and appends the result to dst, returning the extended slice.
+//
+// Values are written in place instead of being built as a tree of
+// chunks, and type strings are parsed once, so encoding into a reused
+// buffer (see GetBuffer) doesn't allocate for the common values, i.e.
+// *big.Int, native integers, common.Address, bool, string, []byte and
+// []any for arrays and tuples. Other values are converted as by Encode.
+// On error, dst is returned with its original length.
+func EncodeAppend(dst []byte, typeStrs []string, values ...any) ([]byte, error) {
+	if len(typeStrs) != len(values) {
+		return dst, fmt.Errorf(
+			"typeStrs and values must have the same length. typeStrs: %v (length %v), values: %v (length %v)",
+			typeStrs,
+			len(typeStrs),
+			values,
+			len(values),
+		)
+	}
+
+	// Avoid allocating the list of types for short argument lists.
+	var array [8]*encodingType
+	types := array[:0]
+	if len(typeStrs) > len(array) {
+		types = make([]*encodingType, 0, len(typeStrs))
+	}
+	for _, typeStr := range typeStrs {
+		t, err := encodingTypeFor(typeStr)
+		if err != nil {
+			return dst, err
+		}
+		types = append(types, t)
+	}
+
+	encoded, err := appendSequence(dst, types, nil, values)
+	if err != nil {
+		return dst, err
+	}
+
+	return encoded, nil
+}
+
+// EncodeWithSelectorAppend encodes function call based on its selector,
+// like EncodeWithSelector, and appends the result to dst.
+func EncodeWithSelectorAppend(dst []byte, selector []byte, typeStrs []string, params ...any) ([]byte, error) {
+	if len(typeStrs) != len(params) {
+		return dst, fmt.Errorf("number of parameter types and given paramenters mismatch")
+	}
+	if len(selector) > 4 {
+		return dst, fmt.Errorf("value and type bytes size mismatch: type bytes4; value bytes size %v", len(selector))
+	}
+
+	base := len(dst)
+	dst = append(dst, selector...)
+	dst = appendZeros(dst, 4-len(selector))
+
+	encoded, err := EncodeAppend(dst, typeStrs, params...)
+	if err != nil {
+		return dst[:base], err
+	}
+
+	return encoded, nil
+}
+
+// appendSequence appends the encoding of a list of values, which are
+// the arguments, the members of a tuple, or the elements of an array
+// when elem is given. Static values are written in the head reserved
+// first, and dynamic values are appended after it.
+func appendSequence(dst []byte, types []*encodingType, elem *encodingType, values []any) ([]byte, error) {
+	typeAt := func(i int) *encodingType {
+		if elem != nil {
+			return elem
+		}
+		return types[i]
+	}
+
+	base := len(dst)
+	var headLength int
+	for i := range values {
+		headLength += typeAt(i).headSize
+	}
+	dst = appendZeros(dst, headLength)
+
+	headPos := base
+	for i, value := range values {
+		t := typeAt(i)
+		if t.dynamic {
+			putWord(dst[headPos:], uint64(len(dst)-base))
+
+			var err error
+			dst, err = appendValue(dst, t, value)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// The head has its final capacity, so appending from the
+			// position of the value overwrites its reserved bytes.
+			encoded, err := appendValue(dst[:headPos], t, value)
+			if err != nil {
+				return nil, err
+			}
+			if len(encoded) != headPos+t.headSize {
+				return nil, fmt.Errorf("invalid encoded size for %s", t.typeStr)
+			}
+		}
+		headPos += t.headSize
+	}
+
+	return dst, nil
+}
+
+// appendValue appends the encoding of a single value.
+func appendValue(dst []byte, t *encodingType, value any) ([]byte, error) {
+	switch {
+	case t.elem != nil:
+		list, err := toAnyList(t.typeStr, value)
+		if err != nil {
+			return nil, err
+		}
+		if t.arraySize != 0 && len(list) != t.arraySize {
+			return nil, fmt.Errorf("array size mismatch")
+		}
+
+		// Only unbounded arrays are prefixed with their length.
+		if t.arraySize == 0 {
+			dst = appendWord(dst, uint64(len(list)))
+		}
+
+		return appendSequence(dst, nil, t.elem, list)
+
+	case t.isTuple():
+		if value == nil {
+			return appendZeros(dst, t.headSize), nil
+		}
+
+		list, err := toAnyList(t.typeStr, value)
+		if err != nil {
+			return nil, err
+		}
+		if len(list) != len(t.components) {
+			return nil, fmt.Errorf(
+				"typeStrs and values must have the same length. typeStrs: %v (length %v), values: %v (length %v)",
+				t.typeStr,
+				len(t.components),
+				list,
+				len(list),
+			)
+		}
+
+		return appendSequence(dst, t.components, nil, list)
+	}
+
+	if encoded, ok := appendCore(dst, t, value); ok {
+		return encoded, nil
+	}
+
+	encoded, err := encode(t.typeStr, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(dst, encoded...), nil
+}
+
+// appendCore appends the encoding of the common core values without
+// converting them. It reports false for other values, which are
+// encoded by encode.
+func appendCore(dst []byte, t *encodingType, value any) ([]byte, bool) {
+	switch t.typeStr {
+	case "address":
+		switch value := value.(type) {
+		case common.Address:
+			return append(appendZeros(dst, 32-common.AddressLength), value[:]...), true
+		case *common.Address:
+			if value != nil {
+				return append(appendZeros(dst, 32-common.AddressLength), value[:]...), true
+			}
+		}
+		return nil, false
+
+	case "bool":
+		value, ok := value.(bool)
+		if !ok {
+			return nil, false
+		}
+		var word uint64
+		if value {
+			word = 1
+		}
+		return appendWord(dst, word), true
+
+	case "string":
+		value, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		dst = appendWord(dst, uint64(len(value)))
+		dst = append(dst, value...)
+		return appendZeros(dst, padding(len(value))), true
+
+	case "bytes":
+		value, ok := value.([]byte)
+		if !ok {
+			return nil, false
+		}
+		dst = appendWord(dst, uint64(len(value)))
+		dst = append(dst, value...)
+		return appendZeros(dst, padding(len(value))), true
+	}
+
+	if t.bytesSize > 0 {
+		var b []byte
+		switch value := value.(type) {
+		case []byte:
+			b = value
+		case common.Hash:
+			if t.bytesSize != common.HashLength {
+				return nil, false
+			}
+			return append(dst, value[:]...), true
+		default:
+			return nil, false
+		}
+		if len(b) > t.bytesSize {
+			return nil, false
+		}
+		dst = append(dst, b...)
+		return appendZeros(dst, 32-len(b)), true
+	}
+
+	if t.bits == 0 {
+		return nil, false
+	}
+
+	// Values out of range are left to encode, which reports them.
+	maxBits := t.bits
+	if !t.isUint {
+		maxBits--
+	}
+	switch value := value.(type) {
+	case *big.Int:
+		if value != nil && value.Sign() < 0 && !t.isUint {
+			// Negative values are written as the two's complement of
+			// -value-1, whose bits are flipped.
+			complement := new(big.Int).Not(value)
+			if complement.BitLen() > maxBits {
+				return nil, false
+			}
+			dst = appendZeros(dst, 32)
+			word := dst[len(dst)-32:]
+			complement.FillBytes(word)
+			for i := range word {
+				word[i] = ^word[i]
+			}
+			return dst, true
+		}
+		if value == nil || value.Sign() < 0 || value.BitLen() > maxBits {
+			return nil, false
+		}
+		dst = appendZeros(dst, 32)
+		value.FillBytes(dst[len(dst)-32:])
+		return dst, true
+	case *uint256.Int:
+		if value == nil || !t.isUint || value.BitLen() > maxBits {
+			return nil, false
+		}
+		dst = appendZeros(dst, 32)
+		value.PutUint256(dst[len(dst)-32:])
+		return dst, true
+	}
+
+	if signed, ok := nativeSigned(value); ok && signed < 0 {
+		if t.isUint || (maxBits < 64 && ^signed>>maxBits != 0) {
+			return nil, false
+		}
+		// Negative values are sign extended to the whole word.
+		dst = append(dst, negativeWordPrefix[:]...)
+		return binary.BigEndian.AppendUint64(dst, uint64(signed)), true
+	}
+
+	word, ok := nativeWord(value)
+	if !ok || (maxBits < 64 && word>>maxBits != 0) {
+		return nil, false
+	}
+
+	return appendWord(dst, word), true
+}
+
+// negativeWordPrefix holds the first 24 bytes of the words of negative
+// native integers.
+var negativeWordPrefix = [24]byte{
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+}
+
+// nativeSigned converts a signed native integer into an int64.
+func nativeSigned(value any) (int64, bool) {
+	switch value := value.(type) {
+	case int:
+		return int64(value), true
+	case int8:
+		return int64(value), true
+	case int16:
+		return int64(value), true
+	case int32:
+		return int64(value), true
+	case int64:
+		return value, true
+	}
+
+	return 0, false
+}
+
+// nativeWord converts a non-negative native integer into an uint64.
+func nativeWord(value any) (uint64, bool) {
+	var signed int64
+	switch value := value.(type) {
+	case uint:
+		return uint64(value), true
+	case uint8:
+		return uint64(value), true
+	case uint16:
+		return uint64(value), true
+	case uint32:
+		return uint64(value), true
+	case uint64:
+		return value, true
+	case int:
+		signed = int64(value)
+	case int8:
+		signed = int64(value)
+	case int16:
+		signed = int64(value)
+	case int32:
+		signed = int64(value)
+	case int64:
+		signed = value
+	default:
+		return 0, false
+	}
+
+	return uint64(signed), signed >= 0
+}
+
+// appendZeros appends n zero bytes.
+func appendZeros(dst []byte, n int) []byte {
+	dst = slices.Grow(dst, n)
+	dst = dst[:len(dst)+n]
+	clear(dst[len(dst)-n:])
+
+	return dst
+}
+
+// appendWord appends a 32 bytes word holding given value.
+func appendWord(dst []byte, value uint64) []byte {
+	dst = appendZeros(dst, 32)
+	putWord(dst[len(dst)-32:], value)
+
+	return dst
+}
+
+// putWord writes a 32 bytes word holding given value at the start of
+// dst, whose bytes are expected to be zero.
+func putWord(dst []byte, value uint64) {
+	binary.BigEndian.PutUint64(dst[24:32], value)
+}
+
+// padding returns the number of bytes padding a value of given length
+// to a multiple of 32 bytes.
+func padding(length int) int {
+	return (32 - length%32) % 32
+}
+
+// bufferPool holds the buffers returned by GetBuffer.
+var bufferPool = sync.Pool{
+	New: func() any {
+		return &Buffer{B: make([]byte, 0, 1024)}
+	},
+}
+
+// Buffer is a reusable encoding buffer, obtained with GetBuffer. Its
+// bytes are only valid until it is reused or put back with PutBuffer.
+type Buffer struct {
+	B []byte
+}
+
+// GetBuffer returns an empty buffer from a pool, so that hot loops and
+// concurrent handlers encoding many values don't allocate a new slice
+// per call. Put the buffer back with PutBuffer once done with its bytes.
+func GetBuffer() *Buffer {
+	return bufferPool.Get().(*Buffer)
+}
+
+// PutBuffer resets given buffer and puts it back in the pool.
+func PutBuffer(b *Buffer) {
+	b.B = b.B[:0]
+	bufferPool.Put(b)
+}
+
+// Encode replaces the content of the buffer with the encoding of given
+// arguments based on provided types, and returns it.
+func (b *Buffer) Encode(typeStrs []string, values ...any) ([]byte, error) {
+	encoded, err := EncodeAppend(b.B[:0], typeStrs, values...)
+	if err != nil {
+		return nil, err
+	}
+	b.B = encoded
+
+	return encoded, nil
+}
+
+// EncodeWithSelector replaces the content of the buffer with the
+// encoding of a function call based on its selector, and returns it.
+func (b *Buffer) EncodeWithSelector(selector []byte, typeStrs []string, params ...any) ([]byte, error) {
+	encoded, err := EncodeWithSelectorAppend(b.B[:0], selector, typeStrs, params...)
+	if err != nil {
+		return nil, err
+	}
+	b.B = encoded
+
+	return encoded, nil
+}
@@ -0,0 +1,94 @@
+package abi_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleEncodeAppend() {
+	recipient := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	types := []string{"address", "uint256"}
+
+	buf := abi.GetBuffer()
+	defer abi.PutBuffer(buf)
+
+	for _, amount := range []int64{1, 2} {
+		calldata, err := buf.EncodeWithSelector(abi.EncodeSignature("transfer(address,uint256)"), types, recipient, big.NewInt(amount))
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		encoded, _ := abi.EncodeWithSignature("transfer(address,uint256)", &recipient, big.NewInt(amount))
+		fmt.Println(common.Bytes2Hex(calldata[:4]), len(calldata), bytes.Equal(calldata, encoded))
+	}
+
+	prefixed, err := abi.EncodeAppend([]byte{0x19, 0x00}, []string{"string"}, "hello")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(common.Bytes2Hex(prefixed[:2]), len(prefixed))
+
+	// Output:
+	// a9059cbb 68 true
+	// a9059cbb 68 true
+	// 1900 98
+}
+
+// benchmarkValues are the arguments of a typical swap call.
+func benchmarkValues() ([]string, []any) {
+	token := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	types := []string{"address", "uint256", "uint256", "address[]", "bytes"}
+	values := []any{
+		token,
+		big.NewInt(1_000_000),
+		big.NewInt(990_000),
+		[]any{token, token, token},
+		[]byte("arbitrary byte array..."),
+	}
+
+	return types, values
+}
+
+func BenchmarkEncode(b *testing.B) {
+	types, values := benchmarkValues()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := abi.Encode(types, values...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeAppend(b *testing.B) {
+	types, values := benchmarkValues()
+	var dst []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		dst, err = abi.EncodeAppend(dst[:0], types, values...)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuffer(b *testing.B) {
+	types, values := benchmarkValues()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := abi.GetBuffer()
+			if _, err := buf.Encode(types, values...); err != nil {
+				b.Fatal(err)
+			}
+			abi.PutBuffer(buf)
+		}
+	})
+}
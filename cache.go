@@ -0,0 +1,405 @@
+package abi
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultSignatureCacheSize is the number of entries held by the
+// signature cache unless changed with SetSignatureCacheSize.
+const DefaultSignatureCacheSize = 4096
+
+// DefaultTypeCacheSize is the number of entries held by the type cache
+// unless changed with SetTypeCacheSize.
+const DefaultTypeCacheSize = 4096
+
+// CacheEvent is an event of the signature or type cache reported to the
+// hook set with SetSignatureCacheHook.
+type CacheEvent int
+
+const (
+	CacheHit CacheEvent = iota
+	CacheMiss
+	CacheEviction
+)
+
+// String implements the fmt.Stringer interface.
+func (e CacheEvent) String() string {
+	switch e {
+	case CacheHit:
+		return "hit"
+	case CacheMiss:
+		return "miss"
+	case CacheEviction:
+		return "eviction"
+	default:
+		return "CacheEvent(" + strconv.Itoa(int(e)) + ")"
+	}
+}
+
+// CacheStats reports the activity of the signature or type cache since
+// the program started.
+type CacheStats struct {
+	Len       int // number of cached entries
+	Size      int // maximum number of cached entries
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// cacheKind tells apart the kinds of values held by the signature
+// cache.
+type cacheKind uint8
+
+const (
+	cachedFragment     cacheKind = iota // *Fragment parsed by ParseFragment
+	cachedHash                          // common.Hash of a canonical signature
+	cachedTypePlan                      // *typePlan of a type string
+	cachedEncodingType                  // cachedEncoding of a type string
+)
+
+// cacheKey is the key of a cached value.
+type cacheKey struct {
+	kind cacheKind
+	key  string
+}
+
+// cacheEntry is a cached value along with its key, held by the
+// elements of the recency list.
+type cacheEntry struct {
+	key   cacheKey
+	value any
+}
+
+// signatureCache caches parsed fragments and signature hashes
+// (selectors and topics), so that signatures used repeatedly (i.e. by
+// ParseLog or DecodeCalldata for each log or transaction) are parsed and
+// hashed once. It evicts the least recently used entries and is safe for
+// concurrent use.
+var signatureCache = struct {
+	sync.Mutex
+	size    int
+	entries map[cacheKey]*list.Element
+	recency *list.List // front is the most recently used entry
+	stats   CacheStats
+}{
+	size:    DefaultSignatureCacheSize,
+	entries: make(map[cacheKey]*list.Element),
+	recency: list.New(),
+}
+
+// typeCacheEntry is a value held by the type cache.
+type typeCacheEntry struct {
+	key   cacheKey
+	value any
+	used  atomic.Bool // set on hits, cleared when the clock hand passes
+}
+
+// typeCache caches type plans and encoding types, which are read for
+// each encoded or decoded value, apart from the signature cache so that
+// they don't evict each other. Hits are lock-free. Misses insert entries
+// under the lock, evicting when the cache is full the first entry not
+// used since the clock hand last passed it (the CLOCK approximation of
+// LRU).
+var typeCache = struct {
+	sync.Mutex                   // guards clock, hand and size
+	entries    sync.Map          // map[cacheKey]*typeCacheEntry
+	clock      []*typeCacheEntry // entries in clock order
+	hand       int
+	size       int
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	evictions  atomic.Uint64
+}{
+	size: DefaultTypeCacheSize,
+}
+
+// cacheHook is the hook set with SetSignatureCacheHook.
+var cacheHook atomic.Pointer[func(event CacheEvent, key string)]
+
+// SetSignatureCacheSize sets the maximum number of entries held by the
+// signature cache, evicting the least recently used entries exceeding
+// it. A size of zero or less disables the cache.
+func SetSignatureCacheSize(size int) {
+	signatureCache.Lock()
+	defer signatureCache.Unlock()
+
+	signatureCache.size = size
+	evictCacheEntries()
+}
+
+// SignatureCacheStats returns the current statistics of the signature
+// cache, i.e. to be exported as metrics.
+func SignatureCacheStats() CacheStats {
+	signatureCache.Lock()
+	defer signatureCache.Unlock()
+
+	stats := signatureCache.stats
+	stats.Len = signatureCache.recency.Len()
+	stats.Size = signatureCache.size
+
+	return stats
+}
+
+// SetTypeCacheSize sets the maximum number of entries held by the type
+// cache, which holds the layouts of type strings read by IsArray,
+// IsTuple, IsDynamic and the encoders. A size of zero or less disables
+// the cache.
+func SetTypeCacheSize(size int) {
+	typeCache.Lock()
+	defer typeCache.Unlock()
+
+	typeCache.size = size
+	for len(typeCache.clock) > 0 && len(typeCache.clock) > size {
+		evictTypeCacheEntry()
+	}
+}
+
+// TypeCacheStats returns the current statistics of the type cache.
+func TypeCacheStats() CacheStats {
+	typeCache.Lock()
+	defer typeCache.Unlock()
+
+	return CacheStats{
+		Len:       len(typeCache.clock),
+		Size:      typeCache.size,
+		Hits:      typeCache.hits.Load(),
+		Misses:    typeCache.misses.Load(),
+		Evictions: typeCache.evictions.Load(),
+	}
+}
+
+// SetSignatureCacheHook sets a function called on each hit, miss and
+// eviction of the signature and type caches with the signature or type
+// string involved, i.e. to be counted as metrics. It is called
+// synchronously, possibly concurrently and while a cache is locked, so
+// it must be fast, safe for concurrent use and must not use the caches.
+// Setting a nil fn removes the hook.
+func SetSignatureCacheHook(fn func(event CacheEvent, key string)) {
+	if fn == nil {
+		cacheHook.Store(nil)
+		return
+	}
+	cacheHook.Store(&fn)
+}
+
+// cached returns the value cached for given key, computing and caching
+// it with compute when missing. Values are computed without holding the
+// lock, so concurrent misses may compute the same value.
+func cached[V any](kind cacheKind, key string, compute func() V) V {
+	k := cacheKey{kind: kind, key: key}
+
+	signatureCache.Lock()
+	if signatureCache.size <= 0 {
+		signatureCache.Unlock()
+		return compute()
+	}
+	if element, ok := signatureCache.entries[k]; ok {
+		signatureCache.recency.MoveToFront(element)
+		signatureCache.stats.Hits++
+		cacheEvent(CacheHit, key)
+		signatureCache.Unlock()
+		return element.Value.(*cacheEntry).value.(V)
+	}
+	signatureCache.stats.Misses++
+	cacheEvent(CacheMiss, key)
+	signatureCache.Unlock()
+
+	value := compute()
+
+	signatureCache.Lock()
+	defer signatureCache.Unlock()
+	if _, ok := signatureCache.entries[k]; !ok && signatureCache.size > 0 {
+		signatureCache.entries[k] = signatureCache.recency.PushFront(&cacheEntry{key: k, value: value})
+		evictCacheEntries()
+	}
+
+	return value
+}
+
+// evictCacheEntries evicts the least recently used entries exceeding
+// the size of the cache, which must be locked.
+func evictCacheEntries() {
+	for signatureCache.recency.Len() > 0 && signatureCache.recency.Len() > signatureCache.size {
+		entry := signatureCache.recency.Remove(signatureCache.recency.Back()).(*cacheEntry)
+		delete(signatureCache.entries, entry.key)
+		signatureCache.stats.Evictions++
+		cacheEvent(CacheEviction, entry.key.key)
+	}
+}
+
+// cacheEvent reports an event to the hook of the caches.
+func cacheEvent(event CacheEvent, key string) {
+	if hook := cacheHook.Load(); hook != nil {
+		(*hook)(event, key)
+	}
+}
+
+// typeCached returns the value held by the type cache for given key,
+// computing and caching it with compute when missing. Like cached,
+// concurrent misses may compute the same value.
+func typeCached[V any](kind cacheKind, key string, compute func() V) V {
+	k := cacheKey{kind: kind, key: key}
+	if e, ok := typeCache.entries.Load(k); ok {
+		entry := e.(*typeCacheEntry)
+		if !entry.used.Load() {
+			entry.used.Store(true)
+		}
+		typeCache.hits.Add(1)
+		cacheEvent(CacheHit, key)
+		return entry.value.(V)
+	}
+	typeCache.misses.Add(1)
+	cacheEvent(CacheMiss, key)
+
+	value := compute()
+
+	typeCache.Lock()
+	defer typeCache.Unlock()
+	if _, ok := typeCache.entries.Load(k); !ok && typeCache.size > 0 {
+		if len(typeCache.clock) >= typeCache.size {
+			evictTypeCacheEntry()
+		}
+		entry := &typeCacheEntry{key: k, value: value}
+		typeCache.clock = append(typeCache.clock, entry)
+		typeCache.entries.Store(k, entry)
+	}
+
+	return value
+}
+
+// evictTypeCacheEntry evicts the first entry not used since the clock
+// hand last passed it, clearing the used flag of the entries passed.
+// The type cache must be locked and not empty.
+func evictTypeCacheEntry() {
+	clock := typeCache.clock
+	for {
+		if typeCache.hand >= len(clock) {
+			typeCache.hand = 0
+		}
+		entry := clock[typeCache.hand]
+		if entry.used.Swap(false) {
+			typeCache.hand++
+			continue
+		}
+
+		last := len(clock) - 1
+		clock[typeCache.hand] = clock[last]
+		clock[last] = nil
+		typeCache.clock = clock[:last]
+		typeCache.entries.Delete(entry.key)
+		typeCache.evictions.Add(1)
+		cacheEvent(CacheEviction, entry.key.key)
+		return
+	}
+}
+
+// signatureHash returns the keccak256 hash of a canonical signature,
+// whose first 4 bytes are the selector of functions and errors and
+// which is the topic of events.
+func signatureHash(signature string) common.Hash {
+	return cached(cachedHash, signature, func() common.Hash {
+		return crypto.Keccak256Hash([]byte(signature))
+	})
+}
+
+// cachedParse is the result of ParseFragment held by the cache.
+type cachedParse struct {
+	fragment *Fragment
+	err      error
+}
+
+// typePlan holds the layout of a type string, as returned by IsArray,
+// IsTuple, IsDynamic and headSize. Plans are shared and must not be
+// modified.
+type typePlan struct {
+	isArray   bool
+	arraySize int
+	arrayErr  error
+
+	isTuple  bool
+	members  []string
+	tupleErr error
+
+	dynamic  bool
+	headSize int
+	headErr  error
+}
+
+// typePlanFor returns the cached plan of given type string.
+func typePlanFor(typeStr string) *typePlan {
+	return typeCached(cachedTypePlan, typeStr, func() *typePlan {
+		return newTypePlan(typeStr)
+	})
+}
+
+// newTypePlan computes the plan of given type string, using the cached
+// plans of its array elements and tuple members.
+func newTypePlan(typeStr string) *typePlan {
+	p := &typePlan{}
+	p.isArray, p.arraySize, p.arrayErr = parseArrayType(typeStr)
+	p.isTuple, p.members, p.tupleErr = parseTupleType(typeStr)
+
+	switch {
+	case p.arrayErr != nil:
+	case p.isArray:
+		elem := typePlanFor(arrayElemType(typeStr))
+		p.dynamic = p.arraySize == 0 || elem.dynamic
+	case p.tupleErr != nil:
+	case p.isTuple:
+		for _, member := range p.members {
+			if typePlanFor(member).dynamic {
+				p.dynamic = true
+				break
+			}
+		}
+	default:
+		p.dynamic = typeStr == "string" || typeStr == "bytes"
+	}
+
+	switch {
+	case p.dynamic:
+		p.headSize = 32
+	case p.arrayErr != nil:
+		p.headErr = p.arrayErr
+	case p.isArray:
+		elem := typePlanFor(arrayElemType(typeStr))
+		switch {
+		case elem.headErr != nil:
+			p.headErr = elem.headErr
+		case elem.headSize != 0 && p.arraySize > math.MaxInt/elem.headSize:
+			p.headErr = fmt.Errorf("size of %s overflows", typeStr)
+		default:
+			p.headSize = p.arraySize * elem.headSize
+		}
+	case p.tupleErr != nil:
+		p.headErr = p.tupleErr
+	case p.isTuple:
+		for _, member := range p.members {
+			memberPlan := typePlanFor(member)
+			if memberPlan.headErr != nil {
+				p.headErr = memberPlan.headErr
+				break
+			}
+			if p.headSize > math.MaxInt-memberPlan.headSize {
+				p.headErr = fmt.Errorf("size of %s overflows", typeStr)
+				break
+			}
+			p.headSize += memberPlan.headSize
+		}
+	default:
+		p.headSize = 32
+	}
+	if p.headErr != nil {
+		p.headSize = 0
+	}
+
+	return p
+}
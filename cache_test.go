@@ -0,0 +1,97 @@
+package abi_test
+
+import (
+	"fmt"
+
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleSetSignatureCacheHook() {
+	sig := "event Pinged(address indexed sender, uint256 nonce)"
+	abi.SetSignatureCacheHook(func(event abi.CacheEvent, key string) {
+		if key == sig {
+			fmt.Println(event, key)
+		}
+	})
+	defer abi.SetSignatureCacheHook(nil)
+
+	// The second call returns a copy of the cached fragment.
+	for i := 0; i < 2; i++ {
+		_, err := abi.ParseFragment(sig)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	fmt.Println(abi.SignatureCacheStats().Size)
+
+	// Output:
+	// miss event Pinged(address indexed sender, uint256 nonce)
+	// hit event Pinged(address indexed sender, uint256 nonce)
+	// 4096
+}
+
+func ExampleSetSignatureCacheSize() {
+	first := "function ping(uint256 nonce)"
+	second := "function pong(uint256 nonce)"
+	third := "function peng(uint256 nonce)"
+
+	abi.SetSignatureCacheSize(0)
+	abi.SetSignatureCacheSize(2)
+	defer abi.SetSignatureCacheSize(abi.DefaultSignatureCacheSize)
+
+	abi.SetSignatureCacheHook(func(event abi.CacheEvent, key string) {
+		if key == first || key == second || key == third {
+			fmt.Println(event, key)
+		}
+	})
+	defer abi.SetSignatureCacheHook(nil)
+
+	// Using the first signature again makes the second one the least
+	// recently used, evicted to hold the third one.
+	for _, sig := range []string{first, second, first, third} {
+		_, err := abi.ParseFragment(sig)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	stats := abi.SignatureCacheStats()
+	fmt.Println(stats.Len, stats.Size)
+
+	// Output:
+	// miss function ping(uint256 nonce)
+	// miss function pong(uint256 nonce)
+	// hit function ping(uint256 nonce)
+	// miss function peng(uint256 nonce)
+	// eviction function pong(uint256 nonce)
+	// 2 2
+}
+
+func ExampleSetTypeCacheSize() {
+	abi.SetTypeCacheSize(0)
+	abi.SetTypeCacheSize(2)
+	defer abi.SetTypeCacheSize(abi.DefaultTypeCacheSize)
+
+	abi.SetSignatureCacheHook(func(event abi.CacheEvent, key string) {
+		fmt.Println(event, key)
+	})
+	defer abi.SetSignatureCacheHook(nil)
+
+	// The layout of uint24 is used again before the cache is full, so
+	// that int24 is evicted to hold bytes24.
+	for _, typeStr := range []string{"uint24", "int24", "uint24", "bytes24"} {
+		abi.IsDynamic(typeStr, false)
+	}
+
+	stats := abi.TypeCacheStats()
+	fmt.Println(stats.Len, stats.Size, stats.Evictions > 0)
+
+	// Output:
+	// miss uint24
+	// miss int24
+	// hit uint24
+	// miss bytes24
+	// eviction int24
+	// 2 2 true
+}
@@ -0,0 +1,40 @@
+package abi
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DecodeCalldata decodes the call data of a transaction (i.e. tx.Data())
+// by resolving its selector in given registry. It returns the name of
+// the called method and its decoded arguments, which can be parsed into
+// a struct with Parse.
+func DecodeCalldata(data []byte, registry *Registry) (string, []any, error) {
+	fragment, args, err := decodeCalldata(data, registry)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fragment.Name, args, nil
+}
+
+// decodeCalldata resolves the selector of given call data and decodes
+// its arguments.
+func decodeCalldata(data []byte, registry *Registry) (*Fragment, []any, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("call data is too short to contain a selector. Length: %d", len(data))
+	}
+
+	fragment, ok := registry.Lookup([4]byte(data[:4]))
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown selector: 0x%s", common.Bytes2Hex(data[:4]))
+	}
+
+	args, err := Decode(fragment.InputTypes(), data[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding %s: %w", fragment.Signature(), err)
+	}
+
+	return fragment, args, nil
+}
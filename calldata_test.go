@@ -0,0 +1,58 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleDecodeCalldata() {
+	registry, err := abi.NewRegistry(
+		"function transfer(address to, uint256 amount) returns (bool)",
+		"function approve(address spender, uint256 amount) returns (bool)",
+		"transferFrom(address,address,uint256)",
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	data := common.Hex2Bytes("a9059cbb0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d278900000000000000000000000000000000000000000000000000000000000003e8")
+
+	method, args, err := abi.DecodeCalldata(data, registry)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var transfer struct {
+		To     common.Address
+		Amount *big.Int
+	}
+	err = abi.Parse(args, &transfer)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(method, transfer.To, transfer.Amount)
+
+	_, _, err = abi.DecodeCalldata(common.Hex2Bytes("deadbeef"), registry)
+	fmt.Println(err)
+
+	// Output:
+	// transfer 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1000
+	// unknown selector: 0xdeadbeef
+}
+
+func ExampleDecodeCalldata_nilRegistry() {
+	data := common.Hex2Bytes("a9059cbb0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d278900000000000000000000000000000000000000000000000000000000000003e8")
+
+	var registry *abi.Registry
+	_, _, err := abi.DecodeCalldata(data, registry)
+	fmt.Println(err)
+	fmt.Println(registry.Signatures())
+
+	// Output:
+	// unknown selector: 0xa9059cbb
+	// []
+}
@@ -0,0 +1,84 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Canonicalize re-encodes ABI encoded data in its canonical form, as
+// produced by Encode: dirty high bytes of addresses, non-standard
+// offsets (i.e. pointing past unused bytes or sharing tails) and bytes
+// trailing the encoded values are stripped. Data encoded canonically is
+// returned unchanged, as Encode(Decode(data)) is data for canonical
+// inputs. Values not fitting their type (i.e. a uint8 word above 255, an
+// int8 word which is not sign extended, or a bytes4 word with non-zero
+// trailing bytes) are rejected, as their meaning is ambiguous.
+//
+// The signature is either a function or error signature (i.e.
+// `transfer(address,uint256)` or `function transfer(address to, uint256
+// amount)`), in which case data must start with its selector, which is
+// kept, or a bare list of parameters (i.e. `(address,uint256)`).
+func Canonicalize(data []byte, sig string) ([]byte, error) {
+	selector, components, err := canonicalLayout(sig)
+	if err != nil {
+		return nil, fmt.Errorf("[Canonicalize] %w", err)
+	}
+
+	if len(data) < len(selector) {
+		return nil, fmt.Errorf("[Canonicalize] data too short to contain a selector")
+	}
+	if !bytes.Equal(data[:len(selector)], selector) {
+		return nil, fmt.Errorf("[Canonicalize] invalid selector")
+	}
+
+	typeStrs := make([]string, len(components))
+	for i, component := range components {
+		typeStrs[i] = component.CanonicalType()
+	}
+
+	decoded, err := Decode(typeStrs, data[len(selector):])
+	if err != nil {
+		return nil, fmt.Errorf("[Canonicalize] error decoding data: %w", err)
+	}
+
+	encoded, err := Encode(typeStrs, decoded...)
+	if err != nil {
+		return nil, fmt.Errorf("[Canonicalize] error re-encoding data: %w", err)
+	}
+
+	return append(append([]byte{}, selector...), encoded...), nil
+}
+
+// IsCanonical checks whether data is ABI encoded in its canonical form
+// for given signature (see Canonicalize), i.e. before verifying that
+// hashed call data matches its decoded values. Data which can't be
+// decoded returns an error.
+func IsCanonical(data []byte, sig string) (bool, error) {
+	canonical, err := Canonicalize(data, sig)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(canonical, data), nil
+}
+
+// canonicalLayout returns the selector (nil for bare lists of
+// parameters) and parameters of the signature given to Canonicalize.
+func canonicalLayout(sig string) ([]byte, []Component, error) {
+	sig = strings.TrimSpace(sig)
+	if strings.HasPrefix(sig, "(") && matchingParenthesis(sig, 0) == len(sig)-1 {
+		components, err := parseParams(sig[1 : len(sig)-1])
+		return nil, components, err
+	}
+
+	fragment, err := ParseFragment(sig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fragment.Type != "function" && fragment.Type != "error" {
+		return nil, nil, fmt.Errorf("expected a function or error signature, got %s", fragment.Type)
+	}
+
+	return fragment.Selector(), fragment.Inputs, nil
+}
@@ -0,0 +1,94 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleCanonicalize() {
+	sig := "function transfer(address to, uint256 amount)"
+	to := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	calldata, err := abi.EncodeWithSignature("transfer(address,uint256)", to, big.NewInt(1000))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Dirty the high bytes of the address and append garbage, which
+	// decoders ignore although the hash of the call data changes.
+	tampered := append([]byte{}, calldata...)
+	tampered[4] = 0xff
+	tampered = append(tampered, 0xde, 0xad)
+
+	decoded, err := abi.DecodeWithSignature("transfer(address,uint256)", tampered)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(decoded)
+
+	canonical, err := abi.IsCanonical(tampered, sig)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(canonical)
+
+	stripped, err := abi.Canonicalize(tampered, sig)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(common.Bytes2Hex(stripped) == common.Bytes2Hex(calldata))
+
+	// Output:
+	// [0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1000]
+	// false
+	// true
+}
+
+func ExampleIsCanonical() {
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	price, _ := new(big.Float).SetString("-1.25")
+	typeStrs := []string{"address", "int8", "bool", "bytes4", "bytes", "string", "uint256[]", "(address,bytes)[2]", "(string,int64[])", "fixed128x18", "bytes3[]"}
+	values := []any{
+		owner,
+		big.NewInt(-3),
+		true,
+		[]byte{1, 2, 3, 4},
+		[]byte("data"),
+		"text",
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[]any{[]any{owner, []byte{1}}, []any{owner, []byte{}}},
+		[]any{"tuple", []*big.Int{big.NewInt(-1), big.NewInt(7)}},
+		price,
+		[][]byte{{1, 2, 3}},
+	}
+
+	// Data produced by Encode is canonical: decoding then re-encoding it
+	// gives back the same bytes.
+	for i, typeStr := range typeStrs {
+		encoded, err := abi.Encode([]string{typeStr}, values[i])
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		canonical, err := abi.IsCanonical(encoded, "("+typeStr+")")
+		if err != nil {
+			fmt.Println(err)
+		}
+		fmt.Println(typeStr, canonical)
+	}
+
+	// Output:
+	// address true
+	// int8 true
+	// bool true
+	// bytes4 true
+	// bytes true
+	// string true
+	// uint256[] true
+	// (address,bytes)[2] true
+	// (string,int64[]) true
+	// fixed128x18 true
+	// bytes3[] true
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+// RevertError is returned when a call reverts with revert data. Name
+// and Args hold the decoded error when it is a built-in Error(string)
+// or Panic(uint256) error, otherwise Data can be decoded with
+// abi.ParseRevert given the custom errors of the contract.
+type RevertError struct {
+	Name string
+	Args []any
+	Data []byte
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *RevertError) Error() string {
+	switch {
+	case e.Name == "Error" && len(e.Args) == 1:
+		return fmt.Sprintf("execution reverted: %v", e.Args[0])
+	case e.Name != "":
+		return fmt.Sprintf("execution reverted: %s%v", e.Name, e.Args)
+	default:
+		return fmt.Sprintf("execution reverted: 0x%s", common.Bytes2Hex(e.Data))
+	}
+}
+
+// Unwrap returns the error returned by the node.
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// dataError is implemented by the errors of the rpc package holding
+// revert data.
+type dataError interface {
+	ErrorData() interface{}
+}
+
+// CallAndParse calls the function with given human-readable signature
+// (i.e. `function balanceOf(address owner) view returns (uint256 balance)`)
+// on the contract at given address with an eth_call at the latest
+// block, and parses its return values into the struct pointed by out.
+// Fields tagged with `abi:"name"` are mapped to the output with the
+// same name. Calls reverting with revert data fail with a *RevertError.
+func CallAndParse(ctx context.Context, caller ethereum.ContractCaller, to common.Address, sig string, args []any, out any) error {
+	method, err := abi.ParseFragment(sig)
+	if err != nil {
+		return err
+	}
+
+	contract, err := abi.NewContract(method)
+	if err != nil {
+		return err
+	}
+
+	callData, err := contract.EncodeCall(method.Signature(), args...)
+	if err != nil {
+		return err
+	}
+
+	data, err := caller.CallContract(ctx, ethereum.CallMsg{To: &to, Data: callData}, nil)
+	if err != nil {
+		return revertError(err)
+	}
+
+	return contract.DecodeReturn(method.Signature(), data, out)
+}
+
+// revertError converts the error of a reverted call into a *RevertError
+// when it holds revert data.
+func revertError(err error) error {
+	var dataErr dataError
+	if !errors.As(err, &dataErr) {
+		return err
+	}
+
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok || !strings.HasPrefix(hexData, "0x") {
+		return err
+	}
+
+	revertErr := &RevertError{Data: common.FromHex(hexData), Err: err}
+	name, args, decodeErr := abi.ParseRevert(revertErr.Data)
+	if decodeErr == nil {
+		revertErr.Name = name
+		revertErr.Args = args
+	}
+
+	return revertErr
+}
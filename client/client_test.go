@@ -0,0 +1,65 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+	"github.com/omnes-tech/abi/client"
+)
+
+// fakeCaller replies to eth_call like a node would, returning given
+// data or reverting with given revert data.
+type fakeCaller struct {
+	data   []byte
+	revert []byte
+}
+
+type rpcError struct {
+	data string
+}
+
+func (e rpcError) Error() string          { return "execution reverted" }
+func (e rpcError) ErrorData() interface{} { return e.data }
+
+func (c fakeCaller) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if c.revert != nil {
+		return nil, rpcError{data: "0x" + common.Bytes2Hex(c.revert)}
+	}
+	return c.data, nil
+}
+
+func ExampleCallAndParse() {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	sig := "function balanceOf(address owner) view returns (uint256 balance)"
+
+	// A connected *ethclient.Client can be used as caller.
+	data, _ := abi.Encode([]string{"uint256"}, big.NewInt(1000))
+	caller := fakeCaller{data: data}
+
+	var result struct {
+		Balance *big.Int `abi:"balance"`
+	}
+	err := client.CallAndParse(context.Background(), caller, token, sig, []any{owner}, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(result.Balance)
+
+	revertData, _ := abi.EncodeWithSignature("Error(string)", "not allowed")
+	err = client.CallAndParse(context.Background(), fakeCaller{revert: revertData}, token, sig, []any{owner}, &result)
+	fmt.Println(err)
+
+	var revertErr *client.RevertError
+	fmt.Println(errors.As(err, &revertErr), revertErr.Name)
+
+	// Output:
+	// 1000
+	// execution reverted: not allowed
+	// true Error
+}
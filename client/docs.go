@@ -0,0 +1,5 @@
+// Package client connects the abi package to Ethereum nodes, encoding
+// contract calls, performing them through an ethclient.Client (or any
+// ethereum.ContractCaller) and parsing their results or revert reasons,
+// and fetching event logs over large block ranges in chunks.
+package client
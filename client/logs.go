@@ -0,0 +1,339 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/omnes-tech/abi"
+)
+
+// DefaultChunkSize is the number of blocks queried per eth_getLogs call
+// unless set in FilterOptions.
+const DefaultChunkSize = 2000
+
+// DefaultRetries is the number of times a query failing with an error
+// other than a range error is retried unless set in FilterOptions.
+const DefaultRetries = 3
+
+// rangeErrorMessages are parts of the error messages returned by
+// providers rejecting eth_getLogs queries spanning too many blocks or
+// returning too many logs.
+var rangeErrorMessages = []string{
+	"block range",        // i.e. "block range is too wide", "exceed maximum block range"
+	"returned more than", // i.e. "query returned more than 10000 results"
+	"too many",           // i.e. "too many results", "too many blocks"
+	"range too large",    // i.e. "eth_getLogs range too large"
+	"response size",      // i.e. "Log response size exceeded"
+	"limit exceeded",     // i.e. "logs limit exceeded"
+}
+
+// LogReader fetches logs and the current block number, as done by an
+// ethclient.Client.
+type LogReader interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	ethereum.BlockNumberReader
+}
+
+// FilterOptions controls the behavior of FilterAndParse.
+type FilterOptions struct {
+	// ChunkSize is the number of blocks queried per eth_getLogs call. It
+	// defaults to DefaultChunkSize when zero.
+	ChunkSize uint64
+
+	// ParseOptions are the options used to parse each log. Components
+	// are always set to the event parameters.
+	ParseOptions abi.Options
+
+	// IsRangeError reports whether a FilterLogs error rejects the block
+	// range or the number of logs of the query, in which case the range
+	// is halved. It defaults to IsRangeError when nil.
+	IsRangeError func(err error) bool
+
+	// Retries is the number of times a query failing with another error
+	// (i.e. a network error) is retried with the same range, waiting
+	// RetryDelay between attempts. It defaults to DefaultRetries when
+	// zero, and disables retries when negative.
+	Retries    int
+	RetryDelay time.Duration
+}
+
+// IsRangeError reports whether a FilterLogs error is returned by a
+// provider rejecting a query spanning too many blocks or returning too
+// many logs, matching the messages of common providers.
+func IsRangeError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, part := range rangeErrorMessages {
+		if strings.Contains(msg, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterAndParse fetches the logs matching given query with eth_getLogs
+// and parses each of them into a new target created with makeTarget
+// (i.e. `func() any { return new(Transfer) }`), returning an iterator
+// over the parsed logs in chain order. The event signature must be
+// human-readable with its indexed parameters marked, i.e.
+// `event Transfer(address indexed from, address indexed to, uint256 value)`.
+// Queries without topics are filtered by the event topic.
+//
+// The block range of the query is split into chunks of
+// FilterOptions.ChunkSize blocks, fetched as the iteration goes. As
+// providers reject ranges returning too many logs, queries spanning
+// several blocks failing with a range error (see
+// FilterOptions.IsRangeError) are retried with halved ranges, which are
+// kept for the following chunks. Queries failing with other errors are
+// retried as set by FilterOptions.Retries without changing the range. A
+// query without ToBlock ends at the current block, and a query with a
+// BlockHash is fetched at once.
+//
+// The iteration can be resumed after a failure or a restart by querying
+// from the block returned by LogIterator.Checkpoint.
+func FilterAndParse(ctx context.Context, reader LogReader, query ethereum.FilterQuery, eventSig string, makeTarget func() any, opts FilterOptions) (*LogIterator, error) {
+	event, err := abi.ParseFragment(eventSig)
+	if err != nil {
+		return nil, err
+	}
+	if event.Type != "event" {
+		return nil, fmt.Errorf("%s is not an event", eventSig)
+	}
+
+	if len(query.Topics) == 0 && !event.Anonymous {
+		query.Topics = [][]common.Hash{{event.Topic()}}
+	}
+
+	it := &LogIterator{
+		ctx:        ctx,
+		reader:     reader,
+		query:      query,
+		event:      event,
+		makeTarget: makeTarget,
+		chunkSize:  opts.ChunkSize,
+		parseOpts:  opts.ParseOptions,
+		isRangeErr: opts.IsRangeError,
+		retries:    opts.Retries,
+		retryDelay: opts.RetryDelay,
+	}
+	it.parseOpts.Components = event.Inputs
+	if it.chunkSize == 0 {
+		it.chunkSize = DefaultChunkSize
+	}
+	if it.isRangeErr == nil {
+		it.isRangeErr = IsRangeError
+	}
+	if it.retries == 0 {
+		it.retries = DefaultRetries
+	}
+
+	if query.BlockHash != nil {
+		return it, nil
+	}
+
+	if query.FromBlock != nil {
+		if !query.FromBlock.IsUint64() {
+			return nil, fmt.Errorf("invalid from block %s", query.FromBlock)
+		}
+		it.next = query.FromBlock.Uint64()
+	}
+	if query.ToBlock != nil {
+		if !query.ToBlock.IsUint64() {
+			return nil, fmt.Errorf("invalid to block %s", query.ToBlock)
+		}
+		it.last = query.ToBlock.Uint64()
+	} else {
+		it.last, err = reader.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching current block number: %w", err)
+		}
+	}
+
+	return it, nil
+}
+
+// LogIterator iterates over the logs fetched and parsed by
+// FilterAndParse. Logs are read by calling Next until it returns false,
+// then Err reports the error that stopped the iteration, if any:
+//
+//	for it.Next() {
+//		transfer := it.Value().(*Transfer)
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type LogIterator struct {
+	ctx        context.Context
+	reader     LogReader
+	query      ethereum.FilterQuery
+	event      *abi.Fragment
+	makeTarget func() any
+	chunkSize  uint64
+	parseOpts  abi.Options
+	isRangeErr func(err error) bool
+	retries    int
+	retryDelay time.Duration
+
+	next    uint64 // first block not fetched yet
+	last    uint64 // last block of the query
+	fetched bool   // set once the logs of a BlockHash query are fetched
+
+	pending []types.Log // fetched logs not returned yet
+	log     types.Log
+	value   any
+	err     error
+}
+
+// Next fetches, when needed, and parses the next log, returning false
+// when there are no more logs or an error occurred.
+func (it *LogIterator) Next() bool {
+	it.value = nil
+	if it.err != nil {
+		return false
+	}
+
+	for len(it.pending) == 0 {
+		if it.done() {
+			return false
+		}
+		it.err = it.fetch()
+		if it.err != nil {
+			return false
+		}
+	}
+
+	log := it.pending[0]
+	decoded, err := abi.DecodeLog(log, it.event)
+	if err == nil {
+		target := it.makeTarget()
+		err = abi.ParseWithOptionsCtx(it.ctx, decoded, target, it.parseOpts)
+		it.value = target
+	}
+	if err != nil {
+		it.value = nil
+		it.err = fmt.Errorf("error parsing log %d of block %d: %w", log.Index, log.BlockNumber, err)
+		return false
+	}
+	it.log = log
+	it.pending = it.pending[1:]
+
+	return true
+}
+
+// Log returns the raw log parsed by the last call to Next.
+func (it *LogIterator) Log() types.Log {
+	return it.log
+}
+
+// Value returns the target holding the log parsed by the last call to
+// Next, as created by makeTarget.
+func (it *LogIterator) Value() any {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *LogIterator) Err() error {
+	return it.err
+}
+
+// Checkpoint returns the block the iteration can be resumed from, all
+// logs of the blocks before it having been returned by Next. Resuming
+// from a block whose logs were partly returned returns them again.
+func (it *LogIterator) Checkpoint() uint64 {
+	if len(it.pending) > 0 {
+		return it.pending[0].BlockNumber
+	}
+
+	return it.next
+}
+
+// done checks whether all logs of the query have been fetched.
+func (it *LogIterator) done() bool {
+	if it.query.BlockHash != nil {
+		return it.fetched
+	}
+
+	return it.next > it.last
+}
+
+// fetch fetches the logs of the next chunk of blocks, halving the chunk
+// size while the query fails with a range error.
+func (it *LogIterator) fetch() error {
+	query := it.query
+	if query.BlockHash != nil {
+		logs, err := it.filterLogs(query, false)
+		if err != nil {
+			return fmt.Errorf("error fetching logs of block %s: %w", query.BlockHash.Hex(), err)
+		}
+		it.pending, it.fetched = logs, true
+		return nil
+	}
+
+	for {
+		to := it.next + it.chunkSize - 1
+		if to > it.last || to < it.next {
+			to = it.last
+		}
+		query.FromBlock = new(big.Int).SetUint64(it.next)
+		query.ToBlock = new(big.Int).SetUint64(to)
+
+		logs, err := it.filterLogs(query, to > it.next)
+		if errors.Is(err, errRange) {
+			it.chunkSize = (to - it.next + 1) / 2
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error fetching logs of blocks %d to %d: %w", it.next, to, err)
+		}
+
+		it.pending, it.next = logs, to+1
+		return nil
+	}
+}
+
+// errRange is returned by filterLogs when a query fails with a range
+// error and its range can be halved.
+var errRange = errors.New("range error")
+
+// filterLogs performs a query, retrying it on errors other than range
+// errors. It returns errRange on range errors when canHalve is set.
+func (it *LogIterator) filterLogs(query ethereum.FilterQuery, canHalve bool) ([]types.Log, error) {
+	for attempt := 0; ; attempt++ {
+		if err := it.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		logs, err := it.reader.FilterLogs(it.ctx, query)
+		switch {
+		case err == nil:
+			return logs, nil
+		case it.ctx.Err() != nil:
+			return nil, err
+		case it.isRangeErr(err):
+			if canHalve {
+				return nil, errRange
+			}
+			return nil, err
+		case attempt >= it.retries:
+			return nil, err
+		}
+
+		if it.retryDelay > 0 {
+			timer := time.NewTimer(it.retryDelay)
+			select {
+			case <-it.ctx.Done():
+				timer.Stop()
+				return nil, it.ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/omnes-tech/abi"
+	"github.com/omnes-tech/abi/client"
+)
+
+// fakeLogReader replies to eth_getLogs like a provider limiting queries
+// to given number of blocks would.
+type fakeLogReader struct {
+	logs      []types.Log
+	head      uint64
+	maxBlocks uint64
+}
+
+func (r fakeLogReader) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	if to-from+1 > r.maxBlocks {
+		return nil, errors.New("query exceeds max block range")
+	}
+	fmt.Printf("eth_getLogs %d-%d\n", from, to)
+
+	var logs []types.Log
+	for _, log := range r.logs {
+		if log.BlockNumber >= from && log.BlockNumber <= to && log.Topics[0] == q.Topics[0][0] {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (r fakeLogReader) BlockNumber(context.Context) (uint64, error) {
+	return r.head, nil
+}
+
+// flakyLogReader fails the first given number of eth_getLogs calls
+// with a network error.
+type flakyLogReader struct {
+	fakeLogReader
+	failures *int
+}
+
+func (r flakyLogReader) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if *r.failures > 0 {
+		*r.failures--
+		fmt.Printf("eth_getLogs %d-%d: connection reset\n", q.FromBlock, q.ToBlock)
+		return nil, errors.New("read tcp: connection reset by peer")
+	}
+	return r.fakeLogReader.FilterLogs(ctx, q)
+}
+
+type transfer struct {
+	From  common.Address `abi:"from"`
+	To    common.Address `abi:"to"`
+	Value *big.Int       `abi:"value"`
+}
+
+func ExampleFilterAndParse() {
+	eventSig := "event Transfer(address indexed from, address indexed to, uint256 value)"
+	event := abi.MustParseFragment(eventSig)
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+	var logs []types.Log
+	for i, block := range []uint64{1002, 1150, 1999} {
+		data, _ := abi.Encode([]string{"uint256"}, big.NewInt(int64(i+1)*100))
+		logs = append(logs, types.Log{
+			BlockNumber: block,
+			Topics:      []common.Hash{event.Topic(), common.BytesToHash(owner.Bytes()), {}},
+			Data:        data,
+		})
+	}
+
+	// A connected *ethclient.Client can be used as reader. Ranges are
+	// halved until the provider accepts them.
+	reader := fakeLogReader{logs: logs, head: 2100, maxBlocks: 500}
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(1000)}
+	it, err := client.FilterAndParse(context.Background(), reader, query, eventSig, func() any { return new(transfer) }, client.FilterOptions{})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	for it.Next() {
+		t := it.Value().(*transfer)
+		fmt.Println(it.Log().BlockNumber, t.From, t.Value)
+	}
+	if err := it.Err(); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(it.Checkpoint())
+
+	// Output:
+	// eth_getLogs 1000-1274
+	// 1002 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 100
+	// 1150 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 200
+	// eth_getLogs 1275-1549
+	// eth_getLogs 1550-1824
+	// eth_getLogs 1825-2099
+	// 1999 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 300
+	// eth_getLogs 2100-2100
+	// 2101
+}
+
+func ExampleFilterAndParse_retries() {
+	eventSig := "event Transfer(address indexed from, address indexed to, uint256 value)"
+	newTransfer := func() any { return new(transfer) }
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(1000), ToBlock: big.NewInt(2999)}
+
+	// Network errors are retried without halving the range.
+	failures := 2
+	reader := flakyLogReader{fakeLogReader{head: 3000, maxBlocks: 1000}, &failures}
+	it, err := client.FilterAndParse(context.Background(), reader, query, eventSig, newTransfer, client.FilterOptions{ChunkSize: 1000})
+	if err != nil {
+		fmt.Println(err)
+	}
+	for it.Next() {
+	}
+	fmt.Println(it.Err())
+
+	// The iteration fails once retries are exhausted.
+	failures = 2
+	it, err = client.FilterAndParse(context.Background(), reader, query, eventSig, newTransfer, client.FilterOptions{ChunkSize: 1000, Retries: 1})
+	if err != nil {
+		fmt.Println(err)
+	}
+	for it.Next() {
+	}
+	fmt.Println(it.Err())
+	fmt.Println(it.Checkpoint())
+
+	// Output:
+	// eth_getLogs 1000-1999: connection reset
+	// eth_getLogs 1000-1999: connection reset
+	// eth_getLogs 1000-1999
+	// eth_getLogs 2000-2999
+	// <nil>
+	// eth_getLogs 1000-1999: connection reset
+	// eth_getLogs 1000-1999: connection reset
+	// error fetching logs of blocks 1000 to 1999: read tcp: connection reset by peer
+	// 1000
+}
+
+func ExampleIsRangeError() {
+	for _, msg := range []string{
+		"query returned more than 10000 results",
+		"exceed maximum block range: 5000",
+		"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range",
+		"read tcp: connection reset by peer",
+		"context deadline exceeded",
+	} {
+		fmt.Println(client.IsRangeError(errors.New(msg)))
+	}
+
+	// Output:
+	// true
+	// true
+	// true
+	// false
+	// false
+}
@@ -0,0 +1,622 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/omnes-tech/abi"
+)
+
+// config holds the generator options.
+type config struct {
+	pkg     string
+	helpers bool
+}
+
+// nodeKind identifies how a Go type is encoded and decoded.
+type nodeKind int
+
+const (
+	kindAddress nodeKind = iota
+	kindAs
+	kindFixedBytes
+	kindSlice
+	kindArray
+	kindTuple
+)
+
+// typeNode describes the Go type bound to an ABI type.
+type typeNode struct {
+	kind   nodeKind
+	goType string
+	size   int
+	elem   *typeNode
+	fields []fieldNode
+}
+
+// fieldNode is a named member of a tuple node.
+type fieldNode struct {
+	name string
+	node *typeNode
+}
+
+// generator accumulates the generated declarations.
+type generator struct {
+	structs     map[string]string
+	structDecls bytes.Buffer
+	decls       bytes.Buffer
+	tmp         int
+}
+
+// generate returns the formatted Go source of the bindings of given
+// contract.
+func generate(contract *abi.Contract, cfg config) ([]byte, error) {
+	g := &generator{structs: make(map[string]string)}
+
+	methodNames := goNames(contract.Methods)
+	for i, method := range contract.Methods {
+		err := g.genMethod(methodNames[i], method)
+		if err != nil {
+			return nil, fmt.Errorf("error generating function %s: %w", method.Signature(), err)
+		}
+	}
+
+	eventNames := goNames(contract.Events)
+	for i, event := range contract.Events {
+		err := g.genEvent(eventNames[i], event)
+		if err != nil {
+			return nil, fmt.Errorf("error generating event %s: %w", event.Signature(), err)
+		}
+	}
+
+	errorNames := goNames(contract.Errors)
+	for i, errorFragment := range contract.Errors {
+		err := g.genError(errorNames[i], errorFragment)
+		if err != nil {
+			return nil, fmt.Errorf("error generating error %s: %w", errorFragment.Signature(), err)
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by abigen-lite. DO NOT EDIT.\n\npackage %s\n\n", cfg.pkg)
+	out.WriteString(`import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/omnes-tech/abi"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = bytes.Equal
+	_ = fmt.Errorf
+	_ = big.NewInt
+	_ = common.HexToAddress
+	_ = types.Log{}
+	_ = abi.Decode
+)
+
+`)
+	out.Write(g.structDecls.Bytes())
+	out.Write(g.decls.Bytes())
+	if cfg.helpers {
+		out.WriteString(helpersSource)
+	}
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting generated code: %w", err)
+	}
+
+	return formatted, nil
+}
+
+// genMethod generates the input and output structs of a function.
+func (g *generator) genMethod(name string, method *abi.Fragment) error {
+	varName := lowerFirst(name)
+	signature := method.Signature()
+
+	inputs, err := g.fieldNodes(method.Inputs, name+"Input", false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&g.decls, "var %sSelector = %s\n\n", varName, byteSliceLiteral(method.Selector()))
+	fmt.Fprintf(&g.decls, "var %sInputTypes = %s\n\n", varName, stringSliceLiteral(method.InputTypes()))
+
+	g.writeStruct(name+"Input", fmt.Sprintf("holds the arguments of `%s`.", signature), inputs)
+	g.writeEncode(name+"Input", "call data of `"+signature+"`", varName+"InputTypes", varName+"Selector", inputs)
+	g.writeDecode(name+"Input", "call data of `"+signature+"`", varName+"InputTypes", varName+"Selector", inputs)
+
+	if len(method.Outputs) == 0 {
+		return nil
+	}
+
+	outputs, err := g.fieldNodes(method.Outputs, name+"Output", false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&g.decls, "var %sOutputTypes = %s\n\n", varName, stringSliceLiteral(method.OutputTypes()))
+
+	g.writeStruct(name+"Output", fmt.Sprintf("holds the return values of `%s`.", signature), outputs)
+	g.writeEncode(name+"Output", "return data of `"+signature+"`", varName+"OutputTypes", "", outputs)
+	g.writeDecode(name+"Output", "return data of `"+signature+"`", varName+"OutputTypes", "", outputs)
+
+	return nil
+}
+
+// genEvent generates the struct of an event and its DecodeLog method.
+func (g *generator) genEvent(name string, event *abi.Fragment) error {
+	typeName := name + "Event"
+	varName := lowerFirst(typeName)
+
+	fields, err := g.fieldNodes(event.Inputs, typeName, true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&g.decls, "var %s = abi.MustParseFragment(%q)\n\n", varName, event.String())
+	g.writeStruct(typeName, fmt.Sprintf("holds the parameters of the `%s` event.", event.Signature()), fields)
+
+	fmt.Fprintf(&g.decls, "// DecodeLog decodes given `%s` event log.\n", event.Signature())
+	fmt.Fprintf(&g.decls, "func (x *%s) DecodeLog(log types.Log) error {\n", typeName)
+	fmt.Fprintf(&g.decls, "%s, err := abi.DecodeLog(log, %s)\n", decodedName(fields), varName)
+	g.decls.WriteString("if err != nil {\nreturn err\n}\n")
+	g.writeFieldsDecode(typeName, fields)
+	g.decls.WriteString("return nil\n}\n\n")
+
+	return nil
+}
+
+// genError generates the struct of a custom error and its Encode and
+// Decode methods.
+func (g *generator) genError(name string, errorFragment *abi.Fragment) error {
+	typeName := name + "Error"
+	varName := lowerFirst(typeName)
+	signature := errorFragment.Signature()
+
+	fields, err := g.fieldNodes(errorFragment.Inputs, typeName, false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&g.decls, "var %sSelector = %s\n\n", varName, byteSliceLiteral(errorFragment.Selector()))
+	fmt.Fprintf(&g.decls, "var %sTypes = %s\n\n", varName, stringSliceLiteral(errorFragment.InputTypes()))
+
+	g.writeStruct(typeName, fmt.Sprintf("holds the arguments of the `%s` error.", signature), fields)
+	g.writeEncode(typeName, "revert data of `"+signature+"`", varName+"Types", varName+"Selector", fields)
+	g.writeDecode(typeName, "revert data of `"+signature+"`", varName+"Types", varName+"Selector", fields)
+
+	return nil
+}
+
+// writeStruct writes the declaration of a struct with given fields.
+func (g *generator) writeStruct(typeName string, doc string, fields []fieldNode) {
+	fmt.Fprintf(&g.decls, "// %s %s\n", typeName, doc)
+	writeStructBody(&g.decls, typeName, fields)
+}
+
+// writeStructBody writes a struct type declaration to w.
+func writeStructBody(w *bytes.Buffer, typeName string, fields []fieldNode) {
+	fmt.Fprintf(w, "type %s struct {\n", typeName)
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s %s\n", field.name, field.node.goType)
+	}
+	w.WriteString("}\n\n")
+}
+
+// writeEncode writes the Encode method of a struct. When selectorVar is
+// not empty, the encoded values are prefixed with the selector.
+func (g *generator) writeEncode(typeName string, what string, typesVar string, selectorVar string, fields []fieldNode) {
+	var body bytes.Buffer
+	exprs := make([]string, len(fields))
+	for i, field := range fields {
+		exprs[i] = g.encodeExpr(&body, "x."+field.name, field.node)
+	}
+
+	args := typesVar
+	if len(exprs) > 0 {
+		args += ", " + strings.Join(exprs, ", ")
+	}
+
+	fmt.Fprintf(&g.decls, "// Encode encodes the %s.\n", what)
+	fmt.Fprintf(&g.decls, "func (x *%s) Encode() ([]byte, error) {\n", typeName)
+	g.decls.Write(body.Bytes())
+	fmt.Fprintf(&g.decls, "encoded, err := abi.Encode(%s)\n", args)
+	g.decls.WriteString("if err != nil {\nreturn nil, err\n}\n")
+	if selectorVar == "" {
+		g.decls.WriteString("return encoded, nil\n}\n\n")
+	} else {
+		fmt.Fprintf(&g.decls, "return append(append([]byte{}, %s...), encoded...), nil\n}\n\n", selectorVar)
+	}
+}
+
+// writeDecode writes the Decode method of a struct. When selectorVar is
+// not empty, the data must start with the selector.
+func (g *generator) writeDecode(typeName string, what string, typesVar string, selectorVar string, fields []fieldNode) {
+	fmt.Fprintf(&g.decls, "// Decode decodes the %s.\n", what)
+	fmt.Fprintf(&g.decls, "func (x *%s) Decode(data []byte) error {\n", typeName)
+	if selectorVar != "" {
+		fmt.Fprintf(&g.decls, "if len(data) < 4 || !bytes.Equal(data[:4], %s) {\n", selectorVar)
+		fmt.Fprintf(&g.decls, "return fmt.Errorf(\"invalid selector for %s\")\n}\n", typeName)
+		g.decls.WriteString("data = data[4:]\n")
+	}
+	fmt.Fprintf(&g.decls, "%s, err := abi.Decode(%s, data)\n", decodedName(fields), typesVar)
+	g.decls.WriteString("if err != nil {\nreturn err\n}\n")
+	g.writeFieldsDecode(typeName, fields)
+	g.decls.WriteString("return nil\n}\n\n")
+}
+
+// writeFieldsDecode writes the statements assigning the decoded values
+// to the struct fields.
+func (g *generator) writeFieldsDecode(typeName string, fields []fieldNode) {
+	if len(fields) == 0 {
+		return
+	}
+
+	fmt.Fprintf(&g.decls, "if len(decoded) != %d {\n", len(fields))
+	fmt.Fprintf(&g.decls, "return fmt.Errorf(\"expected %d decoded values, got %%d\", len(decoded))\n}\n", len(fields))
+	for i, field := range fields {
+		g.decodeStmts(&g.decls, "x."+field.name, fmt.Sprintf("decoded[%d]", i), field.node, typeName+"."+field.name, nil)
+	}
+}
+
+// encodeExpr writes the statements needed to convert the Go value src
+// into the value expected by abi.Encode and returns its expression.
+func (g *generator) encodeExpr(w *bytes.Buffer, src string, node *typeNode) string {
+	switch node.kind {
+	case kindAddress:
+		return "&" + src
+	case kindFixedBytes:
+		return src + "[:]"
+	case kindSlice, kindArray:
+		values := g.tmpName("v")
+		index := g.tmpName("i")
+		fmt.Fprintf(w, "%s := make([]any, len(%s))\n", values, src)
+		fmt.Fprintf(w, "for %s := range %s {\n", index, src)
+		elem := g.encodeExpr(w, src+"["+index+"]", node.elem)
+		fmt.Fprintf(w, "%s[%s] = %s\n}\n", values, index, elem)
+		return values
+	case kindTuple:
+		exprs := make([]string, len(node.fields))
+		for i, field := range node.fields {
+			exprs[i] = g.encodeExpr(w, src+"."+field.name, field.node)
+		}
+		values := g.tmpName("t")
+		fmt.Fprintf(w, "%s := []any{%s}\n", values, strings.Join(exprs, ", "))
+		return values
+	default:
+		return src
+	}
+}
+
+// decodeStmts writes the statements converting the decoded value src
+// into the Go value dst. The path and its index arguments are used in
+// error messages.
+func (g *generator) decodeStmts(w *bytes.Buffer, dst string, src string, node *typeNode, path string, pathArgs []string) {
+	errCheck := func() {
+		args := strings.Join(append(append([]string{}, pathArgs...), "err"), ", ")
+		fmt.Fprintf(w, "if err != nil {\nreturn fmt.Errorf(\"%s: %%w\", %s)\n}\n", path, args)
+	}
+
+	switch node.kind {
+	case kindAddress:
+		fmt.Fprintf(w, "%s, err = abigenAddress(%s)\n", dst, src)
+		errCheck()
+	case kindAs:
+		fmt.Fprintf(w, "%s, err = abigenAs[%s](%s)\n", dst, node.goType, src)
+		errCheck()
+	case kindFixedBytes:
+		fmt.Fprintf(w, "err = abigenFixedBytes(%s, %s[:])\n", src, dst)
+		errCheck()
+	case kindSlice, kindArray:
+		list := g.tmpName("l")
+		index := g.tmpName("i")
+		size := node.size
+		if node.kind == kindSlice {
+			size = -1
+		}
+		fmt.Fprintf(w, "%s, err := abigenList(%s, %d)\n", list, src, size)
+		errCheck()
+		if node.kind == kindSlice {
+			fmt.Fprintf(w, "%s = make(%s, len(%s))\n", dst, node.goType, list)
+		}
+		fmt.Fprintf(w, "for %s := range %s {\n", index, list)
+		g.decodeStmts(w, dst+"["+index+"]", list+"["+index+"]", node.elem, path+"[%d]", append(append([]string{}, pathArgs...), index))
+		w.WriteString("}\n")
+	case kindTuple:
+		tuple := g.tmpName("t")
+		fmt.Fprintf(w, "%s, err := abigenList(%s, %d)\n", tuple, src, len(node.fields))
+		errCheck()
+		for i, field := range node.fields {
+			g.decodeStmts(w, dst+"."+field.name, fmt.Sprintf("%s[%d]", tuple, i), field.node, path+"."+field.name, pathArgs)
+		}
+	}
+}
+
+// fieldNodes builds the fields of the struct bound to given components.
+// Indexed parameters of dynamic types are bound to common.Hash when
+// hashIndexed is set, as only their hash is available in event logs.
+func (g *generator) fieldNodes(components []abi.Component, structName string, hashIndexed bool) ([]fieldNode, error) {
+	names := fieldNames(components)
+	fields := make([]fieldNode, len(components))
+	for i, component := range components {
+		if hashIndexed && component.Indexed && isHashedTopic(component.CanonicalType()) {
+			fields[i] = fieldNode{name: names[i], node: &typeNode{kind: kindAs, goType: "common.Hash"}}
+			continue
+		}
+
+		node, err := g.node(component, structName+names[i])
+		if err != nil {
+			return nil, fmt.Errorf("error binding %s: %w", component.Name, err)
+		}
+		fields[i] = fieldNode{name: names[i], node: node}
+	}
+
+	return fields, nil
+}
+
+// node builds the typeNode of given component. Tuples without an
+// internalType are named after fallbackName.
+func (g *generator) node(component abi.Component, fallbackName string) (*typeNode, error) {
+	typeStr := component.Type
+	if strings.HasSuffix(typeStr, "]") {
+		openIndex := strings.LastIndex(typeStr, "[")
+		elemComponent := component
+		elemComponent.Type = typeStr[:openIndex]
+		elem, err := g.node(elemComponent, fallbackName)
+		if err != nil {
+			return nil, err
+		}
+
+		sizeStr := typeStr[openIndex+1 : len(typeStr)-1]
+		if sizeStr == "" {
+			return &typeNode{kind: kindSlice, goType: "[]" + elem.goType, elem: elem}, nil
+		}
+		size, err := strconv.Atoi(sizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array size in %s", typeStr)
+		}
+		return &typeNode{kind: kindArray, goType: "[" + sizeStr + "]" + elem.goType, size: size, elem: elem}, nil
+	}
+
+	switch {
+	case typeStr == "address":
+		return &typeNode{kind: kindAddress, goType: "common.Address"}, nil
+	case typeStr == "bool" || typeStr == "string":
+		return &typeNode{kind: kindAs, goType: typeStr}, nil
+	case typeStr == "function":
+		return &typeNode{kind: kindAs, goType: "abi.FunctionPointer"}, nil
+	case typeStr == "bytes":
+		return &typeNode{kind: kindAs, goType: "[]byte"}, nil
+	case strings.HasPrefix(typeStr, "bytes"):
+		size, err := strconv.Atoi(typeStr[len("bytes"):])
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type %s", typeStr)
+		}
+		return &typeNode{kind: kindFixedBytes, goType: "[" + strconv.Itoa(size) + "]byte", size: size}, nil
+	case strings.HasPrefix(typeStr, "int") || strings.HasPrefix(typeStr, "uint"):
+		return &typeNode{kind: kindAs, goType: "*big.Int"}, nil
+	case strings.HasPrefix(typeStr, "fixed") || strings.HasPrefix(typeStr, "ufixed"):
+		return &typeNode{kind: kindAs, goType: "*big.Float"}, nil
+	case typeStr == "tuple":
+		return g.tupleNode(component, fallbackName)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", typeStr)
+}
+
+// tupleNode builds the node of a tuple, declaring its struct the first
+// time it is seen.
+func (g *generator) tupleNode(component abi.Component, fallbackName string) (*typeNode, error) {
+	name := structName(component.InternalType)
+	if name == "" {
+		name = fallbackName
+	}
+
+	key := component.CanonicalType() + " " + strings.Join(abi.ComponentNames(component.Components), ",")
+	base := name
+	for i := 1; ; i++ {
+		existing, ok := g.structs[name]
+		if !ok || existing == key {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+
+	fields, err := g.fieldNodes(component.Components, name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := g.structs[name]; !ok {
+		g.structs[name] = key
+		fmt.Fprintf(&g.structDecls, "// %s is the Go binding of the `%s` tuple.\n", name, component.CanonicalType())
+		writeStructBody(&g.structDecls, name, fields)
+	}
+
+	return &typeNode{kind: kindTuple, goType: name, fields: fields}, nil
+}
+
+// isHashedTopic checks whether an indexed parameter of given type is
+// stored in event topics through its keccak256 hash.
+func isHashedTopic(typeStr string) bool {
+	isTypeTuple, _, _ := abi.IsTuple(typeStr)
+	isTypeArray, _, _ := abi.IsArray(typeStr)
+
+	return isTypeTuple || isTypeArray || abi.IsDynamic(typeStr, false)
+}
+
+// tmpName returns a new temporary variable name with given prefix.
+func (g *generator) tmpName(prefix string) string {
+	g.tmp++
+	return prefix + strconv.Itoa(g.tmp)
+}
+
+// structName extracts the struct name from an internalType, i.e.
+// `struct Exchange.Order[]` becomes `Order`.
+func structName(internalType string) string {
+	if !strings.HasPrefix(internalType, "struct ") {
+		return ""
+	}
+
+	name := strings.TrimPrefix(internalType, "struct ")
+	if index := strings.Index(name, "["); index != -1 {
+		name = name[:index]
+	}
+	if index := strings.LastIndex(name, "."); index != -1 {
+		name = name[index+1:]
+	}
+
+	return exported(name)
+}
+
+// goNames returns the exported Go names of given fragments, suffixing
+// overloaded names with their position among the overloads.
+func goNames(fragments []*abi.Fragment) []string {
+	counts := make(map[string]int)
+	for _, fragment := range fragments {
+		counts[fragment.Name]++
+	}
+
+	seen := make(map[string]int)
+	names := make([]string, len(fragments))
+	for i, fragment := range fragments {
+		names[i] = exported(fragment.Name)
+		if counts[fragment.Name] > 1 {
+			names[i] += strconv.Itoa(seen[fragment.Name])
+			seen[fragment.Name]++
+		}
+	}
+
+	return names
+}
+
+// fieldNames returns the exported Go field names of given components.
+// Unnamed components are named after their position.
+func fieldNames(components []abi.Component) []string {
+	seen := make(map[string]bool)
+	names := make([]string, len(components))
+	for i, component := range components {
+		name := exported(component.Name)
+		if name == "" {
+			name = "Arg" + strconv.Itoa(i)
+		}
+		if seen[name] {
+			name += strconv.Itoa(i)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// exported converts a Solidity identifier into an exported Go
+// identifier, i.e. `_amount` becomes `Amount`.
+func exported(name string) string {
+	name = strings.TrimLeft(name, "_$")
+	name = strings.ReplaceAll(name, "$", "_")
+	if name == "" {
+		return ""
+	}
+
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+// lowerFirst lowers the first letter of given identifier.
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+
+	return string(runes)
+}
+
+// decodedName returns the name given to the decoded values, which is
+// blank when there are no fields to assign.
+func decodedName(fields []fieldNode) string {
+	if len(fields) == 0 {
+		return "_"
+	}
+
+	return "decoded"
+}
+
+// byteSliceLiteral formats given bytes as a Go []byte literal.
+func byteSliceLiteral(b []byte) string {
+	parts := make([]string, len(b))
+	for i, value := range b {
+		parts[i] = fmt.Sprintf("0x%02x", value)
+	}
+
+	return "[]byte{" + strings.Join(parts, ", ") + "}"
+}
+
+// stringSliceLiteral formats given strings as a Go []string literal.
+func stringSliceLiteral(values []string) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Quote(value)
+	}
+
+	return "[]string{" + strings.Join(parts, ", ") + "}"
+}
+
+// helpersSource holds the helpers shared by the generated methods.
+const helpersSource = `
+// abigenList asserts a decoded tuple or array, checking its length
+// unless n is negative.
+func abigenList(v any, n int) ([]any, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected []any, got %T", v)
+	}
+	if n >= 0 && len(list) != n {
+		return nil, fmt.Errorf("expected %d values, got %d", n, len(list))
+	}
+	return list, nil
+}
+
+// abigenAs asserts a decoded value of type T.
+func abigenAs[T any](v any) (T, error) {
+	t, ok := v.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("expected %T, got %T", zero, v)
+	}
+	return t, nil
+}
+
+// abigenAddress converts a decoded address.
+func abigenAddress(v any) (common.Address, error) {
+	s, ok := v.(string)
+	if !ok || !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("expected address, got %v", v)
+	}
+	return common.HexToAddress(s), nil
+}
+
+// abigenFixedBytes copies decoded fixed bytes into dst.
+func abigenFixedBytes(v any, dst []byte) error {
+	b, ok := v.([]byte)
+	if !ok {
+		return fmt.Errorf("expected []byte, got %T", v)
+	}
+	if len(b) < len(dst) {
+		return fmt.Errorf("expected %d bytes, got %d", len(dst), len(b))
+	}
+	copy(dst, b)
+	return nil
+}
+`
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omnes-tech/abi"
+)
+
+func Example_generate() {
+	contract, err := abi.LoadJSON(strings.NewReader(`[
+		{"type":"function","name":"fill","inputs":[{"name":"order","type":"tuple","internalType":"struct Exchange.Order","components":[{"name":"maker","type":"address"},{"name":"amounts","type":"uint256[]"},{"name":"salt","type":"bytes32"}]},{"name":"_owners","type":"address[3]"}],"outputs":[{"name":"filled","type":"uint256"}]},
+		{"type":"event","name":"Named","inputs":[{"name":"name","type":"string","indexed":true},{"name":"owner","type":"address","indexed":false}],"anonymous":false}
+	]`))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	code, err := generate(contract, config{pkg: "exchange"})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "exchange.go", code, 0)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					var fields []string
+					for _, field := range typeSpec.Type.(*ast.StructType).Fields.List {
+						fields = append(fields, field.Names[0].Name)
+					}
+					fmt.Println("type", typeSpec.Name.Name, fields)
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv != nil {
+				fmt.Println("method", decl.Name.Name)
+			}
+		}
+	}
+
+	// Output:
+	// type Order [Maker Amounts Salt]
+	// type FillInput [Order Owners]
+	// method Encode
+	// method Decode
+	// type FillOutput [Filled]
+	// method Encode
+	// method Decode
+	// type NamedEvent [Name Owner]
+	// method DecodeLog
+}
+
+var update = flag.Bool("update", false, "update the golden files of the generated bindings")
+
+// generateTestdata generates the bindings of testdata/exchange.abi.json.
+func generateTestdata(t *testing.T) []byte {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("testdata", "exchange.abi.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	contract, err := abi.LoadJSON(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := generate(contract, config{pkg: "exchange", helpers: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
+
+func TestGenerateGolden(t *testing.T) {
+	code := generateTestdata(t)
+
+	golden := filepath.Join("testdata", "exchange.go.golden")
+	if *update {
+		err := os.WriteFile(golden, code, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(code, want) {
+		t.Errorf("generated bindings differ from %s, run `go test -run TestGenerateGolden -update` to update it", golden)
+	}
+}
+
+// roundTripMain encodes a call with the generated bindings, checks it
+// against the abi package and decodes it back.
+const roundTripMain = `package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+
+	"example.com/roundtrip/exchange"
+)
+
+func main() {
+	in := exchange.FillInput{
+		Order: exchange.Order{
+			Maker:   common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+			Amounts: []*big.Int{big.NewInt(1000), big.NewInt(2000)},
+			Salt:    [32]byte{0xde, 0xad},
+		},
+		Owners: [3]common.Address{{0x01}, {0x02}, {0x03}},
+	}
+	data, err := in.Encode()
+	if err != nil {
+		fail(err)
+	}
+
+	want, err := abi.EncodeWithSignature("fill((address,uint256[],bytes32),address[3])",
+		[]any{in.Order.Maker, []any{in.Order.Amounts[0], in.Order.Amounts[1]}, in.Order.Salt[:]},
+		[]any{in.Owners[0], in.Owners[1], in.Owners[2]},
+	)
+	if err != nil {
+		fail(err)
+	}
+	if !bytes.Equal(data, want) {
+		fail(fmt.Errorf("call data mismatch:\n%x\n%x", data, want))
+	}
+
+	var out exchange.FillInput
+	err = out.Decode(data)
+	if err != nil {
+		fail(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		fail(fmt.Errorf("decoded %+v, want %+v", out, in))
+	}
+
+	fmt.Println("ok")
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+`
+
+func TestGenerateBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("building the generated bindings is slow")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The temporary module requires the dependencies of this module,
+	// replacing it with its local copy.
+	goMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, requires, _ := strings.Cut(string(goMod), "\n")
+	goMod = []byte("module example.com/roundtrip\n" + requires +
+		"\nrequire github.com/omnes-tech/abi v0.0.0\n\nreplace github.com/omnes-tech/abi => " + root + "\n")
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"go.mod":               goMod,
+		"go.sum":               goSum,
+		"main.go":              []byte(roundTripMain),
+		"exchange/exchange.go": generateTestdata(t),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err == nil {
+			err = os.WriteFile(path, content, 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("error running the generated bindings: %v\n%s", err, output)
+	}
+	if got := strings.TrimSpace(string(output)); got != "ok" {
+		t.Errorf("unexpected output of the generated bindings: %s", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Command abigen-lite generates reflection-free Go bindings from a
+// Solidity JSON ABI.
+//
+// For every function it emits an input struct (with Encode and Decode
+// methods for call data) and, when the function has outputs, an output
+// struct (with Encode and Decode methods for return data). Events get a
+// struct with a DecodeLog method and errors a struct with Encode and
+// Decode methods for revert data. Tuples become named structs, taken
+// from their internalType when available. The generated methods assign
+// decoded values with plain type assertions instead of going through
+// abi.Parse.
+//
+// Usage:
+//
+//	abigen-lite -abi Token.abi.json -pkg token -out token.go
+//
+// When generating several files into the same package, pass
+// -helpers=false to all but one of them so the shared decoding helpers
+// are only emitted once.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/omnes-tech/abi"
+)
+
+func main() {
+	abiPath := flag.String("abi", "", "path to the JSON ABI file (required)")
+	pkg := flag.String("pkg", "bindings", "package name of the generated file")
+	out := flag.String("out", "", "output file (defaults to stdout)")
+	helpers := flag.Bool("helpers", true, "emit the shared decoding helpers")
+	flag.Parse()
+
+	if *abiPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	err := run(*abiPath, *pkg, *out, *helpers)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "abigen-lite:", err)
+		os.Exit(1)
+	}
+}
+
+// run loads the JSON ABI at abiPath and writes the generated bindings.
+func run(abiPath string, pkg string, out string, helpers bool) error {
+	file, err := os.Open(abiPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	contract, err := abi.LoadJSON(file)
+	if err != nil {
+		return err
+	}
+
+	code, err := generate(contract, config{pkg: pkg, helpers: helpers})
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+
+	return os.WriteFile(out, code, 0o644)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/omnes-tech/abi"
+)
+
+// stringsFlag is a flag that can be given several times.
+type stringsFlag []string
+
+// String implements flag.Value.
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+// Set implements flag.Value.
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runSelector prints the canonical signature and selector of a
+// signature, and its topic when it is an event.
+func runSelector(args []string) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("selector expects a single signature")
+	}
+
+	fragment, err := abi.ParseFragment(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{
+		"signature": fragment.Signature(),
+		"selector":  hexutil.Encode(fragment.Selector()),
+	}
+	if fragment.Type == "event" {
+		result["topic"] = fragment.Topic().Hex()
+	}
+
+	return result, nil
+}
+
+// runEncode encodes the arguments of a signature. Signatures with a
+// name are encoded as call data, prefixed with their selector.
+func runEncode(args []string) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("encode expects a signature")
+	}
+
+	fragment, err := parseSignature(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	typeStrs := fragment.InputTypes()
+	if len(args[1:]) != len(typeStrs) {
+		return nil, fmt.Errorf("expected %d arguments for %s, got %d", len(typeStrs), fragment.Signature(), len(args[1:]))
+	}
+
+	values := make([]any, len(typeStrs))
+	for i, typeStr := range typeStrs {
+		values[i], err = parseArg(typeStr, args[1+i])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing argument %d: %w", i, err)
+		}
+	}
+
+	encoded, err := abi.Encode(typeStrs, values...)
+	if err != nil {
+		return nil, err
+	}
+	if fragment.Name != "" {
+		encoded = append(fragment.Selector(), encoded...)
+	}
+
+	return map[string]any{"data": hexutil.Encode(encoded)}, nil
+}
+
+// runDecode decodes data holding the inputs (or outputs) of a signature.
+func runDecode(args []string, stdin io.Reader) (any, error) {
+	flags := flag.NewFlagSet("decode", flag.ContinueOnError)
+	returns := flags.Bool("returns", false, "decode the return values of the signature")
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if flags.NArg() < 1 {
+		return nil, fmt.Errorf("decode expects a signature")
+	}
+
+	fragment, err := parseSignature(flags.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readHex(flags.Arg(1), stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	components := fragment.Inputs
+	if *returns {
+		components = fragment.Outputs
+	}
+
+	decoded, err := abi.Decode(abi.ComponentTypes(components), data)
+	if err != nil {
+		return nil, err
+	}
+
+	return toJSONMap(decoded, components)
+}
+
+// runDecodeCalldata decodes call data against the given signatures.
+func runDecodeCalldata(args []string, stdin io.Reader) (any, error) {
+	flags := flag.NewFlagSet("decode-calldata", flag.ContinueOnError)
+	var signatures stringsFlag
+	flags.Var(&signatures, "sig", "function signature (can be repeated)")
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := abi.NewRegistry(signatures...)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readHex(flags.Arg(0), stdin)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("call data is too short to contain a selector")
+	}
+
+	fragment, ok := registry.Lookup([4]byte(data[:4]))
+	if !ok {
+		return nil, fmt.Errorf("unknown selector: %s", hexutil.Encode(data[:4]))
+	}
+
+	decoded, err := abi.Decode(fragment.InputTypes(), data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := toJSONMap(decoded, fragment.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"method": fragment.Signature(), "args": parsed}, nil
+}
+
+// runDecodeLog decodes an event log given its topics and data.
+func runDecodeLog(args []string, stdin io.Reader) (any, error) {
+	flags := flag.NewFlagSet("decode-log", flag.ContinueOnError)
+	eventSig := flags.String("event", "", "event signature with its indexed parameters")
+	topicsStr := flags.String("topics", "", "comma separated topics of the log")
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := abi.ParseFragment(*eventSig)
+	if err != nil {
+		return nil, err
+	}
+
+	var log types.Log
+	for _, topic := range strings.Split(*topicsStr, ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			log.Topics = append(log.Topics, common.HexToHash(topic))
+		}
+	}
+
+	var hasData bool
+	for _, input := range event.Inputs {
+		hasData = hasData || !input.Indexed
+	}
+	if hasData {
+		log.Data, err = readHex(flags.Arg(0), stdin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decoded, err := abi.DecodeLog(log, event)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := toJSONMap(decoded, event.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"event": event.Signature(), "args": parsed}, nil
+}
+
+// parseSignature parses a signature, either a fragment or a bare list
+// of types (i.e. `(uint256,string)`).
+func parseSignature(signature string) (*abi.Fragment, error) {
+	signature = strings.TrimSpace(signature)
+	if strings.HasPrefix(signature, "(") {
+		inputs, err := abi.ParseFragment("f" + signature)
+		if err != nil {
+			return nil, err
+		}
+		inputs.Name = ""
+		return inputs, nil
+	}
+
+	return abi.ParseFragment(signature)
+}
@@ -0,0 +1,71 @@
+// Command goethabi encodes and decodes ABI data from the terminal,
+// printing its results as JSON.
+//
+// Usage:
+//
+//	goethabi selector <signature>
+//	goethabi encode <signature> [args...]
+//	goethabi decode [-returns] <signature> [hex]
+//	goethabi decode-calldata -sig <signature> [-sig <signature>...] [hex]
+//	goethabi decode-log -event <signature> -topics <topic,...> [hex]
+//
+// Signatures are human-readable (i.e. `function transfer(address to, uint256 amount)`)
+// or canonical (i.e. `transfer(address,uint256)`). Arguments of arrays
+// and tuples are given as JSON arrays (i.e. `[1,2,3]` or `["0x...",42]`).
+// When the hex data is omitted or is "-", it is read from stdin.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// usage describes the subcommands.
+const usage = `usage:
+  goethabi selector <signature>
+  goethabi encode <signature> [args...]
+  goethabi decode [-returns] <signature> [hex]
+  goethabi decode-calldata -sig <signature> [-sig <signature>...] [hex]
+  goethabi decode-log -event <signature> -topics <topic,...> [hex]
+`
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "goethabi:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the subcommand given in args.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand\n%s", usage)
+	}
+
+	var result any
+	var err error
+	switch args[0] {
+	case "selector":
+		result, err = runSelector(args[1:])
+	case "encode":
+		result, err = runEncode(args[1:])
+	case "decode":
+		result, err = runDecode(args[1:], stdin)
+	case "decode-calldata":
+		result, err = runDecodeCalldata(args[1:], stdin)
+	case "decode-log":
+		result, err = runDecodeLog(args[1:], stdin)
+	case "help", "-h", "-help", "--help":
+		_, err = io.WriteString(stdout, usage)
+		return err
+	default:
+		return fmt.Errorf("unknown subcommand %q\n%s", args[0], usage)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(stdout, result)
+}
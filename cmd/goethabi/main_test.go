@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func Example_selector() {
+	err := run([]string{"selector", "event Transfer(address indexed from, address indexed to, uint256 value)"}, nil, os.Stdout)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {
+	//   "selector": "0xddf252ad",
+	//   "signature": "Transfer(address,address,uint256)",
+	//   "topic": "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	// }
+}
+
+func Example_encode() {
+	err := run([]string{"encode", "function transfer(address to, uint256 amount)", "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", "1000"}, nil, os.Stdout)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {
+	//   "data": "0xa9059cbb0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d278900000000000000000000000000000000000000000000000000000000000003e8"
+	// }
+}
+
+func Example_decode() {
+	stdin := strings.NewReader("0x000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000b")
+	err := run([]string{"decode", "(uint256[] ids, bool ok)"}, stdin, os.Stdout)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {
+	//   "ids": [
+	//     "10",
+	//     "11"
+	//   ],
+	//   "ok": true
+	// }
+}
+
+func Example_decodeCalldata() {
+	err := run([]string{
+		"decode-calldata",
+		"-sig", "function transfer(address to, uint256 amount)",
+		"-sig", "function approve(address spender, uint256 amount)",
+		"0xa9059cbb0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d278900000000000000000000000000000000000000000000000000000000000003e8",
+	}, nil, os.Stdout)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {
+	//   "args": {
+	//     "amount": "1000",
+	//     "to": "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"
+	//   },
+	//   "method": "transfer(address,uint256)"
+	// }
+}
+
+func Example_decodeLog() {
+	err := run([]string{
+		"decode-log",
+		"-event", "event Transfer(address indexed from, address indexed to, uint256 value)",
+		"-topics", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef,0x0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d2789,0x000000000000000000000000000000000000000000000000000000000000dead",
+		"0x00000000000000000000000000000000000000000000000000000000000003e8",
+	}, nil, os.Stdout)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {
+	//   "args": {
+	//     "from": "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789",
+	//     "to": "0x000000000000000000000000000000000000dEaD",
+	//     "value": "1000"
+	//   },
+	//   "event": "Transfer(address,address,uint256)"
+	// }
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/omnes-tech/abi"
+)
+
+// parseArg converts a command line argument into the value expected by
+// abi.Encode for given type. Arrays and tuples are given as JSON.
+func parseArg(typeStr string, raw string) (any, error) {
+	isTypeArray, _, err := abi.IsArray(typeStr)
+	if err != nil {
+		return nil, err
+	}
+	isTypeTuple, _, err := abi.IsTuple(typeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isTypeArray && !isTypeTuple {
+		return parseCoreArg(typeStr, raw)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON for %s: %w", typeStr, err)
+	}
+
+	return fromJSON(typeStr, value)
+}
+
+// fromJSON converts a JSON value into the value expected by abi.Encode
+// for given type.
+func fromJSON(typeStr string, value any) (any, error) {
+	isTypeArray, _, err := abi.IsArray(typeStr)
+	if err != nil {
+		return nil, err
+	}
+	isTypeTuple, splitedTypes, err := abi.IsTuple(typeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isTypeArray && !isTypeTuple {
+		switch value := value.(type) {
+		case string:
+			return parseCoreArg(typeStr, value)
+		case json.Number:
+			return parseCoreArg(typeStr, value.String())
+		case bool:
+			return parseCoreArg(typeStr, strconv.FormatBool(value))
+		default:
+			return nil, fmt.Errorf("invalid value %v for %s", value, typeStr)
+		}
+	}
+
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected JSON array for %s, got %v", typeStr, value)
+	}
+
+	elemTypes := splitedTypes
+	if isTypeArray {
+		elemTypeStr := typeStr[:strings.LastIndex(typeStr, "[")]
+		elemTypes = make([]string, len(list))
+		for i := range elemTypes {
+			elemTypes[i] = elemTypeStr
+		}
+	}
+	if len(elemTypes) != len(list) {
+		return nil, fmt.Errorf("expected %d values for %s, got %d", len(elemTypes), typeStr, len(list))
+	}
+
+	result := make([]any, len(list))
+	for i, elem := range list {
+		result[i], err = fromJSON(elemTypes[i], elem)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// parseCoreArg converts a string into the value expected by abi.Encode
+// for given non-array and non-tuple type.
+func parseCoreArg(typeStr string, raw string) (any, error) {
+	switch {
+	case typeStr == "address":
+		if !common.IsHexAddress(raw) {
+			return nil, fmt.Errorf("invalid address %q", raw)
+		}
+		address := common.HexToAddress(raw)
+		return &address, nil
+	case typeStr == "bool":
+		return strconv.ParseBool(raw)
+	case typeStr == "string":
+		return raw, nil
+	case typeStr == "function" || strings.HasPrefix(typeStr, "bytes"):
+		return hexutil.Decode(raw)
+	case strings.HasPrefix(typeStr, "int") || strings.HasPrefix(typeStr, "uint"):
+		value, ok := new(big.Int).SetString(raw, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", raw)
+		}
+		return value, nil
+	case strings.HasPrefix(typeStr, "fixed") || strings.HasPrefix(typeStr, "ufixed"):
+		value, ok := new(big.Float).SetString(raw)
+		if !ok {
+			return nil, fmt.Errorf("invalid fixed point number %q", raw)
+		}
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", typeStr)
+}
+
+// toJSONMap parses decoded values into a map keyed by the component
+// names holding JSON friendly values: integers become decimal strings
+// and bytes become hex strings.
+func toJSONMap(decoded []any, components []abi.Component) (map[string]any, error) {
+	parsed, err := abi.ParseToMap(decoded, components)
+	if err != nil {
+		return nil, err
+	}
+
+	return toJSON(parsed).(map[string]any), nil
+}
+
+// toJSON converts a parsed value into a JSON friendly value.
+func toJSON(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(value))
+		for key, elem := range value {
+			result[key] = toJSON(elem)
+		}
+		return result
+	case []any:
+		result := make([]any, len(value))
+		for i, elem := range value {
+			result[i] = toJSON(elem)
+		}
+		return result
+	case *big.Int:
+		return value.String()
+	case *big.Float:
+		return value.Text('f', -1)
+	case []byte:
+		return hexutil.Encode(value)
+	case common.Hash:
+		return value.Hex()
+	}
+
+	return value
+}
+
+// readHex decodes given hex string, reading it from stdin when empty
+// or "-".
+func readHex(hexStr string, stdin io.Reader) ([]byte, error) {
+	if hexStr == "" || hexStr == "-" {
+		input, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, err
+		}
+		hexStr = string(input)
+	}
+
+	hexStr = strings.TrimSpace(hexStr)
+	if !strings.HasPrefix(hexStr, "0x") && !strings.HasPrefix(hexStr, "0X") {
+		hexStr = "0x" + hexStr
+	}
+	if hexStr == "0x" {
+		return []byte{}, nil
+	}
+
+	return hexutil.Decode(hexStr)
+}
+
+// writeJSON writes the indented JSON of given value.
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
@@ -2,28 +2,26 @@ package abi
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 // IsDynamic checks whether given type string is a dynamic,
-// i.e. if it is either a string, bytes, or an array.
+// i.e. if it is either a string, bytes, an unbounded array,
+// a bounded array of dynamic types, or a tuple holding any
+// dynamic type. The isTuple argument is kept for compatibility,
+// tuples are detected from the type string.
 func IsDynamic(typeStr string, isTuple bool) bool {
+	return typePlanFor(typeStr).dynamic
+}
 
-	openBracketsIndex := strings.Index(typeStr, "[")
-	closeBracketsIndex := strings.Index(typeStr, "]")
-
-	if openBracketsIndex == -1 {
-		if strings.Contains(typeStr, "string") ||
-			typeStr == "bytes" ||
-			(isTuple && (strings.Contains(typeStr, "bytes,") || strings.Contains(typeStr, "bytes)"))) {
-			return true
-		}
-	} else if closeBracketsIndex == openBracketsIndex+1 {
-		return true
-	}
-
-	return false
+// headSize returns the size in bytes taken in the head of an encoding
+// by a value of given type string: 32 bytes for dynamic types (their
+// offset) and the size of the whole inline encoding for static types.
+func headSize(typeStr string) (int, error) {
+	plan := typePlanFor(typeStr)
+	return plan.headSize, plan.headErr
 }
 
 // IsArray checks whether given type string is an array.
@@ -32,6 +30,32 @@ func IsDynamic(typeStr string, isTuple bool) bool {
 // be 0 wheter an error occured, it is not an array,
 // or it is an unbounded array (i.e. `uint256[]`).
 func IsArray(typeStr string) (bool, int, error) {
+	plan := typePlanFor(typeStr)
+	return plan.isArray, plan.arraySize, plan.arrayErr
+}
+
+// IsTuple checks whether given type string is a tuple (i.e. `(uint256,bytes,address)`).
+// Also returns the array of type strings in the tuple (i.e. [uint256,bytes,address]).
+func IsTuple(typeStr string) (bool, []string, error) {
+	isTuple, members, err := tupleType(typeStr)
+	return isTuple, slices.Clone(members), err
+}
+
+// tupleType is IsTuple without copying the type strings in the tuple,
+// which are shared with the type cache and must not be modified.
+func tupleType(typeStr string) (bool, []string, error) {
+	plan := typePlanFor(typeStr)
+	return plan.isTuple, plan.members, plan.tupleErr
+}
+
+// arrayElemType returns the element type of an array type string
+// (i.e. `uint256` for `uint256[3]`).
+func arrayElemType(typeStr string) string {
+	return typeStr[:strings.LastIndex(typeStr, "[")]
+}
+
+// parseArrayType parses an array type string for IsArray.
+func parseArrayType(typeStr string) (bool, int, error) {
 	if strings.Count(typeStr, "[") != strings.Count(typeStr, "]") {
 		return false, 0, fmt.Errorf("invalid array definition")
 	}
@@ -45,11 +69,20 @@ func IsArray(typeStr string) (bool, int, error) {
 			return false, 0, nil
 		}
 
+		if closeBracketIndex < openBracketIndex {
+			return false, 0, fmt.Errorf("invalid array definition")
+		}
+
+		// Sizes must be positive decimal numbers, which Atoi alone does
+		// not check (i.e. `uint256[-1]` or `uint256[+1]`).
 		var arraySize int
-		var err error
-		if closeBracketIndex > openBracketIndex+1 {
-			arraySize, err = strconv.Atoi(typeStr[openBracketIndex+1 : closeBracketIndex])
-			if err != nil {
+		if sizeStr := typeStr[openBracketIndex+1 : closeBracketIndex]; sizeStr != "" {
+			if strings.TrimLeft(sizeStr, "0123456789") != "" {
+				return false, 0, fmt.Errorf("invalid array definition")
+			}
+			var err error
+			arraySize, err = strconv.Atoi(sizeStr)
+			if err != nil || arraySize <= 0 {
 				return false, 0, fmt.Errorf("invalid array definition")
 			}
 		}
@@ -60,9 +93,8 @@ func IsArray(typeStr string) (bool, int, error) {
 	return false, 0, nil
 }
 
-// IsTuple checks whether given type string is a tuple (i.e. `(uint256,bytes,address)`).
-// Also returns the array of type strings in the tuple (i.e. [uint256,bytes,address]).
-func IsTuple(typeStr string) (bool, []string, error) {
+// parseTupleType parses a tuple type string for IsTuple.
+func parseTupleType(typeStr string) (bool, []string, error) {
 	if strings.Count(typeStr, "(") != strings.Count(typeStr, ")") {
 		return false, nil, fmt.Errorf("invalid tuple definition")
 	}
@@ -48,6 +48,17 @@ func ExampleIsTuple() {
 	// Output: true [address uint256 bytes]
 }
 
+func ExampleIsTuple_modified() {
+	typeStr := "(address,uint256)"
+	_, types, _ := abi.IsTuple(typeStr)
+	types[0] = "bytes"
+
+	_, types, _ = abi.IsTuple(typeStr)
+	fmt.Println(types)
+
+	// Output: [address uint256]
+}
+
 func ExampleIsArray() {
 	typeStr := "(address,uint256,bytes)[]"
 	isArray, size, err := abi.IsArray(typeStr)
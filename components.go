@@ -0,0 +1,62 @@
+package abi
+
+import "strings"
+
+// Component describes a named ABI parameter, following the layout used
+// by Solidity JSON ABIs. Tuple parameters have Type "tuple" (or
+// "tuple[]", "tuple[2]", ...) and list their members in Components.
+type Component struct {
+	Name         string      `json:"name"`
+	Type         string      `json:"type"`
+	InternalType string      `json:"internalType,omitempty"`
+	Indexed      bool        `json:"indexed,omitempty"`
+	Components   []Component `json:"components,omitempty"`
+}
+
+// CanonicalType returns the type string of the component in the format
+// expected by Encode and Decode, expanding tuples into their member
+// types (i.e. `tuple[]` with members address and uint256 becomes
+// `(address,uint256)[]`).
+func (c Component) CanonicalType() string {
+	if !strings.HasPrefix(c.Type, "tuple") {
+		return c.Type
+	}
+
+	return "(" + strings.Join(ComponentTypes(c.Components), ",") + ")" + strings.TrimPrefix(c.Type, "tuple")
+}
+
+// ComponentTypes returns the canonical type strings of given components.
+func ComponentTypes(components []Component) []string {
+	typeStrs := make([]string, len(components))
+	for i, component := range components {
+		typeStrs[i] = component.CanonicalType()
+	}
+
+	return typeStrs
+}
+
+// cloneComponents returns a deep copy of given components, keeping
+// nil and empty lists apart.
+func cloneComponents(components []Component) []Component {
+	if components == nil {
+		return nil
+	}
+
+	clone := make([]Component, len(components))
+	for i, component := range components {
+		clone[i] = component
+		clone[i].Components = cloneComponents(component.Components)
+	}
+
+	return clone
+}
+
+// ComponentNames returns the names of given components.
+func ComponentNames(components []Component) []string {
+	names := make([]string, len(components))
+	for i, component := range components {
+		names[i] = component.Name
+	}
+
+	return names
+}
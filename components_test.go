@@ -0,0 +1,26 @@
+package abi_test
+
+import (
+	"fmt"
+
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleComponent_CanonicalType() {
+	component := abi.Component{
+		Name: "orders",
+		Type: "tuple[]",
+		Components: []abi.Component{
+			{Name: "maker", Type: "address"},
+			{Name: "amounts", Type: "uint256[]"},
+			{Name: "fee", Type: "tuple", Components: []abi.Component{
+				{Name: "recipient", Type: "address"},
+				{Name: "bps", Type: "uint16"},
+			}},
+		},
+	}
+
+	fmt.Println(component.CanonicalType())
+
+	// Output: (address,uint256[],(address,uint16))[]
+}
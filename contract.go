@@ -0,0 +1,359 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Contract is a runtime representation of a contract ABI, giving access
+// to its functions, events and errors by name.
+type Contract struct {
+	Constructor *Fragment
+	Fallback    *Fragment
+	Receive     *Fragment
+	Methods     []*Fragment
+	Events      []*Fragment
+	Errors      []*Fragment
+}
+
+// LoadJSON parses a standard Solidity JSON ABI (the `abi` array emitted
+// by solc, Hardhat or Foundry) into a Contract.
+func LoadJSON(r io.Reader) (*Contract, error) {
+	var fragments []*Fragment
+	err := json.NewDecoder(r).Decode(&fragments)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding JSON ABI: %w", err)
+	}
+
+	return NewContract(fragments...)
+}
+
+// NewContract creates a contract holding given fragments. Fragments
+// may come from ParseFragment or from a JSON ABI.
+func NewContract(fragments ...*Fragment) (*Contract, error) {
+	contract := &Contract{}
+	for _, fragment := range fragments {
+		if fragment.Type == "" {
+			fragment.Type = "function"
+		}
+		if fragment.Inputs == nil {
+			fragment.Inputs = []Component{}
+		}
+
+		switch fragment.Type {
+		case "function":
+			contract.Methods = append(contract.Methods, fragment)
+		case "event":
+			contract.Events = append(contract.Events, fragment)
+		case "error":
+			contract.Errors = append(contract.Errors, fragment)
+		case "constructor":
+			contract.Constructor = fragment
+		case "fallback":
+			contract.Fallback = fragment
+		case "receive":
+			contract.Receive = fragment
+		default:
+			return nil, fmt.Errorf("invalid fragment type %q for %s", fragment.Type, fragment.Name)
+		}
+	}
+
+	return contract, nil
+}
+
+// MergeContracts merges the ABIs of several contracts into one, i.e.
+// the implementation and the facets behind a proxy or diamond address,
+// so that call data and logs sent to it can be decoded against all known
+// selectors and topics.
+//
+// Functions, events and errors defined identically by several contracts
+// are kept once. Two definitions sharing a selector (or an event topic)
+// but differing in their signature, outputs or indexed parameters are
+// conflicts, which are all reported in the returned error, wrapping
+// ErrSelectorCollision. The constructor, fallback and receive functions
+// are those of the first contract defining them.
+func MergeContracts(contracts ...*Contract) (*Contract, error) {
+	merged := &Contract{}
+	defined := make(map[string]*Fragment)
+	var conflicts []error
+
+	add := func(fragments []*Fragment, key func(*Fragment) string, layout func(*Fragment) string) []*Fragment {
+		var added []*Fragment
+		for _, fragment := range fragments {
+			existing, ok := defined[key(fragment)]
+			if !ok {
+				defined[key(fragment)] = fragment
+				added = append(added, fragment)
+				continue
+			}
+			if existing.Signature() != fragment.Signature() || layout(existing) != layout(fragment) {
+				conflicts = append(conflicts, fmt.Errorf("%w: %s is defined as both %q and %q", ErrSelectorCollision, key(fragment), existing.String(), fragment.String()))
+			}
+		}
+		return added
+	}
+
+	for _, contract := range contracts {
+		merged.Methods = append(merged.Methods, add(contract.Methods, fragmentSelectorKey, fragmentOutputs)...)
+		merged.Events = append(merged.Events, add(contract.Events, eventKey, fragmentIndexed)...)
+		merged.Errors = append(merged.Errors, add(contract.Errors, fragmentSelectorKey, fragmentOutputs)...)
+
+		if merged.Constructor == nil {
+			merged.Constructor = contract.Constructor
+		}
+		if merged.Fallback == nil {
+			merged.Fallback = contract.Fallback
+		}
+		if merged.Receive == nil {
+			merged.Receive = contract.Receive
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, errors.Join(conflicts...)
+	}
+
+	return merged, nil
+}
+
+// fragmentSelectorKey keys functions and errors by their selector.
+func fragmentSelectorKey(fragment *Fragment) string {
+	return fragment.Type + " 0x" + common.Bytes2Hex(fragment.Selector())
+}
+
+// eventKey keys events by their topic. Anonymous events have none, so
+// they are keyed by their signature.
+func eventKey(fragment *Fragment) string {
+	if fragment.Anonymous {
+		return "anonymous event " + fragment.Signature()
+	}
+
+	return "event " + fragment.Topic().Hex()
+}
+
+// fragmentOutputs describes the outputs of a fragment.
+func fragmentOutputs(fragment *Fragment) string {
+	return strings.Join(fragment.OutputTypes(), ",")
+}
+
+// fragmentIndexed describes which inputs of an event are indexed.
+func fragmentIndexed(fragment *Fragment) string {
+	var indexed strings.Builder
+	for _, input := range fragment.Inputs {
+		if input.Indexed {
+			indexed.WriteByte('1')
+		} else {
+			indexed.WriteByte('0')
+		}
+	}
+
+	return indexed.String()
+}
+
+// Registry returns a registry holding the functions of the contract,
+// to resolve selectors with DecodeCalldata.
+func (c *Contract) Registry() (*Registry, error) {
+	registry := &Registry{}
+	for _, method := range c.Methods {
+		err := registry.RegisterFragment(method)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// Method returns the function with given name. Overloaded functions
+// must be referenced by their signature (i.e. `transfer(address,uint256)`).
+func (c *Contract) Method(name string) (*Fragment, error) {
+	return findFragment(c.Methods, "function", name)
+}
+
+// Event returns the event with given name or signature.
+func (c *Contract) Event(name string) (*Fragment, error) {
+	return findFragment(c.Events, "event", name)
+}
+
+// Error returns the error with given name or signature.
+func (c *Contract) Error(name string) (*Fragment, error) {
+	return findFragment(c.Errors, "error", name)
+}
+
+// EncodeCall encodes a call to given function, prefixed with its
+// selector. Arguments are either given one per function input, or as a
+// single struct holding all inputs, following the conventions of
+// Marshal.
+func (c *Contract) EncodeCall(name string, args ...any) ([]byte, error) {
+	method, err := c.Method(name)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	values, err := marshalArgs(method.Inputs, args)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error encoding arguments of %s: %w", method.Signature(), err)
+	}
+
+	encoded, err := Encode(method.InputTypes(), values...)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error encoding arguments of %s: %w", method.Signature(), err)
+	}
+
+	return append(method.Selector(), encoded...), nil
+}
+
+// EncodeConstructor encodes the deployment data of the contract from
+// its creation bytecode and constructor arguments, given like the
+// arguments of EncodeCall.
+func (c *Contract) EncodeConstructor(bytecode []byte, args ...any) ([]byte, error) {
+	inputs := c.constructorInputs()
+	values, err := marshalArgs(inputs, args)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error encoding constructor arguments: %w", err)
+	}
+
+	return EncodeConstructor(bytecode, ComponentTypes(inputs), values...)
+}
+
+// DecodeConstructor decodes the constructor arguments appended to given
+// creation bytecode in deployment data, and parses them into the struct
+// pointed by v. Fields tagged with `abi:"name"` are mapped to the
+// constructor input with the same name.
+func (c *Contract) DecodeConstructor(deployData []byte, bytecode []byte, v any) error {
+	inputs := c.constructorInputs()
+	decoded, err := DecodeConstructor(deployData, bytecode, ComponentTypes(inputs))
+	if err != nil {
+		return err
+	}
+
+	return ParseWithComponents(decoded, inputs, v)
+}
+
+// DecodeReturn decodes the return data of given function and parses it
+// into the value pointed by v, following the conventions of the package
+// level DecodeReturn. Fields tagged with `abi:"name"` are mapped to the
+// output with the same name.
+func (c *Contract) DecodeReturn(name string, data []byte, v any) error {
+	method, err := c.Method(name)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := Decode(method.OutputTypes(), data)
+	if err != nil {
+		return fmt.Errorf("error decoding return data of %s: %w", method.Signature(), err)
+	}
+
+	return parseReturn(decoded, method.Outputs, v)
+}
+
+// DecodeCalldata finds the function matching the selector of given call
+// data, decodes its arguments and parses them into the struct pointed by
+// v. It returns the function fragment.
+func (c *Contract) DecodeCalldata(data []byte, v any) (*Fragment, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("call data is too short to contain a selector. Length: %d", len(data))
+	}
+
+	for _, method := range c.Methods {
+		if !bytes.Equal(method.Selector(), data[:4]) {
+			continue
+		}
+
+		decoded, err := Decode(method.InputTypes(), data[4:])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", method.Signature(), err)
+		}
+
+		return method, ParseWithComponents(decoded, method.Inputs, v)
+	}
+
+	return nil, fmt.Errorf("unknown selector: 0x%s", common.Bytes2Hex(data[:4]))
+}
+
+// DecodeEvent finds the event matching the first topic of given log,
+// decodes it and parses its parameters into the struct pointed by v.
+// It returns the event fragment. Anonymous events have no topic to be
+// matched with, so they must be decoded with DecodeLog or ResolveLog.
+func (c *Contract) DecodeEvent(log types.Log, v any) (*Fragment, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log has no topics")
+	}
+
+	for _, event := range c.Events {
+		if event.Anonymous || event.Topic() != log.Topics[0] {
+			continue
+		}
+
+		decoded, err := DecodeLog(log, event)
+		if err != nil {
+			return nil, err
+		}
+
+		return event, ParseWithComponents(decoded, event.Inputs, v)
+	}
+
+	return nil, fmt.Errorf("unknown event topic: %s", log.Topics[0].Hex())
+}
+
+// constructorInputs returns the inputs of the contract constructor,
+// which has none when it is not part of the ABI.
+func (c *Contract) constructorInputs() []Component {
+	if c.Constructor == nil {
+		return []Component{}
+	}
+
+	return c.Constructor.Inputs
+}
+
+// marshalArgs converts call arguments into the values to be encoded
+// with given inputs. Arguments are either given one per input or as a
+// single struct holding all inputs.
+func marshalArgs(inputs []Component, args []any) ([]any, error) {
+	typeStrs := ComponentTypes(inputs)
+	if len(args) == 1 && len(typeStrs) != 1 {
+		return marshalParams(reflect.ValueOf(args[0]), typeStrs)
+	}
+
+	return marshalList(reflect.ValueOf(args), typeStrs)
+}
+
+// findFragment finds the fragment with given name or signature.
+func findFragment(fragments []*Fragment, kind string, name string) (*Fragment, error) {
+	isSignature := strings.Contains(name, "(")
+	if isSignature {
+		parsed, err := ParseFragment(name)
+		if err != nil {
+			return nil, err
+		}
+		name = parsed.Signature()
+	}
+
+	var found *Fragment
+	for _, fragment := range fragments {
+		if isSignature && fragment.Signature() == name {
+			return fragment, nil
+		}
+		if !isSignature && fragment.Name == name {
+			if found != nil {
+				return nil, fmt.Errorf("%s %s is overloaded, use its signature instead (i.e. %s)", kind, name, found.Signature())
+			}
+			found = fragment
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%s %s not found", kind, name)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,179 @@
+package abi_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/omnes-tech/abi"
+)
+
+const exampleERC20JSON = `[
+	{"type":"function","name":"transfer","stateMutability":"nonpayable",
+	 "inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],
+	 "outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view",
+	 "inputs":[{"name":"owner","type":"address"}],
+	 "outputs":[{"name":"balance","type":"uint256"}]},
+	{"type":"event","name":"Transfer","anonymous":false,
+	 "inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+func ExampleLoadJSON() {
+	contract, err := abi.LoadJSON(strings.NewReader(exampleERC20JSON))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	for _, method := range contract.Methods {
+		fmt.Println(method.Signature())
+	}
+	for _, event := range contract.Events {
+		fmt.Println(event)
+	}
+
+	// Output:
+	// transfer(address,uint256)
+	// balanceOf(address)
+	// event Transfer(address indexed from, address indexed to, uint256 value)
+}
+
+func ExampleContract_EncodeCall() {
+	contract, err := abi.LoadJSON(strings.NewReader(exampleERC20JSON))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	to := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	encoded, err := contract.EncodeCall("transfer", to, big.NewInt(1000))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(common.Bytes2Hex(encoded))
+
+	// Output: a9059cbb0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d278900000000000000000000000000000000000000000000000000000000000003e8
+}
+
+func ExampleContract_DecodeReturn() {
+	contract, err := abi.LoadJSON(strings.NewReader(exampleERC20JSON))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	data := common.Hex2Bytes("00000000000000000000000000000000000000000000000000000000000003e8")
+
+	var result struct {
+		Balance *big.Int `abi:"balance"`
+	}
+	err = contract.DecodeReturn("balanceOf", data, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(result.Balance)
+
+	// Output: 1000
+}
+
+func ExampleContract_DecodeEvent() {
+	contract, err := abi.LoadJSON(strings.NewReader(exampleERC20JSON))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	log := types.Log{
+		Topics: []common.Hash{
+			common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+			common.HexToHash("0x0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d2789"),
+			common.HexToHash("0x000000000000000000000000000000000000000000000000000000000000dead"),
+		},
+		Data: common.Hex2Bytes("00000000000000000000000000000000000000000000000000000000000003e8"),
+	}
+
+	var transfer struct {
+		From  common.Address `abi:"from"`
+		To    common.Address `abi:"to"`
+		Value *big.Int       `abi:"value"`
+	}
+	event, err := contract.DecodeEvent(log, &transfer)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(event.Name, transfer.From, transfer.To, transfer.Value)
+
+	// Output: Transfer 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 0x000000000000000000000000000000000000dEaD 1000
+}
+
+func ExampleContract_EncodeConstructor() {
+	contract, err := abi.LoadJSON(strings.NewReader(`[
+		{"type":"constructor","stateMutability":"nonpayable","inputs":[{"name":"name","type":"string"},{"name":"supply","type":"uint256"}]}
+	]`))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	bytecode := common.Hex2Bytes("6080604052")
+	deployData, err := contract.EncodeConstructor(bytecode, "Token", 1_000_000)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var args struct {
+		Name   string   `abi:"name"`
+		Supply *big.Int `abi:"supply"`
+	}
+	err = contract.DecodeConstructor(deployData, bytecode, &args)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(args.Name, args.Supply)
+
+	// Output: Token 1000000
+}
+
+func ExampleMergeContracts() {
+	implementation, _ := abi.LoadJSON(strings.NewReader(exampleERC20JSON))
+	facet, _ := abi.NewContract(
+		abi.MustParseFragment("function mint(address to, uint256 amount)"),
+		abi.MustParseFragment("event Transfer(address indexed from, address indexed to, uint256 value)"),
+	)
+
+	proxy, err := abi.MergeContracts(implementation, facet)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	calldata, _ := proxy.EncodeCall("mint", common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"), big.NewInt(1000))
+
+	var args struct {
+		To     common.Address `abi:"to"`
+		Amount *big.Int       `abi:"amount"`
+	}
+	method, err := proxy.DecodeCalldata(calldata, &args)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(method.Name, args.To.Hex(), args.Amount, len(proxy.Events))
+
+	conflicting, _ := abi.NewContract(
+		abi.MustParseFragment("function transfer(address to, uint256 amount)"),
+		abi.MustParseFragment("event Transfer(address indexed from, address to, uint256 indexed value)"),
+	)
+	_, err = abi.MergeContracts(implementation, conflicting)
+	fmt.Println(errors.Is(err, abi.ErrSelectorCollision))
+	fmt.Println(err)
+
+	// Output:
+	// mint 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1000 1
+	// true
+	// selector collision: function 0xa9059cbb is defined as both "function transfer(address to, uint256 amount) returns (bool)" and "function transfer(address to, uint256 amount)"
+	// selector collision: event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef is defined as both "event Transfer(address indexed from, address indexed to, uint256 value)" and "event Transfer(address indexed from, address to, uint256 indexed value)"
+}
@@ -0,0 +1,249 @@
+package abi
+
+import (
+	"encoding"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxDecimals is the maximum number of decimals of a fixed point type
+// (i.e. `fixed128x80`), also bounding the `abi:",decimals=N"` tag.
+const maxDecimals = 80
+
+var (
+	bigRatType    = reflect.TypeOf(big.Rat{})
+	bigRatPtrType = reflect.TypeOf(&big.Rat{})
+
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isFixedType checks whether given type is a fixed point core type
+// (i.e. `ufixed128x18`).
+func isFixedType(typeStr string) bool {
+	return strings.HasPrefix(typeStr, "fixed") || strings.HasPrefix(typeStr, "ufixed")
+}
+
+// fixedParams returns the integer type holding the scaled values of a
+// fixed point type (i.e. int128 for fixed128x18) and its number of
+// decimals. `fixed` and `ufixed` are aliases for fixed128x18 and
+// ufixed128x18.
+func fixedParams(typeStr string) (string, int, error) {
+	if typeStr == "fixed" || typeStr == "ufixed" {
+		typeStr += "128x18"
+	}
+
+	match := fixedTypeRegexp.FindStringSubmatch(typeStr)
+	if match == nil {
+		return "", 0, fmt.Errorf("invalid fixed point type: %s", typeStr)
+	}
+	bits, _ := strconv.Atoi(match[1])
+	decimals, _ := strconv.Atoi(match[2])
+	if bits < 8 || bits > 256 || bits%8 != 0 || decimals > maxDecimals {
+		return "", 0, fmt.Errorf("invalid fixed point type: %s", typeStr)
+	}
+
+	intTypeStr := "int" + match[1]
+	if typeStr[0] == 'u' {
+		intTypeStr = "u" + intTypeStr
+	}
+
+	return intTypeStr, decimals, nil
+}
+
+// decimalsFactor returns 10^decimals.
+func decimalsFactor(decimals int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
+
+// scaleDecimals converts a decimal number into the integer it is scaled
+// to with given number of decimals (i.e. 1.5 into 1500000000000000000
+// with 18 decimals). *big.Float values, which can't hold most decimal
+// numbers exactly, are rounded to the nearest integer, while *big.Rat
+// values holding more decimals are rejected.
+func scaleDecimals(value any, decimals int) (*big.Int, error) {
+	var r *big.Rat
+	exact := true
+	switch value := value.(type) {
+	case *big.Rat:
+		r = value
+	case *big.Float:
+		if value.IsInf() {
+			return nil, fmt.Errorf("infinite value %s", value)
+		}
+		r, _ = value.Rat(nil)
+		exact = false
+	default:
+		return nil, fmt.Errorf("expected *big.Float or *big.Rat, got %T", value)
+	}
+
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(decimalsFactor(decimals)))
+	if scaled.IsInt() {
+		return new(big.Int).Set(scaled.Num()), nil
+	}
+	if exact {
+		return nil, fmt.Errorf("value %s has more than %d decimals", r.RatString(), decimals)
+	}
+
+	// Round half away from zero.
+	quotient, remainder := new(big.Int).QuoRem(new(big.Int).Abs(scaled.Num()), scaled.Denom(), new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(scaled.Denom()) >= 0 {
+		quotient.Add(quotient, one)
+	}
+	if scaled.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+
+	return quotient, nil
+}
+
+// unscaleDecimals converts an integer scaled with given number of
+// decimals into the decimal number it holds.
+func unscaleDecimals(scaled *big.Int, decimals int) *big.Rat {
+	return new(big.Rat).SetFrac(scaled, decimalsFactor(decimals))
+}
+
+// newDecimalFloat returns the *big.Float decoded for a decimal number,
+// with a precision of 256 bits.
+func newDecimalFloat(r *big.Rat) *big.Float {
+	return new(big.Float).SetPrec(256).SetRat(r)
+}
+
+// decimalRat converts a decoded fixed point number into the decimal
+// number it holds. Decoded *big.Float values are the nearest binary
+// numbers of decimal ones, which are given by their shortest decimal
+// representation.
+func decimalRat(value any) (*big.Rat, error) {
+	switch value := value.(type) {
+	case *big.Rat:
+		return new(big.Rat).Set(value), nil
+	case *big.Int:
+		return new(big.Rat).SetInt(value), nil
+	case *big.Float:
+		if value.IsInf() {
+			return nil, fmt.Errorf("infinite value %s", value)
+		}
+		r, ok := new(big.Rat).SetString(value.Text('f', -1))
+		if !ok {
+			return nil, fmt.Errorf("invalid decimal number %s", value)
+		}
+		return r, nil
+	}
+
+	return nil, fmt.Errorf("expected fixed point number, got %T", value)
+}
+
+// setBigRat sets a decoded fixed point number, or integer, into a
+// *big.Rat target.
+func setBigRat(_ *parseState, target reflect.Value, value any, _ []Component) error {
+	r, err := decimalRat(value)
+	if err != nil {
+		return err
+	}
+	target.Set(reflect.ValueOf(r))
+
+	return nil
+}
+
+// isDecimalType checks whether fields of type t can be tagged with
+// `abi:",decimals=N"`: *big.Rat, *big.Float and decimal types
+// implementing encoding.TextMarshaler and encoding.TextUnmarshaler
+// (i.e. decimal.Decimal of github.com/shopspring/decimal).
+func isDecimalType(t reflect.Type) bool {
+	if t == bigRatPtrType || t == bigFloatPtrType {
+		return true
+	}
+
+	return (t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) &&
+		(t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType))
+}
+
+// newDecimalsSetter builds the setter of a field tagged with
+// `abi:",decimals=N"`, which receives decoded integers divided by 10^N.
+// Decimal types implementing encoding.TextUnmarshaler receive the exact
+// decimal representation of the number (i.e. "1.500000000000000000").
+func newDecimalsSetter(t reflect.Type, decimals int) (setter, error) {
+	if !isDecimalType(t) {
+		return nil, fmt.Errorf("decimal fields must be *big.Rat, *big.Float or implement encoding.TextMarshaler and encoding.TextUnmarshaler, got %s", t)
+	}
+
+	return func(s *parseState, target reflect.Value, value any, _ []Component) error {
+		if value == nil {
+			if s.opts.AllowNil {
+				return nil
+			}
+			return newParseError(fmt.Errorf("nil decoded value for %s", t), t, value)
+		}
+
+		scaled, ok := value.(*big.Int)
+		if !ok {
+			return newParseError(newMismatchError("integer for decimal field", value), t, value)
+		}
+		r := unscaleDecimals(scaled, decimals)
+
+		switch {
+		case t == bigRatPtrType:
+			target.Set(reflect.ValueOf(r))
+			return nil
+		case t == bigFloatPtrType:
+			target.Set(reflect.ValueOf(newDecimalFloat(r)))
+			return nil
+		}
+
+		if t.Kind() == reflect.Ptr && target.IsNil() {
+			target.Set(reflect.New(t.Elem()))
+		}
+		unmarshaler, ok := target.Interface().(encoding.TextUnmarshaler)
+		if !ok {
+			unmarshaler = target.Addr().Interface().(encoding.TextUnmarshaler)
+		}
+		err := unmarshaler.UnmarshalText([]byte(r.FloatString(decimals)))
+		if err != nil {
+			return newParseError(err, t, value)
+		}
+
+		return nil
+	}, nil
+}
+
+// marshalDecimals converts a field tagged with `abi:",decimals=N"` into
+// the integer it is scaled to.
+func marshalDecimals(rv reflect.Value, decimals int) (any, error) {
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return nil, fmt.Errorf("[marshalDecimals] nil decimal value")
+	}
+
+	var value any
+	switch {
+	case rv.Type() == bigRatPtrType || rv.Type() == bigFloatPtrType:
+		value = rv.Interface()
+	default:
+		marshaler, ok := rv.Interface().(encoding.TextMarshaler)
+		if !ok && rv.CanAddr() {
+			marshaler, ok = rv.Addr().Interface().(encoding.TextMarshaler)
+		}
+		if !ok {
+			return nil, fmt.Errorf("[marshalDecimals] cannot marshal %s as a decimal number", rv.Type())
+		}
+
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("[marshalDecimals] %w", err)
+		}
+		r, ok := new(big.Rat).SetString(string(text))
+		if !ok {
+			return nil, fmt.Errorf("[marshalDecimals] invalid decimal number %q", text)
+		}
+		value = r
+	}
+
+	scaled, err := scaleDecimals(value, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("[marshalDecimals] %w", err)
+	}
+
+	return scaled, nil
+}
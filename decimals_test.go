@@ -0,0 +1,85 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/omnes-tech/abi"
+)
+
+type exampleBalance struct {
+	Owner   string
+	Balance *big.Rat   `abi:",decimals=6"`
+	Rate    *big.Float `abi:",decimals=18"`
+}
+
+func ExampleParse_decimals() {
+	typeStrs := []string{"address", "uint256", "int256"}
+	encoded, err := abi.Encode(
+		typeStrs,
+		"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789",
+		big.NewInt(1234500000),
+		big.NewInt(-25e16),
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var balance exampleBalance
+	err = abi.Parse(decoded, &balance)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(balance.Balance.FloatString(6), balance.Rate)
+
+	// Marshal scales the decimal numbers back into integers.
+	balance.Balance.SetString("0.5")
+	marshaled, err := abi.Marshal(balance, "(address,uint256,int256)")
+	if err != nil {
+		fmt.Println(err)
+	}
+	values, err := abi.Decode(typeStrs, marshaled)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(values[1], values[2])
+
+	// Output:
+	// 1234.500000 -0.25
+	// 500000 -250000000000000000
+}
+
+func ExampleEncode_fixed() {
+	price, _ := new(big.Float).SetString("-12.75")
+	encoded, err := abi.Encode([]string{"fixed128x18", "ufixed64x2"}, price, big.NewRat(3, 2))
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%x\n", encoded[:32])
+
+	decoded, err := abi.Decode([]string{"fixed128x18", "ufixed64x2"}, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(decoded)
+
+	var result struct {
+		Price  *big.Float
+		Amount *big.Rat
+	}
+	err = abi.Parse(decoded, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(result.Price, result.Amount)
+
+	// Output:
+	// ffffffffffffffffffffffffffffffffffffffffffffffff4f0ee68d69c50000
+	// [-12.75 1.5]
+	// -12.75 3/2
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -12,6 +13,9 @@ import (
 
 // DecodeWithSelector decodes bytecode restricted to given selector.
 func DecodeWithSelector(selector []byte, typeStrs []string, data []byte) ([]any, error) {
+	if len(data) < 4 {
+		return []any{}, fmt.Errorf("data too short to contain a selector")
+	}
 	if !isSelectorIsEqual(selector, data[:4]) {
 		return []any{}, fmt.Errorf("invalid selector")
 	}
@@ -27,6 +31,9 @@ func DecodeWithSignature(funcSignature string, data []byte) ([]any, error) {
 	}
 
 	selector := EncodeSignature(funcSignature)
+	if len(data) < 4 {
+		return []any{}, fmt.Errorf("data too short to contain a selector")
+	}
 	if !isSelectorIsEqual(selector, data[:4]) {
 		return []any{}, fmt.Errorf("invalid selector")
 	}
@@ -34,38 +41,147 @@ func DecodeWithSignature(funcSignature string, data []byte) ([]any, error) {
 	return Decode(typeStrs, data[4:])
 }
 
+// DecodeReturn decodes the return data of a call to the function with
+// given signature, whose `returns (...)` part lists the outputs (i.e.
+// `function balanceOf(address owner) view returns (uint256)`), and
+// parses it into the value pointed by out. Outputs may also be given
+// alone (i.e. `(uint256 balance, bool ok)`) or after the inputs (i.e.
+// `balanceOf(address)(uint256)`).
+//
+// Several outputs are parsed into a struct as done by Parse, named
+// outputs being mapped to fields tagged with `abi:"name"`. A single
+// output can be parsed into any supported type (i.e. *big.Int, bool or
+// common.Address), and a single tuple output into a struct holding its
+// members.
+func DecodeReturn(sig string, data []byte, out any) error {
+	outputs, err := returnComponents(sig)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := Decode(ComponentTypes(outputs), data)
+	if err != nil {
+		return fmt.Errorf("error decoding return data of %s: %w", sig, err)
+	}
+
+	return parseReturn(decoded, outputs, out)
+}
+
+// returnComponents returns the outputs listed in given signature.
+func returnComponents(sig string) ([]Component, error) {
+	sig = strings.TrimSpace(sig)
+	if strings.HasPrefix(sig, "(") && matchingParenthesis(sig, 0) == len(sig)-1 {
+		return parseParams(sig[1 : len(sig)-1])
+	}
+
+	// Outputs following the inputs, as in `balanceOf(address)(uint256)`.
+	if open := strings.Index(sig, "("); open != -1 && !strings.Contains(sig, "returns") {
+		if end := matchingParenthesis(sig, open); end != -1 && strings.HasPrefix(strings.TrimSpace(sig[end+1:]), "(") {
+			sig = sig[:end+1] + " returns " + strings.TrimSpace(sig[end+1:])
+		}
+	}
+
+	fragment, err := ParseFragment(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return fragment.Outputs, nil
+}
+
+// parseReturn parses decoded outputs into the value pointed by out,
+// which is a struct holding the outputs, or the target of a single
+// output.
+func parseReturn(decoded []any, outputs []Component, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("out must be a non-nil pointer, got %T", out)
+	}
+
+	if _, ok := out.(ABIUnmarshaler); ok || len(outputs) != 1 {
+		return ParseWithComponents(decoded, outputs, out)
+	}
+
+	// Structs hold the outputs, but for single tuples of several members.
+	t := rv.Elem().Type()
+	isStruct := t.Kind() == reflect.Struct && !isCoreStruct(t)
+	if isStruct && (!strings.HasPrefix(outputs[0].Type, "tuple") || structPlanFor(t).numValues == 1) {
+		return ParseWithComponents(decoded, outputs, out)
+	}
+
+	return setterFor(t)(&parseState{}, rv.Elem(), decoded[0], outputs[0].Components)
+}
+
+// DecodeConstructor decodes the constructor arguments appended to the
+// creation bytecode of a contract in given deployment data (i.e. the
+// input of its creation transaction).
+func DecodeConstructor(deployData []byte, bytecode []byte, typeStrs []string) ([]any, error) {
+	if !bytes.HasPrefix(deployData, bytecode) {
+		return []any{}, fmt.Errorf("deployment data does not start with given bytecode")
+	}
+
+	return Decode(typeStrs, deployData[len(bytecode):])
+}
+
 // DecodePacked decodes bytecode following packed format.
-// It supports only one dynamic type (either string or bytes)
-// as last item in typeStrs array.
+// It supports only one dynamic type (either string, bytes or an
+// unbounded array) as last item in typeStrs array. Arrays must hold
+// core static types, whose elements are padded to 32 bytes as encoded
+// by EncodePacked.
 func DecodePacked(typeStrs []string, data []byte) ([]any, error) {
 	var result []any
 	var byteCursor uint64
 	for i, typeStr := range typeStrs {
 		isTypeDynamic := IsDynamic(typeStr, false)
-		isTypeArray, _, err := IsArray(typeStr)
+		isTypeArray, arraySize, err := IsArray(typeStr)
 		if err != nil {
 			return []any{}, err
 		}
 
-		if isTypeDynamic && !isTypeArray && i != len(typeStrs)-1 {
+		if isTypeDynamic && i != len(typeStrs)-1 {
 			return []any{}, fmt.Errorf("supports only one dynamic type as last type")
 		}
 
-		if typeStr[:3] == "int" {
-			if len(typeStr) == 3 {
-				typeStr += "256"
+		if isTypeArray {
+			if arraySize == 0 {
+				if len(data[byteCursor:])%32 != 0 {
+					return []any{}, fmt.Errorf("data length is not a multiple of 32 bytes to decode %s at position %d", typeStr, byteCursor)
+				}
+				arraySize = len(data[byteCursor:]) / 32
 			}
-		} else {
-			if len(typeStr) == 4 {
-				typeStr += "256"
+			if uint64(arraySize) > (uint64(len(data))-byteCursor)/32 {
+				return []any{}, fmt.Errorf("data too short to decode %s at position %d", typeStr, byteCursor)
+			}
+
+			val, err := decodePackedArray(typeStr, arraySize, data[byteCursor:byteCursor+uint64(arraySize)*32])
+			if err != nil {
+				return []any{}, err
 			}
+
+			result = append(result, val)
+			byteCursor += uint64(arraySize) * 32
+			continue
+		}
+
+		if typeStr == "int" || typeStr == "uint" {
+			typeStr += "256"
 		}
 		var byteLength int
 		if !isTypeDynamic {
 			byteLength = validCoreTypes[typeStr].ByteLength
+			if isFixedType(typeStr) {
+				intTypeStr, _, err := fixedParams(typeStr)
+				if err != nil {
+					return []any{}, err
+				}
+				byteLength = validCoreTypes[intTypeStr].ByteLength
+			}
 		} else {
 			byteLength = len(data[byteCursor:])
 		}
+		if byteCursor+uint64(byteLength) > uint64(len(data)) {
+			return []any{}, fmt.Errorf("data too short to decode %s at position %d", typeStr, byteCursor)
+		}
 
 		val, err := decodePacked(typeStr, data[byteCursor:byteCursor+uint64(byteLength)])
 		if err != nil {
@@ -86,70 +202,94 @@ func DecodePacked(typeStrs []string, data []byte) ([]any, error) {
 
 // Decode decodes bytecode to given type strings
 func Decode(typeStrs []string, data []byte) ([]any, error) {
+	return decodeValues(&limitState{}, typeStrs, data, 0)
+}
 
+// DecodeWithOptions decodes bytecode to given type strings, returning
+// an error wrapping ErrLimitExceeded when the decoded values exceed the
+// limits of given options.
+func DecodeWithOptions(typeStrs []string, data []byte, opts Options) ([]any, error) {
+	return decodeValues(&limitState{limits: opts.Limits}, typeStrs, data, 0)
+}
+
+// decodeValues decodes bytecode to given type strings, holding values
+// nested at given depth.
+func decodeValues(s *limitState, typeStrs []string, data []byte, depth int) ([]any, error) {
 	var result []any
-	var byteCursor uint64
+	var byteCursor int
 	for _, typeStr := range typeStrs {
 
-		isTypeTuple, splitedTypes, err := IsTuple(typeStr)
+		isTypeTuple, splitedTypes, err := tupleType(typeStr)
 		if err != nil {
 			return []any{}, err
 		}
 
-		var offset int
-		var init int
-		var end int
-		isTypeDynamic := IsDynamic(typeStr, isTypeTuple)
-		if isTypeDynamic {
-			offset = int(new(big.Int).SetBytes(data[byteCursor : byteCursor+32]).Uint64())
-
-			var dynamicSize int
-			init = offset
-			if !isTypeTuple {
-				dynamicSize = int(new(big.Int).SetBytes(data[offset : offset+32]).Uint64())
-				end = offset + 32 + dynamicSize
-			} else {
-				end = offset + 32
-			}
-
-		} else if isTypeTuple {
-			init = int(byteCursor)
-			end = int(byteCursor + uint64(32*len(splitedTypes)))
-		} else {
-			init = int(byteCursor)
-			end = int(byteCursor + 32)
+		isTypeArray, givenArraySize, err := IsArray(typeStr)
+		if err != nil {
+			return []any{}, err
 		}
 
-		isTypeArray, givenArraySize, err := IsArray(typeStr)
+		size, err := headSize(typeStr)
 		if err != nil {
 			return []any{}, err
 		}
+		if byteCursor+size > len(data) {
+			return []any{}, fmt.Errorf("data too short to decode %s at position %d", typeStr, byteCursor)
+		}
+
+		// Dynamic values are stored at the offset found in the head,
+		// static values are stored inline.
+		segment := data[byteCursor : byteCursor+size]
+		if IsDynamic(typeStr, isTypeTuple) {
+			offset := new(big.Int).SetBytes(data[byteCursor : byteCursor+32])
+			if !offset.IsUint64() || offset.Uint64() > uint64(len(data)) {
+				return []any{}, fmt.Errorf("invalid offset %s for %s", offset, typeStr)
+			}
+			segment = data[offset.Uint64():]
+		}
+
+		if isTypeArray || isTypeTuple {
+			err := s.checkDepth(depth + 1)
+			if err != nil {
+				return []any{}, err
+			}
+		}
 
 		if isTypeArray {
-			var innerData []byte
-			var arraySize int
-			if givenArraySize != 0 {
-				offset = int(new(big.Int).SetBytes(data[byteCursor : byteCursor+32]).Uint64())
-				arraySize = givenArraySize
-				innerData = data[offset : offset+32*givenArraySize]
-			} else {
-				arraySize = int(new(big.Int).SetBytes(data[offset : offset+32]).Uint64())
-				// if isTypeTuple {
-				// 	innerData = data[offset+32 : offset+32*(arraySize*len(splitedTypes)+1)]
-				// } else {
-				// 	innerData = data[offset+32 : offset+32*(arraySize+1)]
-				// }
-				innerData = data[offset+32:]
+			arraySize := givenArraySize
+			innerData := segment
+			if givenArraySize == 0 {
+				if len(segment) < 32 {
+					return []any{}, fmt.Errorf("data too short to decode length of %s", typeStr)
+				}
+				length := new(big.Int).SetBytes(segment[:32])
+				if !length.IsUint64() || length.Uint64() > uint64(len(segment)) {
+					return []any{}, fmt.Errorf("invalid length %s for %s", length, typeStr)
+				}
+				arraySize = int(length.Uint64())
+				innerData = segment[32:]
 			}
 
 			typeStr = typeStr[:strings.LastIndex(typeStr, "[")]
 
-			var arrayTypeStrs []string
-			for j := 0; j < arraySize; j++ {
-				arrayTypeStrs = append(arrayTypeStrs, typeStr)
+			elemSize, err := headSize(typeStr)
+			if err != nil {
+				return []any{}, err
+			}
+			if elemSize > 0 && arraySize > len(innerData)/elemSize {
+				return []any{}, fmt.Errorf("data too short to decode %d elements of %s", arraySize, typeStr)
+			}
+			err = s.addElements(arraySize)
+			if err != nil {
+				return []any{}, err
+			}
+
+			arrayTypeStrs := make([]string, arraySize)
+			for j := range arrayTypeStrs {
+				arrayTypeStrs[j] = typeStr
 			}
 
-			innerResult, err := Decode(arrayTypeStrs, innerData)
+			innerResult, err := decodeValues(s, arrayTypeStrs, innerData, depth+1)
 			if err != nil {
 				return []any{}, err
 			}
@@ -157,19 +297,19 @@ func Decode(typeStrs []string, data []byte) ([]any, error) {
 			result = append(result, innerResult)
 
 		} else if isTypeTuple {
-			var innerResult []any
-			if isTypeDynamic {
-				innerResult, err = Decode(splitedTypes, data[init:])
-			} else {
-				innerResult, err = Decode(splitedTypes, data[init:end])
+			err := s.addElements(len(splitedTypes))
+			if err != nil {
+				return []any{}, err
 			}
+
+			innerResult, err := decodeValues(s, splitedTypes, segment, depth+1)
 			if err != nil {
 				return []any{}, err
 			}
 
 			result = append(result, innerResult)
 		} else {
-			val, err := decode(typeStr, data[init:end])
+			val, err := decode(s, typeStr, segment)
 			if err != nil {
 				return []any{}, err
 			}
@@ -177,26 +317,30 @@ func Decode(typeStrs []string, data []byte) ([]any, error) {
 			result = append(result, val)
 		}
 
-		if isTypeTuple {
-			byteCursor += uint64(end - init)
-		} else {
-			byteCursor += 32
-		}
+		byteCursor += size
 	}
 
-	// fmt.Println("result", result)
-
 	return result, nil
 }
 
 // decode decodes give bytecode slice to specified type.
-func decode(typeStr string, data []byte) (any, error) {
+func decode(s *limitState, typeStr string, data []byte) (any, error) {
 	var decoded any
 	var err error
 	if typeStr == "string" || typeStr == "bytes" {
+		if len(data) < 32 {
+			return nil, fmt.Errorf("data byte size is too short for %v. Length: %d", typeStr, len(data))
+		}
 		byteLengthBigInt := new(big.Int)
 		byteLength := data[:32]
 		byteLengthBigInt.SetBytes(byteLength)
+		if !byteLengthBigInt.IsUint64() || byteLengthBigInt.Uint64() > uint64(len(data)-32) {
+			return nil, fmt.Errorf("invalid length %s for %v", byteLengthBigInt, typeStr)
+		}
+		err = s.checkBytesLength(byteLengthBigInt.Uint64())
+		if err != nil {
+			return nil, err
+		}
 
 		decoded, err = decodePacked(typeStr, data[32:32+byteLengthBigInt.Uint64()])
 		if err != nil {
@@ -212,6 +356,35 @@ func decode(typeStr string, data []byte) (any, error) {
 	return decoded, nil
 }
 
+// decodePackedArray decodes the elements of a packed array of core
+// static types, each padded to 32 bytes. Fixed bytes elements are
+// returned as hex strings, like the values returned by DecodePacked.
+func decodePackedArray(typeStr string, length int, data []byte) ([]any, error) {
+	elemTypeStr := arrayElemType(typeStr)
+	plan := typePlanFor(elemTypeStr)
+	if plan.isArray || plan.isTuple || plan.dynamic {
+		return nil, fmt.Errorf("packed arrays of %s are not supported", elemTypeStr)
+	}
+
+	elemTypeStrs := make([]string, length)
+	for i := range elemTypeStrs {
+		elemTypeStrs[i] = elemTypeStr
+	}
+	elems, err := Decode(elemTypeStrs, data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, elem := range elems {
+		if b, ok := elem.([]byte); ok {
+			size, _ := strconv.Atoi(strings.TrimPrefix(elemTypeStr, "bytes"))
+			elems[i] = common.Bytes2Hex(b[:size])
+		}
+	}
+
+	return elems, nil
+}
+
 // decodePacked decodes bytecode slice to given type considering
 // packed format.
 func decodePacked(typeStr string, data []byte) (any, error) {
@@ -232,10 +405,16 @@ func decodePacked(typeStr string, data []byte) (any, error) {
 		return data[len(data)-1] == 1, nil
 	case "string": // @follow-up check this later
 		return string(data), nil
+	case "function":
+		if len(data) < validCoreTypes[typeStr].ByteLength {
+			return nil, fmt.Errorf("data byte size is too short for %v. Length: %d", typeStr, len(data))
+		}
+
+		return functionPointerFromBytes(data)
 	default:
-		if typeStr[:3] == "int" || typeStr[:4] == "uint" {
+		if strings.HasPrefix(typeStr, "int") || strings.HasPrefix(typeStr, "uint") {
 			var index int
-			if typeStr[:3] == "int" {
+			if strings.HasPrefix(typeStr, "int") {
 				index = 3
 				if len(typeStr) == 3 {
 					typeStr += "256"
@@ -254,26 +433,28 @@ func decodePacked(typeStr string, data []byte) (any, error) {
 			if bits%8 != 0 {
 				return []byte{}, fmt.Errorf("invalid bits value: %v, bits = %v", typeStr, bits)
 			}
+			if _, ok := validCoreTypes[typeStr]; !ok {
+				return nil, fmt.Errorf("invalid parameter type: %v", typeStr)
+			}
 
 			if len(data) < validCoreTypes[typeStr].ByteLength {
 				return nil, fmt.Errorf("data byte size is too short for %v. Length: %d", typeStr, len(data))
 			}
 
-			decoded := new(big.Int)
-			if typeStr[:3] == "int" {
-				relevantData := data[len(data)-bits/8:]
-				if (relevantData[0] & 0x80) != 0 {
-					allOnes := new(big.Int).SetBytes(bytes.Repeat([]byte{0xff}, bits/8))
-					decoded.SetBytes(relevantData)
-					decoded.Xor(decoded, allOnes)
-					decoded.Add(decoded, big.NewInt(1))
-					decoded.Neg(decoded)
-					return decoded, nil
+			decoded := new(big.Int).SetBytes(data)
+			if strings.HasPrefix(typeStr, "int") {
+				// Signed integers are the two's complement of the whole
+				// word, which must be sign extended from their bits.
+				if data[0]&0x80 != 0 {
+					decoded.Sub(decoded, new(big.Int).Lsh(one, uint(len(data)*8)))
+				}
+				if decoded.Cmp(validCoreTypes[typeStr].Max) == 1 || decoded.Cmp(validCoreTypes[typeStr].Min) == -1 {
+					return nil, fmt.Errorf("invalid %s value %s: not sign extended from %d bits", typeStr, decoded, bits)
 				}
 			}
 
-			return decoded.SetBytes(data), nil
-		} else if typeStr[:5] == "bytes" {
+			return decoded, nil
+		} else if strings.HasPrefix(typeStr, "bytes") {
 			if len(typeStr) > 5 {
 				bytesSize, err := strconv.Atoi(typeStr[5:])
 				if err != nil {
@@ -290,42 +471,18 @@ func decodePacked(typeStr string, data []byte) (any, error) {
 			}
 
 			return data, nil
-		} else if typeStr[:5] == "fixed" || typeStr[:6] == "ufixed" { // @note differences in result with fixed/ufixed types
-			var index int
-			if typeStr[:5] == "fixed" {
-				index = 5
-				if len(typeStr) == 5 {
-					typeStr += "128x18"
-				}
-			} else {
-				index = 6
-				if len(typeStr) == 6 {
-					typeStr += "128x18"
-				}
-			}
-
-			sizes := strings.Split(typeStr[index:], "x")
-			bits, err := strconv.Atoi(sizes[0])
+		} else if isFixedType(typeStr) {
+			intTypeStr, decimals, err := fixedParams(typeStr)
 			if err != nil {
-				return []byte{}, fmt.Errorf("error getting bits from %s: %v", typeStr, err)
+				return nil, err
 			}
 
-			if bits%8 != 0 {
-				return []byte{}, fmt.Errorf("invalid bits value: %v, bits = %v", typeStr, bits)
-			}
-
-			if len(data) < bits/8 {
-				return nil, fmt.Errorf("data byte size is too short for %v. Length: %d", typeStr, len(data))
-			}
-
-			decoded := new(big.Float)
-			converted, ok := decoded.SetString(string(data))
-			if !ok {
-				return nil, fmt.Errorf("error converting to big.Float: %v", data)
+			scaled, err := decodePacked(intTypeStr, data)
+			if err != nil {
+				return nil, err
 			}
 
-			return converted, nil
-
+			return newDecimalFloat(unscaleDecimals(scaled.(*big.Int), decimals)), nil
 		} else {
 			return nil, fmt.Errorf("invalid parameter type: %v", typeStr)
 		}
@@ -2,6 +2,7 @@ package abi_test
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/omnes-tech/abi"
@@ -78,6 +79,47 @@ func ExampleDecodePacked() {
 	// Output: [0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 100 61726269747261727920627974652061727261792e2e2e]
 }
 
+func ExampleDecodePacked_array() {
+	encoded, err := abi.EncodePacked(
+		[]string{"uint16", "bytes2[2]", "address[]"},
+		uint16(513),
+		[]any{[]byte{0xca, 0xfe}, []byte{0xbe, 0xef}},
+		[]common.Address{common.HexToAddress("0x000000000000000000000000000000000000dEaD")},
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Array elements are padded to 32 bytes.
+	fmt.Println(len(encoded))
+
+	decoded, err := abi.DecodePacked([]string{"uint16", "bytes2[2]", "address[]"}, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(decoded)
+
+	// Output:
+	// 98
+	// [513 [cafe beef] [0x000000000000000000000000000000000000dEaD]]
+}
+
+func ExampleDecodePacked_bool() {
+	typeStrs := []string{"bool", "uint", "bool"}
+	encoded, err := abi.EncodePacked(typeStrs, true, big.NewInt(7), false)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.DecodePacked(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(len(encoded), decoded)
+
+	// Output: 34 [true 7 false]
+}
+
 func ExampleDecodeWithSignature() {
 	encoded := common.Hex2Bytes("c6210dba0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d2789000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000e00000000000000000000000000000000000000000000000000000000000000120000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000640000000000000000000000000000000000000000000000000000000000000160000000000000000000000000000000000000000000000000000000000000001761726269747261727920627974652061727261792e2e2e0000000000000000000000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000001400000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d2789000000000000000000000000000000000000000000000000000000000000006000000000000000000000000000000000000000000000000000000000000000c0000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000640000000000000000000000000000000000000000000000000000000000000160000000000000000000000000000000000000000000000000000000000000001761726269747261727920627974652061727261792e2e2e0000000000000000000000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d2789000000000000000000000000000000000000000000000000000000000000006000000000000000000000000000000000000000000000000000000000000000c0000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000640000000000000000000000000000000000000000000000000000000000000160000000000000000000000000000000000000000000000000000000000000001761726269747261727920627974652061727261792e2e2e000000000000000000")
 
@@ -130,3 +172,74 @@ func ExampleDecodeWithSelector() {
 
 	// Output: [0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 [100 352] [97 114 98 105 116 114 97 114 121 32 98 121 116 101 32 97 114 114 97 121 46 46 46] [[0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 [100 352] [97 114 98 105 116 114 97 114 121 32 98 121 116 101 32 97 114 114 97 121 46 46 46]] [0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 [100 352] [97 114 98 105 116 114 97 114 121 32 98 121 116 101 32 97 114 114 97 121 46 46 46]]]]
 }
+
+func ExampleDecodeReturn() {
+	data, _ := abi.Encode([]string{"uint256"}, big.NewInt(1000))
+
+	var balance *big.Int
+	err := abi.DecodeReturn("function balanceOf(address owner) view returns (uint256)", data, &balance)
+	fmt.Println(balance, err)
+
+	var ok bool
+	data, _ = abi.Encode([]string{"bool"}, true)
+	_ = abi.DecodeReturn("transfer(address,uint256)(bool)", data, &ok)
+	fmt.Println(ok)
+
+	var reserves struct {
+		Reserve0 *big.Int `abi:"reserve0"`
+		Reserve1 *big.Int `abi:"reserve1"`
+		Updated  uint32   `abi:"blockTimestampLast"`
+	}
+	data, _ = abi.Encode([]string{"uint112", "uint112", "uint32"}, big.NewInt(5), big.NewInt(7), big.NewInt(1700000000))
+	_ = abi.DecodeReturn("(uint112 reserve0, uint112 reserve1, uint32 blockTimestampLast)", data, &reserves)
+	fmt.Println(reserves.Reserve0, reserves.Reserve1, reserves.Updated)
+
+	var slot struct {
+		SqrtPriceX96 *big.Int
+		Tick         int32
+	}
+	data, _ = abi.Encode([]string{"(uint160,int24)"}, []any{big.NewInt(42), big.NewInt(9)})
+	_ = abi.DecodeReturn("function slot() returns ((uint160 sqrtPriceX96, int24 tick))", data, &slot)
+	fmt.Println(slot.SqrtPriceX96, slot.Tick)
+
+	// Output:
+	// 1000 <nil>
+	// true
+	// 5 7 1700000000
+	// 42 9
+}
+
+func ExampleDecode_signed() {
+	typeStrs := []string{"int8", "int32", "int256"}
+	encoded, err := abi.EncodeAppend(nil, typeStrs, int8(-5), big.NewInt(-70000), big.NewInt(-1))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(decoded)
+
+	var values struct {
+		Small  int8
+		Medium int64
+		Large  *big.Int
+	}
+	err = abi.Parse(decoded, &values)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(values.Small, values.Medium, values.Large)
+
+	// Negative int8 values are sign extended to the whole word, so that
+	// a word holding 0xfb in its last byte only is rejected.
+	_, err = abi.Decode([]string{"int8"}, common.LeftPadBytes([]byte{0xfb}, 32))
+	fmt.Println(err)
+
+	// Output:
+	// [-5 -70000 -1]
+	// -5 -70000 -1
+	// invalid int8 value 251: not sign extended from 8 bits
+}
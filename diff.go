@@ -0,0 +1,300 @@
+package abi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChangeKind is the kind of a Change reported by Diff.
+type ChangeKind int
+
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeRemoved
+	ChangeModified
+)
+
+// String implements the fmt.Stringer interface.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	default:
+		return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Change is a fragment added, removed or modified between two versions
+// of a contract ABI.
+type Change struct {
+	Kind ChangeKind
+	Old  *Fragment // the old fragment, nil when added
+	New  *Fragment // the new fragment, nil when removed
+
+	// Details lists the differences of a modified fragment, i.e.
+	// `selector changed from 0xa9059cbb to 0x1b4c27a6` or
+	// `input order.amount type changed from uint256 to uint128`.
+	Details []string
+	// Breaking is set when data encoded with one version can't be
+	// decoded with the other, or when the fragment was removed. Renamed
+	// parameters are not breaking, unless parsed into fields tagged with
+	// their name.
+	Breaking bool
+}
+
+// Report lists the changes found by Diff.
+type Report struct {
+	Changes []Change
+}
+
+// Breaking checks whether any change of the report is breaking.
+func (r Report) Breaking() bool {
+	for _, change := range r.Changes {
+		if change.Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String describes the changes of the report, one per line.
+func (r Report) String() string {
+	var b strings.Builder
+	for _, change := range r.Changes {
+		fragment := change.New
+		if fragment == nil {
+			fragment = change.Old
+		}
+
+		fmt.Fprintf(&b, "%s %s %s", change.Kind, fragment.Type, diffName(fragment))
+		if change.Breaking {
+			b.WriteString(" (breaking)")
+		}
+		b.WriteByte('\n')
+		for _, detail := range change.Details {
+			b.WriteString("  " + detail + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// Diff compares two versions of a contract ABI and reports the added,
+// removed and modified functions, events and errors, along with the
+// constructor, fallback and receive functions. Fragments are matched by
+// signature then, when a name is left with a single fragment in both
+// versions, by name. Modified fragments detail their selector (or
+// topic) changes and the parameters whose types, tuple layouts, indexed
+// flags or names changed. Changes are sorted by fragment type and name.
+func Diff(oldABI, newABI *Contract) Report {
+	var report Report
+	for _, kind := range []string{"constructor", "function", "event", "error", "fallback", "receive"} {
+		report.Changes = append(report.Changes, diffFragments(diffFragmentsOf(oldABI, kind), diffFragmentsOf(newABI, kind))...)
+	}
+
+	return report
+}
+
+// diffFragmentsOf returns the fragments of given type of a contract.
+func diffFragmentsOf(c *Contract, kind string) []*Fragment {
+	var single *Fragment
+	switch kind {
+	case "function":
+		return c.Methods
+	case "event":
+		return c.Events
+	case "error":
+		return c.Errors
+	case "constructor":
+		single = c.Constructor
+	case "fallback":
+		single = c.Fallback
+	case "receive":
+		single = c.Receive
+	}
+	if single == nil {
+		return nil
+	}
+
+	return []*Fragment{single}
+}
+
+// diffName names a fragment in a report: its signature, or its type for
+// constructor, fallback and receive functions.
+func diffName(fragment *Fragment) string {
+	switch fragment.Type {
+	case "fallback", "receive":
+		return "function"
+	case "constructor":
+		return "(" + strings.Join(fragment.InputTypes(), ",") + ")"
+	}
+
+	return fragment.Signature()
+}
+
+// diffFragments matches and compares the fragments of a single type.
+func diffFragments(oldFragments, newFragments []*Fragment) []Change {
+	var changes []Change
+	matchedOld := make(map[*Fragment]bool)
+	matchedNew := make(map[*Fragment]bool)
+
+	match := func(key func(*Fragment) string, unique bool) {
+		byKey := make(map[string][]*Fragment)
+		for _, fragment := range newFragments {
+			if !matchedNew[fragment] {
+				byKey[key(fragment)] = append(byKey[key(fragment)], fragment)
+			}
+		}
+		oldCount := make(map[string]int)
+		for _, fragment := range oldFragments {
+			if !matchedOld[fragment] {
+				oldCount[key(fragment)]++
+			}
+		}
+
+		for _, old := range oldFragments {
+			candidates := byKey[key(old)]
+			if matchedOld[old] || len(candidates) == 0 || (unique && (len(candidates) != 1 || oldCount[key(old)] != 1)) {
+				continue
+			}
+
+			fragment := candidates[0]
+			byKey[key(old)] = candidates[1:]
+			matchedOld[old], matchedNew[fragment] = true, true
+			if change, modified := diffFragment(old, fragment); modified {
+				changes = append(changes, change)
+			}
+		}
+	}
+	match(diffName, false)
+	match(func(f *Fragment) string { return f.Name }, true)
+
+	for _, old := range oldFragments {
+		if !matchedOld[old] {
+			changes = append(changes, Change{Kind: ChangeRemoved, Old: old, Breaking: true})
+		}
+	}
+	for _, fragment := range newFragments {
+		if !matchedNew[fragment] {
+			changes = append(changes, Change{Kind: ChangeAdded, New: fragment})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changeName(changes[i]) < changeName(changes[j])
+	})
+
+	return changes
+}
+
+// changeName returns the name a change is sorted by.
+func changeName(change Change) string {
+	if change.New != nil {
+		return change.New.Name
+	}
+
+	return change.Old.Name
+}
+
+// diffFragment compares two matched fragments, returning whether they
+// differ.
+func diffFragment(old, fragment *Fragment) (Change, bool) {
+	d := &differ{}
+
+	if old.Signature() != fragment.Signature() {
+		switch {
+		case fragment.Type == "event" && !old.Anonymous && !fragment.Anonymous:
+			d.breaking("topic changed from %s to %s", old.Topic().Hex(), fragment.Topic().Hex())
+		case fragment.Type == "function" || fragment.Type == "error":
+			d.breaking("selector changed from 0x%s to 0x%s", common.Bytes2Hex(old.Selector()), common.Bytes2Hex(fragment.Selector()))
+		}
+	}
+	if old.Anonymous != fragment.Anonymous {
+		d.breaking("anonymous changed from %t to %t", old.Anonymous, fragment.Anonymous)
+	}
+	if diffMutability(old) != diffMutability(fragment) {
+		d.detail("state mutability changed from %s to %s", diffMutability(old), diffMutability(fragment))
+	}
+
+	d.components("input", old.Inputs, fragment.Inputs)
+	d.components("output", old.Outputs, fragment.Outputs)
+
+	if len(d.details) == 0 {
+		return Change{}, false
+	}
+
+	return Change{Kind: ChangeModified, Old: old, New: fragment, Details: d.details, Breaking: d.isBreaking}, true
+}
+
+// diffMutability returns the state mutability of a fragment, which is
+// nonpayable when not set.
+func diffMutability(fragment *Fragment) string {
+	if fragment.StateMutability == "" {
+		return "nonpayable"
+	}
+
+	return fragment.StateMutability
+}
+
+// differ collects the differences between two matched fragments.
+type differ struct {
+	details    []string
+	isBreaking bool
+}
+
+// detail records a non-breaking difference.
+func (d *differ) detail(format string, args ...any) {
+	d.details = append(d.details, fmt.Sprintf(format, args...))
+}
+
+// breaking records a breaking difference.
+func (d *differ) breaking(format string, args ...any) {
+	d.detail(format, args...)
+	d.isBreaking = true
+}
+
+// components compares the parameters (or tuple members) at given path,
+// position by position.
+func (d *differ) components(path string, old, components []Component) {
+	if len(old) != len(components) {
+		d.breaking("%s count changed from %d to %d", path, len(old), len(components))
+	}
+
+	for i := 0; i < len(old) && i < len(components); i++ {
+		name := components[i].Name
+		if name == "" {
+			name = old[i].Name
+		}
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		label := path + " " + name
+		if strings.Contains(path, " ") {
+			label = path + "." + name
+		}
+
+		if old[i].Name != components[i].Name {
+			d.detail("%s renamed from %q to %q", label, old[i].Name, components[i].Name)
+		}
+		if old[i].Indexed != components[i].Indexed {
+			d.breaking("%s indexed changed from %t to %t", label, old[i].Indexed, components[i].Indexed)
+		}
+
+		isTuple := strings.HasPrefix(old[i].Type, "tuple") && strings.HasPrefix(components[i].Type, "tuple")
+		switch {
+		case isTuple && old[i].Type == components[i].Type:
+			d.components(label, old[i].Components, components[i].Components)
+		case old[i].CanonicalType() != components[i].CanonicalType():
+			d.breaking("%s type changed from %s to %s", label, old[i].CanonicalType(), components[i].CanonicalType())
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package abi_test
+
+import (
+	"fmt"
+
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleDiff() {
+	oldABI, err := abi.NewContract(
+		abi.MustParseFragment("function fill((address maker, uint256 amount) order) returns (uint256)"),
+		abi.MustParseFragment("function cancel(bytes32 hash)"),
+		abi.MustParseFragment("function owner() view returns (address)"),
+		abi.MustParseFragment("event Filled(bytes32 indexed hash, address taker)"),
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	newABI, err := abi.NewContract(
+		abi.MustParseFragment("function fill((address maker, uint128 amount, uint64 expiry) order) returns (uint256)"),
+		abi.MustParseFragment("function owner() view returns (address admin)"),
+		abi.MustParseFragment("function pause()"),
+		abi.MustParseFragment("event Filled(bytes32 indexed hash, address indexed taker)"),
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	report := abi.Diff(oldABI, newABI)
+	fmt.Print(report)
+	fmt.Println(report.Breaking())
+
+	// Output:
+	// removed function cancel(bytes32) (breaking)
+	// modified function fill((address,uint128,uint64)) (breaking)
+	//   selector changed from 0xf867a389 to 0x12d5bb83
+	//   input order count changed from 2 to 3
+	//   input order.amount type changed from uint256 to uint128
+	// modified function owner()
+	//   output admin renamed from "" to "admin"
+	// added function pause()
+	// modified event Filled(bytes32,address) (breaking)
+	//   input taker indexed changed from false to true
+	// true
+}
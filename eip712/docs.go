@@ -0,0 +1,3 @@
+// Package eip712 provides EIP-712 typed structured data hashing for Go
+// structs, following the same struct conventions as the abi package.
+package eip712
@@ -0,0 +1,376 @@
+package eip712
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/omnes-tech/abi"
+)
+
+// Domain is the EIP-712 domain of a signature. Only the fields that are
+// set are part of the domain type.
+type Domain struct {
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract *common.Address
+	Salt              *common.Hash
+}
+
+// member describes a member of an EIP-712 struct type.
+type member struct {
+	Name  string
+	Type  string
+	Index int
+}
+
+var (
+	bigIntPtrType = reflect.TypeOf(&big.Int{})
+	addressType   = reflect.TypeOf(common.Address{})
+	hashType      = reflect.TypeOf(common.Hash{})
+)
+
+// Separator computes the domain separator, i.e. the hashStruct of the
+// EIP712Domain struct.
+func (d Domain) Separator() (common.Hash, error) {
+	var members []string
+	var encoded []byte
+
+	if d.Name != "" {
+		members = append(members, "string name")
+		encoded = append(encoded, crypto.Keccak256([]byte(d.Name))...)
+	}
+	if d.Version != "" {
+		members = append(members, "string version")
+		encoded = append(encoded, crypto.Keccak256([]byte(d.Version))...)
+	}
+	if d.ChainID != nil {
+		members = append(members, "uint256 chainId")
+		encoded = append(encoded, common.LeftPadBytes(d.ChainID.Bytes(), 32)...)
+	}
+	if d.VerifyingContract != nil {
+		members = append(members, "address verifyingContract")
+		encoded = append(encoded, common.LeftPadBytes(d.VerifyingContract.Bytes(), 32)...)
+	}
+	if d.Salt != nil {
+		members = append(members, "bytes32 salt")
+		encoded = append(encoded, d.Salt.Bytes()...)
+	}
+
+	typeHash := crypto.Keccak256([]byte("EIP712Domain(" + strings.Join(members, ",") + ")"))
+
+	return crypto.Keccak256Hash(typeHash, encoded), nil
+}
+
+// Digest computes the final EIP-712 digest to be signed, i.e.
+// keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message)).
+func Digest(domain Domain, message any) (common.Hash, error) {
+	separator, err := domain.Separator()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	structHash, err := HashStruct(message)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, separator.Bytes(), structHash.Bytes()), nil
+}
+
+// TypeString returns the EIP-712 encodeType of the struct v, i.e.
+// `Mail(Person from,Person to,string contents)Person(string name,address wallet)`.
+// Struct types are named after their Go type, members after their
+// `abi:"name"` tag (or their Go field name with a lowercase first
+// letter) and member types are derived from the Go field types unless
+// given with an `abi:",type=uint96"` tag. Tags are parsed with
+// abi.ParseFieldTag, so fields tagged with `abi:"-"` are skipped and
+// invalid tags are reported.
+func TypeString(v any) (string, error) {
+	rt, err := structType(v)
+	if err != nil {
+		return "", err
+	}
+
+	return encodeType(rt)
+}
+
+// TypeHash returns the keccak256 hash of the EIP-712 encodeType of
+// the struct v.
+func TypeHash(v any) (common.Hash, error) {
+	typeStr, err := TypeString(v)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash([]byte(typeStr)), nil
+}
+
+// HashStruct computes the EIP-712 hashStruct of the struct v.
+func HashStruct(v any) (common.Hash, error) {
+	encoded, err := EncodeData(v)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// EncodeData computes typeHash ‖ encodeData of the struct v.
+func EncodeData(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("[EncodeData] v must not be nil")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("[EncodeData] v must be a struct, got %s", rv.Type())
+	}
+
+	return encodeStruct(rv)
+}
+
+// structType returns the struct type of v, dereferencing pointers.
+func structType(v any) (reflect.Type, error) {
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("v must be a struct, got %T", v)
+	}
+
+	return rt, nil
+}
+
+// encodeType builds the EIP-712 encodeType of given struct type: its
+// own definition followed by the definitions of all referenced struct
+// types sorted by name.
+func encodeType(rt reflect.Type) (string, error) {
+	definitions := make(map[string]string)
+	err := collectTypes(rt, definitions)
+	if err != nil {
+		return "", err
+	}
+
+	primary := rt.Name()
+	var referenced []string
+	for name := range definitions {
+		if name != primary {
+			referenced = append(referenced, name)
+		}
+	}
+	sort.Strings(referenced)
+
+	result := definitions[primary]
+	for _, name := range referenced {
+		result += definitions[name]
+	}
+
+	return result, nil
+}
+
+// collectTypes collects the definitions of given struct type and all
+// struct types referenced by its members.
+func collectTypes(rt reflect.Type, definitions map[string]string) error {
+	if rt.Name() == "" {
+		return fmt.Errorf("anonymous struct types are not supported")
+	}
+	if _, ok := definitions[rt.Name()]; ok {
+		return nil
+	}
+
+	members, err := structMembers(rt)
+	if err != nil {
+		return err
+	}
+
+	params := make([]string, len(members))
+	for i, m := range members {
+		params[i] = m.Type + " " + m.Name
+	}
+	definitions[rt.Name()] = rt.Name() + "(" + strings.Join(params, ",") + ")"
+
+	for _, m := range members {
+		fieldType := baseType(rt.Field(m.Index).Type)
+		if isStructType(fieldType) {
+			err := collectTypes(fieldType, definitions)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// structMembers lists the EIP-712 members of given struct type.
+func structMembers(rt reflect.Type) ([]member, error) {
+	var members []member
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, err := abi.ParseFieldTag(field)
+		if err != nil {
+			return nil, err
+		}
+		if tag.Skip {
+			continue
+		}
+		name, typeStr := tag.Name, tag.Type
+		if name == "" {
+			name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+		}
+		if typeStr == "" {
+			typeStr, err = solidityType(field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+
+		members = append(members, member{Name: name, Type: typeStr, Index: i})
+	}
+
+	return members, nil
+}
+
+// solidityType derives the EIP-712 type of given Go type.
+func solidityType(rt reflect.Type) (string, error) {
+	switch {
+	case rt == bigIntPtrType:
+		return "uint256", nil
+	case rt == addressType:
+		return "address", nil
+	case rt == hashType:
+		return "bytes32", nil
+	}
+
+	switch rt.Kind() {
+	case reflect.Ptr:
+		return solidityType(rt.Elem())
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		return fmt.Sprintf("uint%d", rt.Bits()), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		return fmt.Sprintf("int%d", rt.Bits()), nil
+	case reflect.Struct:
+		if rt.Name() == "" {
+			return "", fmt.Errorf("anonymous struct types are not supported")
+		}
+		return rt.Name(), nil
+	case reflect.Slice:
+		if rt.Elem().Kind() == reflect.Uint8 {
+			return "bytes", nil
+		}
+		elemType, err := solidityType(rt.Elem())
+		if err != nil {
+			return "", err
+		}
+		return elemType + "[]", nil
+	case reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 && rt.Len() >= 1 && rt.Len() <= 32 {
+			return fmt.Sprintf("bytes%d", rt.Len()), nil
+		}
+		elemType, err := solidityType(rt.Elem())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s[%d]", elemType, rt.Len()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", rt)
+}
+
+// encodeStruct computes typeHash ‖ encodeData of given struct value.
+func encodeStruct(rv reflect.Value) ([]byte, error) {
+	typeStr, err := encodeType(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := structMembers(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := crypto.Keccak256([]byte(typeStr))
+	for _, m := range members {
+		value, err := encodeValue(rv.Field(m.Index), m.Type)
+		if err != nil {
+			return nil, fmt.Errorf("[encodeStruct] error encoding field %s: %w", rv.Type().Field(m.Index).Name, err)
+		}
+		encoded = append(encoded, value...)
+	}
+
+	return encoded, nil
+}
+
+// encodeValue computes the 32-byte encodeData of a struct member.
+func encodeValue(rv reflect.Value, typeStr string) ([]byte, error) {
+	for rv.Kind() == reflect.Ptr && rv.Type() != bigIntPtrType {
+		if rv.IsNil() {
+			rv = reflect.Zero(rv.Type().Elem())
+			continue
+		}
+		rv = rv.Elem()
+	}
+
+	if strings.HasSuffix(typeStr, "]") {
+		elemTypeStr := typeStr[:strings.LastIndex(typeStr, "[")]
+
+		var encoded []byte
+		for i := 0; i < rv.Len(); i++ {
+			value, err := encodeValue(rv.Index(i), elemTypeStr)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, value...)
+		}
+		return crypto.Keccak256(encoded), nil
+	}
+
+	if isStructType(rv.Type()) {
+		encoded, err := encodeStruct(rv)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(encoded), nil
+	}
+
+	switch typeStr {
+	case "string":
+		return crypto.Keccak256([]byte(rv.String())), nil
+	case "bytes":
+		return crypto.Keccak256(rv.Bytes()), nil
+	}
+
+	return abi.Marshal(rv.Interface(), "("+typeStr+")")
+}
+
+// baseType strips pointers, slices and arrays from given type.
+func baseType(rt reflect.Type) reflect.Type {
+	for rt.Kind() == reflect.Ptr || rt.Kind() == reflect.Slice || rt.Kind() == reflect.Array {
+		rt = rt.Elem()
+	}
+
+	return rt
+}
+
+// isStructType checks whether given type is an EIP-712 struct type
+// (i.e. not big.Int).
+func isStructType(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Struct && rt != bigIntPtrType.Elem()
+}
@@ -0,0 +1,94 @@
+package eip712_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi/eip712"
+)
+
+type Person struct {
+	Name   string
+	Wallet common.Address
+}
+
+type Mail struct {
+	From     Person
+	To       Person
+	Contents string
+}
+
+func ExampleDigest() {
+	verifyingContract := common.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+	domain := eip712.Domain{
+		Name:              "Ether Mail",
+		Version:           "1",
+		ChainID:           big.NewInt(1),
+		VerifyingContract: &verifyingContract,
+	}
+
+	mail := Mail{
+		From:     Person{Name: "Cow", Wallet: common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")},
+		To:       Person{Name: "Bob", Wallet: common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB")},
+		Contents: "Hello, Bob!",
+	}
+
+	typeStr, err := eip712.TypeString(mail)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	separator, err := domain.Separator()
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	structHash, err := eip712.HashStruct(mail)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	digest, err := eip712.Digest(domain, mail)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(typeStr)
+	fmt.Println(separator)
+	fmt.Println(structHash)
+	fmt.Println(digest)
+
+	// Output:
+	// Mail(Person from,Person to,string contents)Person(string name,address wallet)
+	// 0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f
+	// 0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e
+	// 0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2
+}
+
+func ExampleTypeString() {
+	type Order struct {
+		Maker   common.Address `abi:"maker"`
+		Amount  *big.Int       `abi:"amount,type=uint96"`
+		Expiry  uint64         `abi:"deadline"`
+		Tokens  []common.Address
+		Payload []byte
+		Memo    string `abi:"-"`
+	}
+
+	typeStr, err := eip712.TypeString(Order{})
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(typeStr)
+
+	type Invalid struct {
+		Amount *big.Int `abi:"amount,typ=uint96"`
+	}
+	_, err = eip712.TypeString(Invalid{})
+	fmt.Println(err)
+
+	// Output:
+	// Order(address maker,uint96 amount,uint64 deadline,address[] tokens,bytes payload)
+	// invalid abi tag "amount,typ=uint96" on field Amount: unknown option "typ"
+}
@@ -1,8 +1,10 @@
 package abi
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -64,9 +66,21 @@ func EncodeWithSignature(funcSignature string, params ...any) ([]byte, error) {
 
 }
 
+// EncodeConstructor encodes the deployment data of a contract, i.e.
+// its creation bytecode followed by its encoded constructor arguments.
+func EncodeConstructor(bytecode []byte, typeStrs []string, args ...any) ([]byte, error) {
+	encodedArgs, err := Encode(typeStrs, args...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return append(append([]byte{}, bytecode...), encodedArgs...), nil
+}
+
 // EncodeSignature encodes signature to 4-byte selector.
 func EncodeSignature(funcSignature string) []byte {
-	return crypto.Keccak256([]byte(funcSignature))[:4]
+	hash := signatureHash(funcSignature)
+	return hash[:4]
 }
 
 // Encode encodes given arguments based on provided types.
@@ -86,7 +100,7 @@ func Encode(typeStrs []string, values ...any) ([]byte, error) {
 	for i, typeStr := range typeStrs {
 		var encoded []byte
 
-		isTypeTuple, splitedTypes, err := IsTuple(typeStr)
+		isTypeTuple, splitedTypes, err := tupleType(typeStr)
 		if err != nil {
 			return []byte{}, err
 		}
@@ -97,17 +111,16 @@ func Encode(typeStrs []string, values ...any) ([]byte, error) {
 		}
 
 		if isTypeArray {
-			if arraySize != 0 && len(values[i].([]any)) != arraySize {
+			arrayValues, err := toAnyList(typeStr, values[i])
+			if err != nil {
+				return []byte{}, err
+			}
+			if arraySize != 0 && len(arrayValues) != arraySize {
 				return nil, fmt.Errorf("array size mismatch")
 			}
 			openBracketIndex := strings.LastIndex(typeStr, "[")
 
 			var arrayTypes []string
-			var arrayValues []any
-			arrayValues, ok := values[i].([]any)
-			if !ok {
-				arrayValues = toAnyArray(values[i])
-			}
 			for j := 0; j < len(arrayValues); j++ {
 				arrayTypes = append(arrayTypes, typeStr[:openBracketIndex])
 			}
@@ -117,13 +130,24 @@ func Encode(typeStrs []string, values ...any) ([]byte, error) {
 				return []byte{}, err
 			}
 
-			arraySize := big.NewInt(int64(len(arrayValues)))
-			encoded = append(common.LeftPadBytes(arraySize.Bytes(), 32), encoded...)
+			// Only unbounded arrays are prefixed with their length.
+			if arraySize == 0 {
+				arrayLength := big.NewInt(int64(len(arrayValues)))
+				encoded = append(common.LeftPadBytes(arrayLength.Bytes(), 32), encoded...)
+			}
 		} else if isTypeTuple {
 			if values[i] == nil {
-				encoded = common.LeftPadBytes(big.NewInt(0).Bytes(), 32*len(splitedTypes))
+				size, err := headSize(typeStr)
+				if err != nil {
+					return []byte{}, err
+				}
+				encoded = make([]byte, size)
 			} else {
-				encoded, err = Encode(splitedTypes, values[i].([]any)...)
+				tupleValues, err := toAnyList(typeStr, values[i])
+				if err != nil {
+					return []byte{}, err
+				}
+				encoded, err = Encode(splitedTypes, tupleValues...)
 				if err != nil {
 					return []byte{}, err
 				}
@@ -157,7 +181,11 @@ func Encode(typeStrs []string, values ...any) ([]byte, error) {
 }
 
 // EncodePacked encodes given arguments based on provided types
-// with packed encoding.
+// with packed encoding. Elements of arrays of core static types are
+// padded to 32 bytes, as done by Solidity's `abi.encodePacked`.
+// Values of fixed bytes types longer than their size are accepted
+// when right-padded with zeros up to 32 bytes, as returned by Decode,
+// and are truncated to their size.
 func EncodePacked(typeStrs []string, values ...any) ([]byte, error) {
 	if len(typeStrs) != len(values) {
 		return []byte{}, fmt.Errorf("typeStrs and values must have the same length. typeStrs: %v (length %v), values: %v (length %v)",
@@ -172,7 +200,7 @@ func EncodePacked(typeStrs []string, values ...any) ([]byte, error) {
 	for i, typeStr := range typeStrs {
 		var encoded []byte
 
-		isTypeTuple, splitedTypes, err := IsTuple(typeStr)
+		isTypeTuple, splitedTypes, err := tupleType(typeStr)
 		if err != nil {
 			return []byte{}, err
 		}
@@ -183,23 +211,45 @@ func EncodePacked(typeStrs []string, values ...any) ([]byte, error) {
 		}
 
 		if isTypeArray {
-			if arraySize != 0 && len(values[i].([]any)) != arraySize {
+			arrayValues, err := toAnyList(typeStr, values[i])
+			if err != nil {
+				return []byte{}, err
+			}
+			if arraySize != 0 && len(arrayValues) != arraySize {
 				return nil, fmt.Errorf("array size mismatch")
 			}
-			openBracketIndex := strings.LastIndex(typeStr, "[")
+			elemTypeStr := typeStr[:strings.LastIndex(typeStr, "[")]
 
-			var arrayTypes []string
-			arrayValues := values[i].([]any)
-			for j := 0; j < len(arrayValues); j++ {
-				arrayTypes = append(arrayTypes, typeStr[:openBracketIndex])
+			// Array elements of core static types are padded to 32 bytes,
+			// as done by Solidity's abi.encodePacked.
+			isElemTuple, _, err := tupleType(elemTypeStr)
+			if err != nil {
+				return []byte{}, err
 			}
-
-			encoded, err = EncodePacked(arrayTypes, arrayValues...)
+			isElemArray, _, err := IsArray(elemTypeStr)
 			if err != nil {
 				return []byte{}, err
 			}
+			padElems := !isElemTuple && !isElemArray && !IsDynamic(elemTypeStr, false)
+
+			for _, arrayValue := range arrayValues {
+				var encodedElem []byte
+				if padElems {
+					encodedElem, err = encode(elemTypeStr, arrayValue)
+				} else {
+					encodedElem, err = EncodePacked([]string{elemTypeStr}, arrayValue)
+				}
+				if err != nil {
+					return []byte{}, err
+				}
+				encoded = append(encoded, encodedElem...)
+			}
 		} else if isTypeTuple {
-			encoded, err = EncodePacked(splitedTypes, values[i].([]any)...)
+			tupleValues, err := toAnyList(typeStr, values[i])
+			if err != nil {
+				return []byte{}, err
+			}
+			encoded, err = EncodePacked(splitedTypes, tupleValues...)
 			if err != nil {
 				return []byte{}, err
 			}
@@ -216,6 +266,18 @@ func EncodePacked(typeStrs []string, values ...any) ([]byte, error) {
 	return result, nil
 }
 
+// Keccak256Packed computes the keccak256 hash of given arguments
+// encoded with packed encoding, like Solidity's
+// `keccak256(abi.encodePacked(...))`.
+func Keccak256Packed(typeStrs []string, values ...any) (common.Hash, error) {
+	encoded, err := EncodePacked(typeStrs, values...)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash(encoded), nil
+}
+
 // encode encodes given argument based on provided type string.
 func encode(typeStr string, value any) ([]byte, error) {
 	encoded, err := encodePacked(typeStr, value)
@@ -233,8 +295,12 @@ func encode(typeStr string, value any) ([]byte, error) {
 
 		encoded = append(bytesLength, encoded...)
 
-	} else if len(typeStr) > 5 && typeStr[:5] == "bytes" {
+	} else if (len(typeStr) > 5 && typeStr[:5] == "bytes") || typeStr == "function" {
 		encoded = common.RightPadBytes(encoded[:], 32)
+	} else if (strings.HasPrefix(typeStr, "int") || strings.HasPrefix(typeStr, "fixed")) && len(encoded) > 0 && encoded[0]&0x80 != 0 {
+		// Negative signed integers and fixed point numbers are sign
+		// extended.
+		encoded = append(bytes.Repeat([]byte{0xff}, 32-len(encoded)), encoded...)
 	} else {
 		encoded = common.LeftPadBytes(encoded[:], 32)
 	}
@@ -245,6 +311,16 @@ func encode(typeStr string, value any) ([]byte, error) {
 // encodePacked encodes given argument based on provided type string
 // with packed encoding.
 func encodePacked(typeStr string, value any) ([]byte, error) {
+	// Accept the same Go values as Marshal (i.e. common.Address, native
+	// integers or [32]byte) by converting them to the expected values.
+	// Fixed point values are converted by scaleDecimals.
+	if !isPackedValue(typeStr, value) && !isFixedType(typeStr) {
+		converted, err := marshalCoreValue(unwrapValue(reflect.ValueOf(value)), typeStr)
+		if err != nil {
+			return []byte{}, err
+		}
+		value = converted
+	}
 
 	bytes := make([]byte, 0)
 	switch typeStr {
@@ -271,6 +347,12 @@ func encodePacked(typeStr string, value any) ([]byte, error) {
 			return []byte{}, fmt.Errorf("invalid parameter type: %v, %T", typeStr, value)
 		}
 		bytes = append(bytes, []byte(val)...)
+	case "function":
+		val, ok := value.([]byte)
+		if !ok || len(val) != functionPointerLength {
+			return []byte{}, fmt.Errorf("invalid parameter type: %v, %T", typeStr, value)
+		}
+		bytes = append(bytes, val...)
 	default:
 		if typeStr[:3] == "int" || typeStr[:4] == "uint" {
 			val, ok := value.(*big.Int)
@@ -335,6 +417,11 @@ func encodePacked(typeStr string, value any) ([]byte, error) {
 					return []byte{}, fmt.Errorf("invalid byte size: %v", typeStr)
 				}
 
+				// Decoded fixed bytes are 32 bytes long, right-padded
+				// with zeros.
+				if len(val) > bytesSize && len(val) <= 32 && isZeroBytes(val[bytesSize:]) {
+					val = val[:bytesSize]
+				}
 				if len(val) > bytesSize {
 					return []byte{}, fmt.Errorf("value and type bytes size mismatch: type %v; value bytes size %v", typeStr, len(val))
 				}
@@ -345,59 +432,22 @@ func encodePacked(typeStr string, value any) ([]byte, error) {
 			}
 
 			bytes = append(bytes, val...)
-		} else if typeStr[:5] == "fixed" || typeStr[:6] == "ufixed" { // @note differences in result with fixed/ufixed types
-			val, ok := value.(*big.Float)
-			if !ok {
-				return []byte{}, fmt.Errorf("invalid parameter type: %v, %T", typeStr, value)
-			}
-
-			var index int
-			if typeStr[:5] == "fixed" {
-				index = 5
-				if len(typeStr) == 5 {
-					typeStr += "128x18"
-				}
-			} else {
-				index = 6
-				if len(typeStr) == 6 {
-					typeStr += "128x18"
-				}
-			}
-
-			sizes := strings.Split(typeStr[index:], "x")
-			bits, err := strconv.Atoi(sizes[0])
+		} else if isFixedType(typeStr) {
+			intTypeStr, decimals, err := fixedParams(typeStr)
 			if err != nil {
-				return []byte{}, fmt.Errorf("error getting bits from %s: %v", typeStr, err)
+				return []byte{}, err
 			}
 
-			fracPlaces, err := strconv.Atoi(sizes[1])
+			scaled, err := scaleDecimals(value, decimals)
 			if err != nil {
-				return []byte{}, fmt.Errorf("error getting frac. places from %s: %v", typeStr, err)
-			}
-
-			if bits%8 != 0 {
-				return []byte{}, fmt.Errorf("invalid bits value: %v, bits = %v", typeStr, bits)
-			}
-
-			var min *big.Float
-			var max *big.Float
-			if typeStr[:5] == "fixed" {
-				min, max = computeSignedFixedBounds(int64(bits), float64(fracPlaces))
-			} else {
-				min, max = computeUnsignedFixedBounds(int64(bits), float64(fracPlaces))
+				return []byte{}, fmt.Errorf("invalid parameter value for %v: %w", typeStr, err)
 			}
-
-			if val.Cmp(min) == -1 || val.Cmp(max) == 1 {
-				return []byte{}, fmt.Errorf("value out of allowed range: %v, %v", typeStr, val)
+			if scaled.Cmp(validCoreTypes[intTypeStr].Max) == 1 || scaled.Cmp(validCoreTypes[intTypeStr].Min) == -1 {
+				return []byte{}, fmt.Errorf("value out of allowed range: %v, %v", typeStr, value)
 			}
 
-			scaledValue := new(big.Float)
-			scaledValue.Mul(val, pow(floatTen, uint64(fracPlaces)))
-			bigIntValue := new(big.Int)
-			scaledValue.Int(bigIntValue)
-
-			bytes = append(bytes, common.LeftPadBytes(bigIntValue.Bytes(), bits/8)...)
-
+			// Fixed point numbers are encoded as their scaled integers.
+			return encodePacked(intTypeStr, scaled)
 		} else {
 			return []byte{}, fmt.Errorf("invalid parameter type: %v, %T", typeStr, value)
 		}
@@ -406,6 +456,25 @@ func encodePacked(typeStr string, value any) ([]byte, error) {
 	return bytes, nil
 }
 
+// isPackedValue checks whether value is of the Go type encodePacked
+// expects for given type string, needing no conversion.
+func isPackedValue(typeStr string, value any) bool {
+	switch value.(type) {
+	case *common.Address:
+		return typeStr == "address"
+	case bool:
+		return typeStr == "bool"
+	case string:
+		return typeStr == "string"
+	case *big.Int:
+		return strings.HasPrefix(typeStr, "int") || strings.HasPrefix(typeStr, "uint")
+	case []byte:
+		return strings.HasPrefix(typeStr, "bytes") || typeStr == "function"
+	default:
+		return false
+	}
+}
+
 // calculateHeadLength calculates encoded bytecode head length.
 func calculateHeadLength(rawHeadChunks [][]byte) uint64 {
 	headLength := uint64(0)
@@ -471,42 +540,34 @@ func joinChunks(headChunks [][]byte, tailChunks [][]byte) []byte {
 	return joined
 }
 
-// pow performs exponentiation for big.Float number.
-func pow(a *big.Float, e uint64) *big.Float {
-	result := zeroFloat().Copy(a)
-	for i := uint64(0); i < e-1; i++ {
-		result = mul(result, a)
+// isZeroBytes checks whether all given bytes are zero.
+func isZeroBytes(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
 	}
-	return result
-}
 
-// zeroFloat returns 0 in big.Float type.
-func zeroFloat() *big.Float {
-	r := big.NewFloat(0.0)
-	r.SetPrec(256)
-	return r
+	return true
 }
 
-// mul multiplies two big.Float arguments
-func mul(a, b *big.Float) *big.Float {
-	return zeroFloat().Mul(a, b)
-}
+// toAnyList converts the value of an array or tuple into the list of
+// its element values. Values which are not []any (i.e. []string,
+// [][]byte or structs) are converted following the Marshal conventions.
+func toAnyList(typeStr string, value any) ([]any, error) {
+	if list, ok := value.([]any); ok {
+		return list, nil
+	}
 
-func toAnyArray(input any) []any {
-	var result []interface{}
+	converted, err := marshalValue(reflect.ValueOf(value), typeStr)
+	if err != nil {
+		return nil, err
+	}
 
-	switch v := input.(type) {
-	case []string:
-		for _, s := range v {
-			result = append(result, s)
-		}
-	case [][]byte:
-		for _, b := range v {
-			result = append(result, b)
-		}
-	default:
-		fmt.Println("Unsupported input type")
+	list, ok := converted.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid parameter type: %v, %T", typeStr, value)
 	}
 
-	return result
+	return list, nil
 }
@@ -128,3 +128,102 @@ func ExampleEncodeSignature() {
 
 	// Output: c6210dba
 }
+
+func ExampleEncodePacked_goValues() {
+	encoded, err := abi.EncodePacked(
+		[]string{"address", "uint16", "bytes4", "address[]"},
+		common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+		uint16(513),
+		[4]byte{0xde, 0xad, 0xbe, 0xef},
+		[]common.Address{common.HexToAddress("0x000000000000000000000000000000000000dEaD")},
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(common.Bytes2Hex(encoded))
+
+	// Output: 5ff137d4b0fdcd49dca30c7cf57e578a026d27890201deadbeef000000000000000000000000000000000000000000000000000000000000dead
+}
+
+func ExampleKeccak256Packed() {
+	hash, err := abi.Keccak256Packed(
+		[]string{"address", "uint256"},
+		common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+		big.NewInt(1),
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(hash)
+
+	// Output: 0x7a1fb55861f8d59ddbc2a0af2f773dfa9da54d95338c6730635b488c30ba9a53
+}
+
+func ExampleEncodeConstructor() {
+	bytecode := common.Hex2Bytes("6080604052")
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+	deployData, err := abi.EncodeConstructor(bytecode, []string{"address", "uint256"}, &owner, big.NewInt(1000))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	args, err := abi.DecodeConstructor(deployData, bytecode, []string{"address", "uint256"})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(len(deployData), args)
+
+	// Output: 69 [0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1000]
+}
+
+func ExampleEncode_signed() {
+	encoded, err := abi.Encode(
+		[]string{"int8", "int32", "int256"},
+		int8(-5),
+		int32(-70000),
+		big.NewInt(-1),
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	for i := 0; i < len(encoded); i += 32 {
+		fmt.Println(common.Bytes2Hex(encoded[i : i+32]))
+	}
+
+	// Output:
+	// fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb
+	// fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffeee90
+	// ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff
+}
+
+func ExampleEncodePacked_decodedBytes() {
+	encoded, err := abi.Encode([]string{"bytes4"}, []byte{0xde, 0xad, 0xbe, 0xef})
+	if err != nil {
+		fmt.Println(err)
+	}
+	decoded, err := abi.Decode([]string{"bytes4"}, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Decoded bytes4 values hold the whole 32 bytes word.
+	packed, err := abi.EncodePacked([]string{"bytes4"}, decoded[0])
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(len(decoded[0].([]byte)), common.Bytes2Hex(packed))
+
+	// Output: 32 deadbeef
+}
+
+func ExampleEncodePacked_invalidValue() {
+	_, err := abi.EncodePacked([]string{"uint256"}, "one")
+	fmt.Println(err)
+
+	// Output: [marshalCoreValue] cannot marshal string into uint256
+}
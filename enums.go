@@ -0,0 +1,103 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// maxEnumMembers is the maximum number of members of a Solidity enum,
+// which is encoded as a uint8.
+const maxEnumMembers = 256
+
+// newEnumSetter builds the setter of a field tagged with
+// `abi:",enum=A|B|C"`. Decoded values must be a valid member index.
+// String fields (or named string types) receive the member name, and
+// integer fields (i.e. `type OrderStatus uint8`) receive the index.
+func newEnumSetter(t reflect.Type, names []string) (setter, error) {
+	if t.Kind() != reflect.String && !isIntKind(t.Kind()) && !isUintKind(t.Kind()) {
+		return nil, fmt.Errorf("enum fields must be strings or integers, got %s", t)
+	}
+
+	return func(s *parseState, target reflect.Value, value any, _ []Component) error {
+		if value == nil {
+			if s.opts.AllowNil {
+				return nil
+			}
+			return newParseError(fmt.Errorf("nil decoded value for %s", t), t, value)
+		}
+
+		index, err := enumIndex(value, names)
+		if err != nil {
+			return newParseError(err, t, value)
+		}
+
+		switch {
+		case t.Kind() == reflect.String:
+			target.SetString(names[index])
+		case isIntKind(t.Kind()):
+			if target.OverflowInt(int64(index)) {
+				return newParseError(newOverflowError(index, t), t, value)
+			}
+			target.SetInt(int64(index))
+		default:
+			if target.OverflowUint(uint64(index)) {
+				return newParseError(newOverflowError(index, t), t, value)
+			}
+			target.SetUint(uint64(index))
+		}
+
+		return nil
+	}, nil
+}
+
+// enumIndex converts a decoded enum value into a member index, checking
+// that it is within the declared members.
+func enumIndex(value any, names []string) (int, error) {
+	var bi *big.Int
+	switch value := value.(type) {
+	case *big.Int:
+		bi = value
+	default:
+		val := reflect.ValueOf(value)
+		switch {
+		case val.CanInt():
+			bi = big.NewInt(val.Int())
+		case val.CanUint():
+			bi = new(big.Int).SetUint64(val.Uint())
+		default:
+			return 0, newMismatchError("enum value", value)
+		}
+	}
+
+	if bi.Sign() < 0 || bi.Cmp(big.NewInt(int64(len(names)))) >= 0 {
+		return 0, fmt.Errorf("enum value %s out of range, expected one of %d members", bi, len(names))
+	}
+
+	return int(bi.Int64()), nil
+}
+
+// marshalEnum converts a field tagged with `abi:",enum=A|B|C"` into
+// the index of its member, given either by name or by index.
+func marshalEnum(rv reflect.Value, names []string) (any, error) {
+	rv = unwrapValue(rv)
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("[marshalEnum] nil enum value")
+	}
+
+	if rv.Kind() == reflect.String {
+		for i, name := range names {
+			if name == rv.String() {
+				return big.NewInt(int64(i)), nil
+			}
+		}
+		return nil, fmt.Errorf("[marshalEnum] unknown enum member %q", rv.String())
+	}
+
+	index, err := enumIndex(rv.Interface(), names)
+	if err != nil {
+		return nil, fmt.Errorf("[marshalEnum] %w", err)
+	}
+
+	return big.NewInt(int64(index)), nil
+}
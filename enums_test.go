@@ -0,0 +1,67 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/omnes-tech/abi"
+)
+
+type OrderStatus uint8
+
+const (
+	OrderOpen OrderStatus = iota
+	OrderFilled
+	OrderCancelled
+)
+
+type exampleEnumOrder struct {
+	ID     *big.Int
+	Status OrderStatus `abi:",enum=Open|Filled|Cancelled"`
+	Side   string      `abi:",enum=Buy|Sell"`
+}
+
+func ExampleParse_enum() {
+	typeStrs := []string{"uint256", "uint8", "uint8"}
+	encoded, err := abi.Encode(typeStrs, big.NewInt(1), big.NewInt(1), big.NewInt(1))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var order exampleEnumOrder
+	err = abi.Parse(decoded, &order)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(order.ID, order.Status == OrderFilled, order.Side)
+
+	// Values outside the declared members are rejected.
+	err = abi.Parse([]any{big.NewInt(2), big.NewInt(3), big.NewInt(0)}, &order)
+	fmt.Println(err)
+
+	// Output:
+	// 1 true Sell
+	// [parseStruct] error parsing field exampleEnumOrder.Status (expected abi_test.OrderStatus, got *big.Int): enum value 3 out of range, expected one of 3 members
+}
+
+func ExampleMarshal_enum() {
+	order := exampleEnumOrder{ID: big.NewInt(7), Status: OrderCancelled, Side: "Buy"}
+
+	encoded, err := abi.Marshal(order, "(uint256,uint8,uint8)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode([]string{"uint256", "uint8", "uint8"}, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(decoded...)
+
+	// Output: 7 2 0
+}
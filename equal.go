@@ -0,0 +1,248 @@
+package abi
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	addressType  = reflect.TypeOf(common.Address{})
+	bytesType    = reflect.TypeOf([]byte{})
+)
+
+// DeepEqual reports whether a and b are deeply equal following the same
+// traversal rules as Parse. Unlike reflect.DeepEqual, *big.Int and
+// *big.Float values are compared by value (Cmp), pointers are
+// dereferenced, addresses can be compared in their decoded hex string
+// form against common.Address values, and decoded tuples ([]any) can be
+// compared positionally against struct values.
+func DeepEqual(a, b any) bool {
+	return deepEqual(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// deepEqual is the recursive implementation of DeepEqual.
+func deepEqual(a, b reflect.Value) bool {
+	a = unwrapValue(a)
+	b = unwrapValue(b)
+
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+
+	if a.Type() == bigIntType && b.Type() == bigIntType {
+		x, y := a.Addr().Interface().(*big.Int), b.Addr().Interface().(*big.Int)
+		return x.Cmp(y) == 0
+	}
+	if a.Type() == bigFloatType && b.Type() == bigFloatType {
+		x, y := a.Addr().Interface().(*big.Float), b.Addr().Interface().(*big.Float)
+		return x.Cmp(y) == 0
+	}
+
+	if a.Type() == addressType || b.Type() == addressType {
+		x, okA := toAddress(a)
+		y, okB := toAddress(b)
+		return okA && okB && x == y
+	}
+
+	if isBytesValue(a) && isBytesValue(b) {
+		return bytes.Equal(bytesOf(a), bytesOf(b))
+	}
+
+	switch {
+	case a.Kind() == reflect.Struct && b.Kind() == reflect.Struct:
+		if a.Type() != b.Type() {
+			return false
+		}
+		for i := 0; i < a.NumField(); i++ {
+			if !a.Type().Field(i).IsExported() {
+				continue
+			}
+			if !deepEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case a.Kind() == reflect.Struct && isListValue(b):
+		return structEqualsList(a, b)
+	case isListValue(a) && b.Kind() == reflect.Struct:
+		return structEqualsList(b, a)
+	case isListValue(a) && isListValue(b):
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !deepEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case a.Kind() == reflect.Map && b.Kind() == reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			other := b.MapIndex(iter.Key())
+			if !other.IsValid() || !deepEqual(iter.Value(), other) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// structEqualsList compares struct fields positionally against the
+// elements of a decoded tuple, promoting the fields of embedded structs
+// and skipping and spanning values as Parse does.
+func structEqualsList(structVal reflect.Value, list reflect.Value) bool {
+	plan := structPlanFor(structVal.Type())
+	if plan.numValues != list.Len() {
+		return false
+	}
+
+	var cursor int
+	for _, field := range plan.fields {
+		fieldVal, _ := structVal.FieldByIndexErr(field.index)
+		elem := list.Index(cursor)
+		if field.tag.Span > 1 {
+			elem = list.Slice(cursor, cursor+field.tag.Span)
+		}
+		if !deepEqual(fieldVal, elem) {
+			return false
+		}
+		cursor += field.tag.Span
+	}
+	return true
+}
+
+// unwrapValue strips interfaces and pointers from a value. Nil
+// pointers and interfaces become the invalid (zero) reflect.Value.
+func unwrapValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	if v.IsValid() && !v.CanAddr() {
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+	return v
+}
+
+// toAddress converts a common.Address value or a hex address string
+// into common.Address.
+func toAddress(v reflect.Value) (common.Address, bool) {
+	if v.Type() == addressType {
+		return v.Interface().(common.Address), true
+	}
+	if v.Kind() == reflect.String && common.IsHexAddress(v.String()) {
+		return common.HexToAddress(v.String()), true
+	}
+	return common.Address{}, false
+}
+
+// isBytesValue checks whether the value is a byte slice or byte array.
+func isBytesValue(v reflect.Value) bool {
+	return (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.Uint8
+}
+
+// bytesOf returns the content of a byte slice or byte array.
+func bytesOf(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+	result := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(result), v)
+	return result
+}
+
+// isListValue checks whether the value is a slice or an array.
+func isListValue(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+// Clone returns a deep copy of a decoded value tree or of a parsed
+// struct, so that the copy can be mutated (e.g. with big.Int arithmetic)
+// without affecting the original.
+func Clone(v any) any {
+	if v == nil {
+		return nil
+	}
+	return cloneValue(reflect.ValueOf(v)).Interface()
+}
+
+// cloneValue is the recursive implementation of Clone.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		if v.Type().Elem() == bigIntType {
+			return reflect.ValueOf(new(big.Int).Set(v.Interface().(*big.Int)))
+		}
+		if v.Type().Elem() == bigFloatType {
+			return reflect.ValueOf(new(big.Float).Copy(v.Interface().(*big.Float)))
+		}
+		cloned := reflect.New(v.Type().Elem())
+		cloned.Elem().Set(cloneValue(v.Elem()))
+		return cloned
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		cloned := reflect.New(v.Type()).Elem()
+		cloned.Set(cloneValue(v.Elem()))
+		return cloned
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		cloned := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cloned.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return cloned
+	case reflect.Array:
+		cloned := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cloned.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return cloned
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		cloned := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cloned.SetMapIndex(cloneValue(iter.Key()), cloneValue(iter.Value()))
+		}
+		return cloned
+	case reflect.Struct:
+		cloned := reflect.New(v.Type()).Elem()
+		cloned.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				cloned.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+		return cloned
+	default:
+		return v
+	}
+}
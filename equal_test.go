@@ -0,0 +1,36 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleDeepEqual() {
+	sum := new(big.Int).Add(big.NewInt(40), big.NewInt(2))
+
+	fmt.Println(abi.DeepEqual(sum, big.NewInt(42)))
+	fmt.Println(abi.DeepEqual(
+		[]any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", big.NewInt(1)},
+		[]any{common.HexToAddress("0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789"), big.NewInt(1)},
+	))
+	fmt.Println(abi.DeepEqual([]any{big.NewInt(1)}, []any{big.NewInt(2)}))
+
+	// Output:
+	// true
+	// true
+	// false
+}
+
+func ExampleClone() {
+	decoded := []any{big.NewInt(1), []any{big.NewInt(2), []byte{0x01}}}
+
+	cloned := abi.Clone(decoded).([]any)
+	cloned[0].(*big.Int).Add(cloned[0].(*big.Int), big.NewInt(10))
+
+	fmt.Println(decoded[0], cloned[0], abi.DeepEqual(decoded[1], cloned[1]))
+
+	// Output: 1 11 true
+}
@@ -0,0 +1,4 @@
+// Package erc4337 packs, hashes and encodes ERC-4337 user operations
+// for the EntryPoint v0.6 (UserOperation) and v0.7 (PackedUserOperation)
+// contracts with the abi package.
+package erc4337
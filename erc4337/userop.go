@@ -0,0 +1,309 @@
+package erc4337
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/omnes-tech/abi"
+)
+
+// EntryPoint addresses, deployed at the same address on most EVM chains.
+var (
+	EntryPointV06 = common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	EntryPointV07 = common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032")
+)
+
+const (
+	userOperationType       = "(address,uint256,bytes,bytes,uint256,uint256,uint256,uint256,uint256,bytes,bytes)"
+	packedUserOperationType = "(address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes)"
+
+	handleOpsV06Signature = "handleOps(" + userOperationType + "[],address)"
+	handleOpsV07Signature = "handleOps(" + packedUserOperationType + "[],address)"
+)
+
+// UserOperation is a user operation of the EntryPoint v0.6.
+type UserOperation struct {
+	Sender               common.Address
+	Nonce                *big.Int
+	InitCode             []byte
+	CallData             []byte
+	CallGasLimit         *big.Int
+	VerificationGasLimit *big.Int
+	PreVerificationGas   *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	PaymasterAndData     []byte
+	Signature            []byte
+}
+
+// PackedUserOperation is a user operation of the EntryPoint v0.7, as
+// passed on-chain, whose gas fields are packed in pairs of uint128.
+// Use UnpackedUserOperation to build or read it.
+type PackedUserOperation struct {
+	Sender             common.Address
+	Nonce              *big.Int
+	InitCode           []byte
+	CallData           []byte
+	AccountGasLimits   [32]byte
+	PreVerificationGas *big.Int
+	GasFees            [32]byte
+	PaymasterAndData   []byte
+	Signature          []byte
+}
+
+// UnpackedUserOperation holds the fields of a PackedUserOperation one
+// by one, as in the `eth_sendUserOperation` requests of the EntryPoint
+// v0.7. Factory and Paymaster are nil when not used.
+type UnpackedUserOperation struct {
+	Sender                        common.Address
+	Nonce                         *big.Int
+	Factory                       *common.Address
+	FactoryData                   []byte
+	CallData                      []byte
+	CallGasLimit                  *big.Int
+	VerificationGasLimit          *big.Int
+	PreVerificationGas            *big.Int
+	MaxFeePerGas                  *big.Int
+	MaxPriorityFeePerGas          *big.Int
+	Paymaster                     *common.Address
+	PaymasterVerificationGasLimit *big.Int
+	PaymasterPostOpGasLimit       *big.Int
+	PaymasterData                 []byte
+	Signature                     []byte
+}
+
+// handleOpsV06Args holds the arguments of `handleOps` of the EntryPoint
+// v0.6.
+type handleOpsV06Args struct {
+	Ops         []UserOperation
+	Beneficiary common.Address
+}
+
+// handleOpsV07Args holds the arguments of `handleOps` of the EntryPoint
+// v0.7.
+type handleOpsV07Args struct {
+	Ops         []PackedUserOperation
+	Beneficiary common.Address
+}
+
+// Hash computes the userOpHash of the operation, signed by the sender
+// account, for given EntryPoint v0.6 and chain.
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) (common.Hash, error) {
+	packed, err := abi.Encode(
+		[]string{"address", "uint256", "bytes32", "bytes32", "uint256", "uint256", "uint256", "uint256", "uint256", "bytes32"},
+		op.Sender,
+		op.Nonce,
+		crypto.Keccak256(op.InitCode),
+		crypto.Keccak256(op.CallData),
+		op.CallGasLimit,
+		op.VerificationGasLimit,
+		op.PreVerificationGas,
+		op.MaxFeePerGas,
+		op.MaxPriorityFeePerGas,
+		crypto.Keccak256(op.PaymasterAndData),
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error packing user operation: %w", err)
+	}
+
+	return userOpHash(packed, entryPoint, chainID)
+}
+
+// Hash computes the userOpHash of the operation, signed by the sender
+// account, for given EntryPoint v0.7 and chain.
+func (op *PackedUserOperation) Hash(entryPoint common.Address, chainID *big.Int) (common.Hash, error) {
+	packed, err := abi.Encode(
+		[]string{"address", "uint256", "bytes32", "bytes32", "bytes32", "uint256", "bytes32", "bytes32"},
+		op.Sender,
+		op.Nonce,
+		crypto.Keccak256(op.InitCode),
+		crypto.Keccak256(op.CallData),
+		op.AccountGasLimits,
+		op.PreVerificationGas,
+		op.GasFees,
+		crypto.Keccak256(op.PaymasterAndData),
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error packing user operation: %w", err)
+	}
+
+	return userOpHash(packed, entryPoint, chainID)
+}
+
+// Pack packs the operation into its on-chain form. Factory and paymaster
+// data can't be packed without their factory or paymaster.
+func (op *UnpackedUserOperation) Pack() (PackedUserOperation, error) {
+	accountGasLimits, err := PackUint128s(op.VerificationGasLimit, op.CallGasLimit)
+	if err != nil {
+		return PackedUserOperation{}, fmt.Errorf("error packing account gas limits: %w", err)
+	}
+	gasFees, err := PackUint128s(op.MaxPriorityFeePerGas, op.MaxFeePerGas)
+	if err != nil {
+		return PackedUserOperation{}, fmt.Errorf("error packing gas fees: %w", err)
+	}
+
+	if op.Factory == nil && len(op.FactoryData) > 0 {
+		return PackedUserOperation{}, fmt.Errorf("factory data of %d bytes without a factory", len(op.FactoryData))
+	}
+	if op.Paymaster == nil && len(op.PaymasterData) > 0 {
+		return PackedUserOperation{}, fmt.Errorf("paymaster data of %d bytes without a paymaster", len(op.PaymasterData))
+	}
+
+	var initCode []byte
+	if op.Factory != nil {
+		initCode = append(op.Factory.Bytes(), op.FactoryData...)
+	}
+
+	var paymasterAndData []byte
+	if op.Paymaster != nil {
+		gasLimits, err := PackUint128s(op.PaymasterVerificationGasLimit, op.PaymasterPostOpGasLimit)
+		if err != nil {
+			return PackedUserOperation{}, fmt.Errorf("error packing paymaster gas limits: %w", err)
+		}
+		paymasterAndData = append(append(op.Paymaster.Bytes(), gasLimits[:]...), op.PaymasterData...)
+	}
+
+	return PackedUserOperation{
+		Sender:             op.Sender,
+		Nonce:              op.Nonce,
+		InitCode:           initCode,
+		CallData:           op.CallData,
+		AccountGasLimits:   accountGasLimits,
+		PreVerificationGas: op.PreVerificationGas,
+		GasFees:            gasFees,
+		PaymasterAndData:   paymasterAndData,
+		Signature:          op.Signature,
+	}, nil
+}
+
+// Unpack unpacks the fields of the operation.
+func (op *PackedUserOperation) Unpack() (UnpackedUserOperation, error) {
+	verificationGasLimit, callGasLimit := UnpackUint128s(op.AccountGasLimits)
+	maxPriorityFeePerGas, maxFeePerGas := UnpackUint128s(op.GasFees)
+
+	unpacked := UnpackedUserOperation{
+		Sender:               op.Sender,
+		Nonce:                op.Nonce,
+		CallData:             op.CallData,
+		CallGasLimit:         callGasLimit,
+		VerificationGasLimit: verificationGasLimit,
+		PreVerificationGas:   op.PreVerificationGas,
+		MaxFeePerGas:         maxFeePerGas,
+		MaxPriorityFeePerGas: maxPriorityFeePerGas,
+		Signature:            op.Signature,
+	}
+
+	if len(op.InitCode) > 0 {
+		if len(op.InitCode) < common.AddressLength {
+			return UnpackedUserOperation{}, fmt.Errorf("init code too short to hold a factory address: %d bytes", len(op.InitCode))
+		}
+		factory := common.BytesToAddress(op.InitCode[:common.AddressLength])
+		unpacked.Factory = &factory
+		unpacked.FactoryData = op.InitCode[common.AddressLength:]
+	}
+
+	if len(op.PaymasterAndData) > 0 {
+		if len(op.PaymasterAndData) < common.AddressLength+32 {
+			return UnpackedUserOperation{}, fmt.Errorf("paymaster data too short to hold a paymaster address and gas limits: %d bytes", len(op.PaymasterAndData))
+		}
+		paymaster := common.BytesToAddress(op.PaymasterAndData[:common.AddressLength])
+		unpacked.Paymaster = &paymaster
+		unpacked.PaymasterVerificationGasLimit, unpacked.PaymasterPostOpGasLimit = UnpackUint128s([32]byte(op.PaymasterAndData[common.AddressLength : common.AddressLength+32]))
+		unpacked.PaymasterData = op.PaymasterAndData[common.AddressLength+32:]
+	}
+
+	return unpacked, nil
+}
+
+// PackUint128s packs two uint128 values into a bytes32, the first one
+// in the high 16 bytes (i.e. `accountGasLimits` packs the verification
+// gas limit then the call gas limit).
+func PackUint128s(high *big.Int, low *big.Int) ([32]byte, error) {
+	var packed [32]byte
+	for i, value := range []*big.Int{high, low} {
+		if value == nil {
+			continue
+		}
+		if value.Sign() < 0 || value.BitLen() > 128 {
+			return packed, fmt.Errorf("value %s does not fit in uint128", value)
+		}
+		value.FillBytes(packed[i*16 : (i+1)*16])
+	}
+
+	return packed, nil
+}
+
+// UnpackUint128s unpacks the two uint128 values of a bytes32 packed
+// with PackUint128s.
+func UnpackUint128s(packed [32]byte) (high *big.Int, low *big.Int) {
+	return new(big.Int).SetBytes(packed[:16]), new(big.Int).SetBytes(packed[16:])
+}
+
+// EncodeHandleOpsV06 encodes the call data of `handleOps` of the
+// EntryPoint v0.6 with given operations and beneficiary of the fees.
+func EncodeHandleOpsV06(ops []UserOperation, beneficiary common.Address) ([]byte, error) {
+	encoded, err := abi.Marshal(handleOpsV06Args{Ops: ops, Beneficiary: beneficiary}, handleOpsV06Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(abi.EncodeSignature(handleOpsV06Signature), encoded...), nil
+}
+
+// DecodeHandleOpsV06 decodes the call data of `handleOps` of the
+// EntryPoint v0.6 into its operations and beneficiary.
+func DecodeHandleOpsV06(data []byte) ([]UserOperation, common.Address, error) {
+	decoded, err := abi.DecodeWithSignature(handleOpsV06Signature, data)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	var args handleOpsV06Args
+	err = abi.Parse(decoded, &args)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	return args.Ops, args.Beneficiary, nil
+}
+
+// EncodeHandleOpsV07 encodes the call data of `handleOps` of the
+// EntryPoint v0.7 with given operations and beneficiary of the fees.
+func EncodeHandleOpsV07(ops []PackedUserOperation, beneficiary common.Address) ([]byte, error) {
+	encoded, err := abi.Marshal(handleOpsV07Args{Ops: ops, Beneficiary: beneficiary}, handleOpsV07Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(abi.EncodeSignature(handleOpsV07Signature), encoded...), nil
+}
+
+// DecodeHandleOpsV07 decodes the call data of `handleOps` of the
+// EntryPoint v0.7 into its operations and beneficiary.
+func DecodeHandleOpsV07(data []byte) ([]PackedUserOperation, common.Address, error) {
+	decoded, err := abi.DecodeWithSignature(handleOpsV07Signature, data)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	var args handleOpsV07Args
+	err = abi.Parse(decoded, &args)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	return args.Ops, args.Beneficiary, nil
+}
+
+// userOpHash hashes a packed user operation with the EntryPoint and
+// chain it is valid for.
+func userOpHash(packed []byte, entryPoint common.Address, chainID *big.Int) (common.Hash, error) {
+	encoded, err := abi.Encode([]string{"bytes32", "address", "uint256"}, crypto.Keccak256(packed), entryPoint, chainID)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error encoding user operation hash: %w", err)
+	}
+
+	return crypto.Keccak256Hash(encoded), nil
+}
@@ -0,0 +1,97 @@
+package erc4337_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi/erc4337"
+)
+
+func ExampleUnpackedUserOperation_Pack() {
+	factory := common.HexToAddress("0x9406Cc6185a346906296840746125a0E44976454")
+	op := erc4337.UnpackedUserOperation{
+		Sender:               common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+		Nonce:                big.NewInt(0),
+		Factory:              &factory,
+		FactoryData:          common.FromHex("0x5fbfb9cf"),
+		CallData:             common.FromHex("0xb61d27f6"),
+		CallGasLimit:         big.NewInt(100000),
+		VerificationGasLimit: big.NewInt(500000),
+		PreVerificationGas:   big.NewInt(50000),
+		MaxFeePerGas:         big.NewInt(30000000000),
+		MaxPriorityFeePerGas: big.NewInt(1000000000),
+		Signature:            []byte{},
+	}
+
+	packed, err := op.Pack()
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%x\n", packed.AccountGasLimits)
+	fmt.Printf("%x\n", packed.InitCode)
+
+	hash, err := packed.Hash(erc4337.EntryPointV07, big.NewInt(1))
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(hash)
+
+	// Output:
+	// 0000000000000000000000000007a120000000000000000000000000000186a0
+	// 9406cc6185a346906296840746125a0e449764545fbfb9cf
+	// 0xb32ad76aaed54320e77be16ba72e03e885d50bafca75317cb5b46f440819cd97
+}
+
+func ExampleUnpackedUserOperation_Pack_missingPaymaster() {
+	op := erc4337.UnpackedUserOperation{
+		Sender:        common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+		Nonce:         big.NewInt(0),
+		PaymasterData: common.FromHex("0xdeadbeef"),
+	}
+
+	_, err := op.Pack()
+	fmt.Println(err)
+
+	// Output: paymaster data of 4 bytes without a paymaster
+}
+
+func ExampleEncodeHandleOpsV06() {
+	op := erc4337.UserOperation{
+		Sender:               common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+		Nonce:                big.NewInt(1),
+		InitCode:             []byte{},
+		CallData:             common.FromHex("0xb61d27f6"),
+		CallGasLimit:         big.NewInt(100000),
+		VerificationGasLimit: big.NewInt(500000),
+		PreVerificationGas:   big.NewInt(50000),
+		MaxFeePerGas:         big.NewInt(30000000000),
+		MaxPriorityFeePerGas: big.NewInt(1000000000),
+		PaymasterAndData:     []byte{},
+		Signature:            []byte{0x01},
+	}
+	beneficiary := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	data, err := erc4337.EncodeHandleOpsV06([]erc4337.UserOperation{op}, beneficiary)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%x\n", data[:4])
+
+	ops, decodedBeneficiary, err := erc4337.DecodeHandleOpsV06(data)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(len(ops), ops[0].Nonce, ops[0].CallGasLimit, decodedBeneficiary)
+
+	hash, err := ops[0].Hash(erc4337.EntryPointV06, big.NewInt(1))
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(hash)
+
+	// Output:
+	// 1fad948c
+	// 1 1 100000 0x000000000000000000000000000000000000dEaD
+	// 0x8b157f2b10354896b779bff583bfe4ab2b850ce07967dade5a2fbaf755cc795f
+}
@@ -0,0 +1,158 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// ErrOverflow is returned, wrapped in a *ParseError, when a decoded
+// integer does not fit in the field it is parsed into (i.e. 300 into a
+// uint8 field). Options.AllowNarrowing disables the check.
+var ErrOverflow = errors.New("integer overflow")
+
+// ErrInvalidAddress is returned, wrapped in a *ParseError, when parsing
+// with Options.StrictAddresses a malformed address string, or a mixed
+// case one failing its EIP-55 checksum.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// ParseError describes a decoded value that could not be parsed into
+// its Go target. It is returned by Parse and its variants and can be
+// retrieved with errors.As.
+type ParseError struct {
+	// Path locates the target from the parsed struct, i.e.
+	// `Order.Items[3].Price`.
+	Path string
+	// Type is the Go type of the target.
+	Type reflect.Type
+	// ValueType is the Go type of the decoded value, nil when the
+	// decoded value is nil.
+	ValueType reflect.Type
+	// ABIType is the ABI type of the decoded value, only known when
+	// parsing with components.
+	ABIType string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	var details []string
+	if e.Type != nil {
+		details = append(details, "expected "+e.Type.String())
+	}
+	if e.ValueType != nil {
+		details = append(details, "got "+e.ValueType.String())
+	}
+	if e.ABIType != "" {
+		details = append(details, "abi type "+e.ABIType)
+	}
+
+	msg := "[parseStruct] error parsing field " + strings.TrimPrefix(e.Path, ".")
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, ", ") + ")"
+		// Type mismatches would only repeat the types given above.
+		if _, ok := e.Err.(*mismatchError); ok {
+			return msg
+		}
+	}
+
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError wraps an error returned while setting a value of type t,
+// unless it already is a *ParseError coming from a nested value.
+func newParseError(err error, t reflect.Type, value any) error {
+	if _, ok := err.(*ParseError); ok {
+		return err
+	}
+
+	return &ParseError{Type: t, ValueType: reflect.TypeOf(value), Err: err}
+}
+
+// prependPath prepends a path segment (i.e. `.Items` or `[3]`) to the
+// path of a *ParseError. The ABI type of the component describing the
+// segment is recorded when the error does not hold one yet, stripping
+// the array suffixes of the element indexes already in the path.
+func prependPath(err error, segment string, component *Component) error {
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		parseErr = &ParseError{Err: err}
+	}
+
+	if parseErr.ABIType == "" && component != nil {
+		typeStr := component.CanonicalType()
+		for i := 0; i < leadingIndexes(parseErr.Path); i++ {
+			if index := strings.LastIndex(typeStr, "["); index != -1 {
+				typeStr = typeStr[:index]
+			}
+		}
+		parseErr.ABIType = typeStr
+	}
+	parseErr.Path = segment + parseErr.Path
+
+	return parseErr
+}
+
+// leadingIndexes counts the index segments at the start of a path.
+func leadingIndexes(path string) int {
+	var count int
+	for strings.HasPrefix(path, "[") {
+		closeIndex := strings.Index(path, "]")
+		if closeIndex == -1 {
+			break
+		}
+		path = path[closeIndex+1:]
+		count++
+	}
+
+	return count
+}
+
+// mismatchError is returned by setters given a decoded value of an
+// unexpected Go type.
+type mismatchError struct {
+	expected string
+	value    any
+}
+
+// Error implements the error interface.
+func (e *mismatchError) Error() string {
+	return fmt.Sprintf("expected %s, got %T", e.expected, e.value)
+}
+
+// newMismatchError describes a decoded value which is not of the
+// expected kind.
+func newMismatchError(expected string, value any) error {
+	return &mismatchError{expected: expected, value: value}
+}
+
+// newOverflowError describes a decoded value which does not fit in a
+// target of type t, giving the range of t.
+func newOverflowError(value any, t reflect.Type) error {
+	return fmt.Errorf("%w: value %v of type %T does not fit in %s (%s)", ErrOverflow, value, value, t, integerRange(t))
+}
+
+// integerRange describes the values held by integer (or float) type t.
+func integerRange(t reflect.Type) string {
+	bits := uint(t.Bits())
+	switch {
+	case isIntKind(t.Kind()):
+		limit := new(big.Int).Lsh(big.NewInt(1), bits-1)
+		return fmt.Sprintf("range %s to %s", new(big.Int).Neg(limit), limit.Sub(limit, big.NewInt(1)))
+	case isUintKind(t.Kind()):
+		limit := new(big.Int).Lsh(big.NewInt(1), bits)
+		return fmt.Sprintf("range 0 to %s", limit.Sub(limit, big.NewInt(1)))
+	case t.Kind() == reflect.Float32:
+		return "exact integers up to 2^24"
+	}
+
+	return "exact integers up to 2^53"
+}
@@ -0,0 +1,382 @@
+package abi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Fragment describes a function, event, error or constructor of a
+// contract ABI. Its layout follows the entries of Solidity JSON ABIs.
+type Fragment struct {
+	Type            string      `json:"type"`
+	Name            string      `json:"name,omitempty"`
+	Inputs          []Component `json:"inputs"`
+	Outputs         []Component `json:"outputs,omitempty"`
+	StateMutability string      `json:"stateMutability,omitempty"`
+	Anonymous       bool        `json:"anonymous,omitempty"`
+}
+
+// fragmentKinds lists the keywords that may start a human-readable
+// fragment.
+var fragmentKinds = []string{"function", "event", "error", "constructor", "fallback", "receive"}
+
+// paramModifiers lists keywords that may follow a parameter type.
+var paramModifiers = map[string]bool{"memory": true, "calldata": true, "storage": true, "payable": true}
+
+// stateMutabilities lists the state mutability keywords of functions.
+var stateMutabilities = map[string]bool{"pure": true, "view": true, "payable": true, "nonpayable": true}
+
+// fixedTypeRegexp matches fixed point core types (i.e. `ufixed128x18`).
+var fixedTypeRegexp = regexp.MustCompile(`^u?fixed([0-9]+)x([0-9]+)$`)
+
+// ParseFragment parses a human-readable (ethers.js style) fragment,
+// i.e. `function transfer(address to, uint256 amount) returns (bool)`
+// or `event Transfer(address indexed from, address indexed to, uint256 value)`.
+// Fragments without a leading keyword (i.e. `transfer(address,uint256)`)
+// are parsed as functions. Parsed fragments are cached, so that parsing
+// the same fragment again returns a copy of the cached one.
+func ParseFragment(fragment string) (*Fragment, error) {
+	parsed := cached(cachedFragment, fragment, func() cachedParse {
+		result, err := parseFragment(fragment)
+		return cachedParse{fragment: result, err: err}
+	})
+	if parsed.err != nil {
+		return nil, parsed.err
+	}
+
+	return parsed.fragment.clone(), nil
+}
+
+// parseFragment parses a human-readable fragment for ParseFragment.
+func parseFragment(fragment string) (*Fragment, error) {
+	s := strings.Join(strings.Fields(fragment), " ")
+	if s == "" {
+		return nil, fmt.Errorf("empty fragment")
+	}
+
+	result := &Fragment{Type: "function"}
+	for _, kind := range fragmentKinds {
+		if s == kind || strings.HasPrefix(s, kind+" ") || strings.HasPrefix(s, kind+"(") {
+			result.Type = kind
+			s = strings.TrimSpace(s[len(kind):])
+			break
+		}
+	}
+
+	openParIndex := strings.Index(s, "(")
+	if openParIndex == -1 {
+		if result.Type == "fallback" || result.Type == "receive" {
+			s = "()" + s
+			openParIndex = 0
+		} else {
+			return nil, fmt.Errorf("no opening parenthesis found in fragment: %s", fragment)
+		}
+	}
+
+	result.Name = strings.TrimSpace(s[:openParIndex])
+	if result.Name != "" && !isIdentifier(result.Name) {
+		return nil, fmt.Errorf("invalid fragment name: %q", result.Name)
+	}
+	if result.Name == "" && (result.Type == "function" || result.Type == "event" || result.Type == "error") {
+		return nil, fmt.Errorf("missing %s name in fragment: %s", result.Type, fragment)
+	}
+
+	closeParIndex := matchingParenthesis(s, openParIndex)
+	if closeParIndex == -1 {
+		return nil, fmt.Errorf("no closing parenthesis found in fragment: %s", fragment)
+	}
+
+	inputs, err := parseParams(s[openParIndex+1 : closeParIndex])
+	if err != nil {
+		return nil, err
+	}
+	result.Inputs = inputs
+
+	rest := strings.TrimSpace(s[closeParIndex+1:])
+	for rest != "" {
+		if strings.HasPrefix(rest, "returns") {
+			rest = strings.TrimSpace(strings.TrimPrefix(rest, "returns"))
+			if !strings.HasPrefix(rest, "(") {
+				return nil, fmt.Errorf("no opening parenthesis found after returns: %s", fragment)
+			}
+			closeIndex := matchingParenthesis(rest, 0)
+			if closeIndex == -1 {
+				return nil, fmt.Errorf("no closing parenthesis found after returns: %s", fragment)
+			}
+			outputs, err := parseParams(rest[1:closeIndex])
+			if err != nil {
+				return nil, err
+			}
+			result.Outputs = outputs
+			rest = strings.TrimSpace(rest[closeIndex+1:])
+			continue
+		}
+
+		word, remaining, _ := strings.Cut(rest, " ")
+		rest = strings.TrimSpace(remaining)
+		switch {
+		case word == "anonymous" && result.Type == "event":
+			result.Anonymous = true
+		case stateMutabilities[word]:
+			result.StateMutability = word
+		case word == "external" || word == "public" || word == "virtual" || word == "override":
+		default:
+			return nil, fmt.Errorf("unexpected %q in fragment: %s", word, fragment)
+		}
+	}
+
+	if (result.Type == "function" || result.Type == "constructor" || result.Type == "fallback") && result.StateMutability == "" {
+		result.StateMutability = "nonpayable"
+	}
+	if result.Type == "receive" {
+		result.StateMutability = "payable"
+	}
+
+	return result, nil
+}
+
+// MustParseFragment is like ParseFragment but panics on error. It is
+// intended for package level fragment declarations.
+func MustParseFragment(fragment string) *Fragment {
+	result, err := ParseFragment(fragment)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// Signature returns the canonical signature of the fragment, i.e.
+// `transfer(address,uint256)`.
+func (f *Fragment) Signature() string {
+	return f.Name + "(" + strings.Join(f.InputTypes(), ",") + ")"
+}
+
+// Selector returns the 4-byte selector of the fragment signature.
+func (f *Fragment) Selector() []byte {
+	hash := signatureHash(f.Signature())
+	return hash[:4]
+}
+
+// Topic returns the keccak256 hash of the fragment signature, which is
+// the first topic of non-anonymous event logs.
+func (f *Fragment) Topic() common.Hash {
+	return signatureHash(f.Signature())
+}
+
+// clone returns a deep copy of the fragment.
+func (f *Fragment) clone() *Fragment {
+	clone := *f
+	clone.Inputs = cloneComponents(f.Inputs)
+	clone.Outputs = cloneComponents(f.Outputs)
+
+	return &clone
+}
+
+// InputTypes returns the canonical type strings of the fragment inputs.
+func (f *Fragment) InputTypes() []string {
+	return ComponentTypes(f.Inputs)
+}
+
+// OutputTypes returns the canonical type strings of the fragment outputs.
+func (f *Fragment) OutputTypes() []string {
+	return ComponentTypes(f.Outputs)
+}
+
+// String returns the human-readable representation of the fragment.
+func (f *Fragment) String() string {
+	var builder strings.Builder
+	builder.WriteString(f.Type)
+	if f.Name != "" {
+		builder.WriteString(" " + f.Name)
+	}
+	builder.WriteString("(" + formatParams(f.Inputs) + ")")
+	if f.Anonymous {
+		builder.WriteString(" anonymous")
+	}
+	if f.StateMutability != "" && f.StateMutability != "nonpayable" {
+		builder.WriteString(" " + f.StateMutability)
+	}
+	if len(f.Outputs) > 0 {
+		builder.WriteString(" returns (" + formatParams(f.Outputs) + ")")
+	}
+
+	return builder.String()
+}
+
+// parseParams parses a comma separated list of human-readable
+// parameters (i.e. `address to, (uint256 a, bytes b)[] items`).
+func parseParams(paramsStr string) ([]Component, error) {
+	paramsStr = strings.TrimSpace(paramsStr)
+	if paramsStr == "" {
+		return []Component{}, nil
+	}
+
+	var params []Component
+	for _, paramStr := range SplitParams(paramsStr) {
+		param, err := parseParam(strings.TrimSpace(paramStr))
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
+// parseParam parses a single human-readable parameter, i.e.
+// `address indexed from` or `tuple(uint256 a, bytes b)[2] items`.
+func parseParam(paramStr string) (Component, error) {
+	var param Component
+	if paramStr == "" {
+		return param, fmt.Errorf("empty parameter")
+	}
+
+	var rest string
+	if strings.HasPrefix(paramStr, "(") || strings.HasPrefix(paramStr, "tuple(") || strings.HasPrefix(paramStr, "tuple (") {
+		openParIndex := strings.Index(paramStr, "(")
+		closeParIndex := matchingParenthesis(paramStr, openParIndex)
+		if closeParIndex == -1 {
+			return param, fmt.Errorf("no closing parenthesis found in parameter: %s", paramStr)
+		}
+
+		components, err := parseParams(paramStr[openParIndex+1 : closeParIndex])
+		if err != nil {
+			return param, err
+		}
+
+		suffix, remaining := splitArraySuffix(paramStr[closeParIndex+1:])
+		param.Type = "tuple" + suffix
+		param.Components = components
+		rest = remaining
+	} else {
+		typeStr, remaining, _ := strings.Cut(paramStr, " ")
+		normalized, err := normalizeType(typeStr)
+		if err != nil {
+			return param, err
+		}
+		param.Type = normalized
+		rest = remaining
+	}
+
+	for _, word := range strings.Fields(rest) {
+		switch {
+		case word == "indexed":
+			param.Indexed = true
+		case paramModifiers[word]:
+		case param.Name == "" && isIdentifier(word):
+			param.Name = word
+		default:
+			return param, fmt.Errorf("unexpected %q in parameter: %s", word, paramStr)
+		}
+	}
+
+	return param, nil
+}
+
+// normalizeType validates a core type string with optional array
+// suffixes and expands its aliases (i.e. `uint[]` becomes `uint256[]`).
+func normalizeType(typeStr string) (string, error) {
+	base := typeStr
+	suffix := ""
+	if index := strings.Index(typeStr, "["); index != -1 {
+		base = typeStr[:index]
+		suffix = typeStr[index:]
+		if rest, remaining := splitArraySuffix(suffix); rest != suffix || remaining != "" {
+			return "", fmt.Errorf("invalid array definition: %s", typeStr)
+		}
+	}
+
+	switch base {
+	case "uint", "int":
+		base += "256"
+	case "fixed", "ufixed":
+		base += "128x18"
+	case "byte":
+		base = "bytes1"
+	}
+
+	if _, ok := validCoreTypes[base]; !ok && !fixedTypeRegexp.MatchString(base) {
+		return "", fmt.Errorf("invalid parameter type: %s", typeStr)
+	}
+
+	return base + suffix, nil
+}
+
+// splitArraySuffix splits the leading array suffixes (i.e. `[][3]`)
+// from the rest of given string.
+func splitArraySuffix(s string) (string, string) {
+	end := 0
+	for end < len(s) && s[end] == '[' {
+		closeIndex := strings.Index(s[end:], "]")
+		if closeIndex == -1 {
+			break
+		}
+		size := s[end+1 : end+closeIndex]
+		if strings.Trim(size, "0123456789") != "" {
+			break
+		}
+		end += closeIndex + 1
+	}
+
+	return s[:end], strings.TrimSpace(s[end:])
+}
+
+// matchingParenthesis returns the index of the parenthesis closing the
+// one at given index, or -1 if there is none.
+func matchingParenthesis(s string, openIndex int) int {
+	depth := 0
+	for i := openIndex; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// isIdentifier checks whether given string is a valid Solidity
+// identifier.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, char := range s {
+		isLetter := char == '_' || char == '$' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+		isDigit := char >= '0' && char <= '9'
+		if !isLetter && (i == 0 || !isDigit) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatParams formats components as human-readable parameters.
+func formatParams(components []Component) string {
+	params := make([]string, len(components))
+	for i, component := range components {
+		param := component.Type
+		if strings.HasPrefix(component.Type, "tuple") {
+			param = "(" + formatParams(component.Components) + ")" + strings.TrimPrefix(component.Type, "tuple")
+		}
+		if component.Indexed {
+			param += " indexed"
+		}
+		if component.Name != "" {
+			param += " " + component.Name
+		}
+		params[i] = param
+	}
+
+	return strings.Join(params, ", ")
+}
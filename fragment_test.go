@@ -0,0 +1,92 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleParseFragment() {
+	fragment, err := abi.ParseFragment("function transfer(address to, uint amount) external returns (bool)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(fragment.Type, fragment.Name, fragment.StateMutability)
+	fmt.Println(fragment.Signature(), common.Bytes2Hex(fragment.Selector()))
+	fmt.Println(fragment.InputTypes(), fragment.OutputTypes())
+	fmt.Println(fragment)
+
+	// Output:
+	// function transfer nonpayable
+	// transfer(address,uint256) a9059cbb
+	// [address uint256] [bool]
+	// function transfer(address to, uint256 amount) returns (bool)
+}
+
+func ExampleParseFragment_event() {
+	fragment, err := abi.ParseFragment("event Transfer(address indexed from, address indexed to, uint256 value)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(fragment.Signature(), fragment.Topic())
+	for _, input := range fragment.Inputs {
+		fmt.Println(input.Name, input.Type, input.Indexed)
+	}
+
+	// Output:
+	// Transfer(address,address,uint256) 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+	// from address true
+	// to address true
+	// value uint256 false
+}
+
+func ExampleParseFragment_tuples() {
+	fragment, err := abi.ParseFragment(
+		"function getOrders(uint256 id) view returns (tuple(address maker, (address recipient, uint16 bps)[] fees) order, bytes32 hash)",
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(fragment.StateMutability, fragment.OutputTypes())
+
+	maker := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	encoded, err := abi.Encode(
+		fragment.OutputTypes(),
+		[]any{&maker, []any{[]any{&maker, big.NewInt(30)}}},
+		common.HexToHash("0x01").Bytes(),
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(fragment.OutputTypes(), encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var result struct {
+		Hash  []byte `abi:"hash"`
+		Order struct {
+			Maker common.Address `abi:"maker"`
+			Fees  []struct {
+				Bps       *big.Int       `abi:"bps"`
+				Recipient common.Address `abi:"recipient"`
+			} `abi:"fees"`
+		} `abi:"order"`
+	}
+	err = abi.ParseWithComponents(decoded, fragment.Outputs, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(result.Order.Maker, result.Order.Fees[0].Bps, common.BytesToHash(result.Hash))
+
+	// Output:
+	// view [(address,(address,uint16)[]) bytes32]
+	// 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 30 0x0000000000000000000000000000000000000000000000000000000000000001
+}
@@ -0,0 +1,98 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// functionPointerLength is the byte length of a `function` value.
+const functionPointerLength = common.AddressLength + 4
+
+var functionPointerType = reflect.TypeOf(FunctionPointer{})
+
+// FunctionPointer is a value of the Solidity external function type
+// (`function` in ABIs), which points to a function of a contract. It is
+// encoded in 24 bytes as the address of the contract followed by the
+// selector of the function, left aligned like bytes24.
+//
+// Decode returns `function` values as FunctionPointer, which Parse sets
+// into FunctionPointer fields (also accepting bytes24 values), and
+// Encode accepts FunctionPointer, *FunctionPointer, [24]byte and []byte
+// values.
+type FunctionPointer struct {
+	Address  common.Address
+	Selector [4]byte
+}
+
+// NewFunctionPointer returns the pointer to the function with given
+// signature, either human-readable or canonical, of the contract at
+// given address.
+func NewFunctionPointer(address common.Address, signature string) FunctionPointer {
+	return FunctionPointer{Address: address, Selector: Selector(signature)}
+}
+
+// Bytes returns the 24 bytes encoding of the function pointer.
+func (f FunctionPointer) Bytes() [functionPointerLength]byte {
+	var b [functionPointerLength]byte
+	copy(b[:], f.Address[:])
+	copy(b[common.AddressLength:], f.Selector[:])
+
+	return b
+}
+
+// String returns the 0x prefixed hex encoding of the function pointer.
+func (f FunctionPointer) String() string {
+	b := f.Bytes()
+	return hexutil.Encode(b[:])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, so that
+// function pointers are written as hex strings in JSON.
+func (f FunctionPointer) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (f *FunctionPointer) UnmarshalText(text []byte) error {
+	b, err := hexutil.Decode(string(text))
+	if err != nil || len(b) != functionPointerLength {
+		return fmt.Errorf("invalid function pointer %q: expected %d hex encoded bytes", text, functionPointerLength)
+	}
+
+	*f, err = functionPointerFromBytes(b)
+	return err
+}
+
+// functionPointerFromBytes reads a function pointer from the first 24
+// bytes of b.
+func functionPointerFromBytes(b []byte) (FunctionPointer, error) {
+	if len(b) < functionPointerLength {
+		return FunctionPointer{}, fmt.Errorf("function pointers hold %d bytes, got %d", functionPointerLength, len(b))
+	}
+
+	var f FunctionPointer
+	copy(f.Address[:], b[:common.AddressLength])
+	copy(f.Selector[:], b[common.AddressLength:functionPointerLength])
+
+	return f, nil
+}
+
+// setFunctionPointer sets a decoded `function` value, or bytes24 value,
+// into a FunctionPointer target.
+func setFunctionPointer(_ *parseState, target reflect.Value, value any, _ []Component) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return newMismatchError("function pointer", value)
+	}
+
+	f, err := functionPointerFromBytes(b)
+	if err != nil {
+		return err
+	}
+	target.Set(reflect.ValueOf(f))
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleFunctionPointer() {
+	vault := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	callback := abi.NewFunctionPointer(vault, "onFlashLoan(uint256)")
+
+	data, err := abi.EncodeWithSignature("flashLoan(uint256,function)", big.NewInt(1000), callback)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.DecodeWithSignature("flashLoan(uint256,function)", data)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var loan struct {
+		Amount   *big.Int
+		Callback abi.FunctionPointer
+	}
+	err = abi.Parse(decoded, &loan)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(loan.Callback.Address, common.Bytes2Hex(loan.Callback.Selector[:]))
+	fmt.Println(loan.Callback == callback)
+
+	// Output:
+	// 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 58080ce3
+	// true
+}
@@ -0,0 +1,191 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DecoderFunc converts a raw decoded element (e.g. *big.Int, string,
+// []byte or []any) into the final value of a registered Go type.
+type DecoderFunc func(decoded any) (any, error)
+
+// ConvertFunc converts a raw decoded element of a registered type (e.g.
+// *big.Int or string) into a value of a registered Go type.
+type ConvertFunc func(value any) (any, error)
+
+// ABIUnmarshaler is implemented by types that convert a raw decoded
+// element (e.g. *big.Int, string, []byte or []any) into themselves.
+// Parse calls UnmarshalABI instead of applying the built-in
+// conversions, so that i.e. uint256 timestamps can be parsed into a
+// time.Time based type.
+type ABIUnmarshaler interface {
+	UnmarshalABI(v any) error
+}
+
+// ABIMarshaler is implemented by types that convert themselves into a
+// value accepted by Encode (e.g. *big.Int, *common.Address, []byte or
+// []any). Marshal and Encode call MarshalABI before applying the
+// built-in conversions.
+type ABIMarshaler interface {
+	MarshalABI() (any, error)
+}
+
+var (
+	unmarshalerType = reflect.TypeOf((*ABIUnmarshaler)(nil)).Elem()
+	marshalerType   = reflect.TypeOf((*ABIMarshaler)(nil)).Elem()
+)
+
+// decoderHooks holds the decoders registered with RegisterDecoder.
+var decoderHooks = struct {
+	sync.RWMutex
+	m map[reflect.Type]DecoderFunc
+}{m: make(map[reflect.Type]DecoderFunc)}
+
+// RegisterDecoder registers a custom decoder for the given Go type.
+// Whenever Parse finds a struct field or slice element of type t, the
+// raw decoded element is handed to fn and the returned value is set
+// instead of applying the built-in conversions. Registering a nil fn
+// removes the decoder for t.
+func RegisterDecoder(t reflect.Type, fn func(decoded any) (any, error)) {
+	decoderHooks.Lock()
+	defer decoderHooks.Unlock()
+
+	if fn == nil {
+		delete(decoderHooks.m, t)
+	} else {
+		decoderHooks.m[t] = fn
+	}
+
+	resetPlans()
+}
+
+// lookupDecoder returns the decoder registered for given type, if any.
+func lookupDecoder(t reflect.Type) (DecoderFunc, bool) {
+	decoderHooks.RLock()
+	defer decoderHooks.RUnlock()
+
+	fn, ok := decoderHooks.m[t]
+	return fn, ok
+}
+
+// converterKey identifies a converter registered with RegisterConverter.
+type converterKey struct {
+	from, to reflect.Type
+}
+
+// converterHooks holds the converters registered with RegisterConverter.
+var converterHooks = struct {
+	sync.RWMutex
+	m map[converterKey]ConvertFunc
+}{m: make(map[converterKey]ConvertFunc)}
+
+// RegisterConverter registers a converter from the Go type of a raw
+// decoded element (e.g. *big.Int, string or []byte) to the Go type of a
+// struct field or slice element, such as a decimal, an address wrapper
+// or a protobuf timestamp. Whenever Parse sets a decoded element of
+// type from into a target of type to, fn is called instead of applying
+// the built-in conversions, which still apply to elements of any other
+// type. Registered decoders and ABIUnmarshaler implementations take
+// precedence over converters. Registering a nil fn removes the
+// converter from from to to.
+func RegisterConverter(from, to reflect.Type, fn ConvertFunc) {
+	converterHooks.Lock()
+	defer converterHooks.Unlock()
+
+	key := converterKey{from: from, to: to}
+	if fn == nil {
+		delete(converterHooks.m, key)
+	} else {
+		converterHooks.m[key] = fn
+	}
+
+	resetPlans()
+}
+
+// convertersTo returns the converters registered to given type, keyed
+// by the type they convert from, or nil when there are none.
+func convertersTo(t reflect.Type) map[reflect.Type]ConvertFunc {
+	converterHooks.RLock()
+	defer converterHooks.RUnlock()
+
+	var converters map[reflect.Type]ConvertFunc
+	for key, fn := range converterHooks.m {
+		if key.to != t {
+			continue
+		}
+		if converters == nil {
+			converters = make(map[reflect.Type]ConvertFunc)
+		}
+		converters[key.from] = fn
+	}
+
+	return converters
+}
+
+// setWithDecoder runs a registered decoder (or converter) over the
+// decoded element and sets the result into target.
+func setWithDecoder(target reflect.Value, decoded any, fn DecoderFunc) error {
+	result, err := fn(decoded)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	val := reflect.ValueOf(result)
+	if val.Type() != target.Type() {
+		if !val.CanConvert(target.Type()) {
+			return fmt.Errorf("registered hook returned %T, expected %s", result, target.Type())
+		}
+		val = val.Convert(target.Type())
+	}
+	target.Set(val)
+
+	return nil
+}
+
+// isUnmarshaler checks whether values of given type can be parsed with
+// UnmarshalABI, either directly or through their address.
+func isUnmarshaler(t reflect.Type) bool {
+	return (t.Kind() == reflect.Ptr && t.Implements(unmarshalerType)) || reflect.PointerTo(t).Implements(unmarshalerType)
+}
+
+// setUnmarshaler parses a decoded element with the UnmarshalABI method
+// of target, allocating it when it is a nil pointer.
+func setUnmarshaler(_ *parseState, target reflect.Value, value any, _ []Component) error {
+	if target.Kind() == reflect.Ptr && target.Type().Implements(unmarshalerType) {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return target.Interface().(ABIUnmarshaler).UnmarshalABI(value)
+	}
+
+	return target.Addr().Interface().(ABIUnmarshaler).UnmarshalABI(value)
+}
+
+// asMarshaler returns the ABIMarshaler implemented by the addressable
+// value rv or its address, if any.
+func asMarshaler(rv reflect.Value) (ABIMarshaler, bool) {
+	if !rv.IsValid() || !rv.CanAddr() || !rv.Addr().Type().Implements(marshalerType) {
+		return nil, false
+	}
+
+	return rv.Addr().Interface().(ABIMarshaler), true
+}
+
+// marshalWith converts a value with its MarshalABI method.
+func marshalWith(marshaler ABIMarshaler) (reflect.Value, error) {
+	result, err := marshaler.MarshalABI()
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("error marshaling %T: %w", marshaler, err)
+	}
+	if _, ok := result.(ABIMarshaler); ok {
+		return reflect.Value{}, fmt.Errorf("MarshalABI of %T returned ABIMarshaler %T", marshaler, result)
+	}
+
+	return unwrapValue(reflect.ValueOf(result)), nil
+}
@@ -0,0 +1,154 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/omnes-tech/abi"
+)
+
+type USDC struct {
+	amount *big.Int
+}
+
+func (u USDC) String() string {
+	whole, frac := new(big.Int).QuoRem(u.amount, big.NewInt(1_000_000), new(big.Int))
+	return fmt.Sprintf("%s.%06s USDC", whole, frac)
+}
+
+func ExampleRegisterDecoder() {
+	abi.RegisterDecoder(reflect.TypeOf(USDC{}), func(decoded any) (any, error) {
+		amount, ok := decoded.(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("expected *big.Int, got %T", decoded)
+		}
+		return USDC{amount: amount}, nil
+	})
+	defer abi.RegisterDecoder(reflect.TypeOf(USDC{}), nil)
+
+	typeStrs := []string{"uint256", "uint256[]"}
+	encoded, err := abi.Encode(typeStrs, big.NewInt(1_500_000), []any{big.NewInt(250_000), big.NewInt(3_000_000)})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var payment struct {
+		Total USDC
+		Parts []USDC
+	}
+	err = abi.Parse(decoded, &payment)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(payment.Total, payment.Parts)
+
+	// Output: 1.500000 USDC [0.250000 USDC 3.000000 USDC]
+}
+
+type Timestamp struct {
+	time.Time
+}
+
+func (t *Timestamp) UnmarshalABI(v any) error {
+	seconds, ok := v.(*big.Int)
+	if !ok {
+		return fmt.Errorf("expected *big.Int, got %T", v)
+	}
+	t.Time = time.Unix(seconds.Int64(), 0).UTC()
+	return nil
+}
+
+func (t Timestamp) MarshalABI() (any, error) {
+	return big.NewInt(t.Unix()), nil
+}
+
+func ExampleABIUnmarshaler() {
+	type auction struct {
+		Start Timestamp
+		End   *Timestamp
+	}
+
+	start := Timestamp{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	end := Timestamp{start.Add(48 * time.Hour)}
+	encoded, err := abi.Marshal(auction{Start: start, End: &end}, "(uint64,uint64)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode([]string{"uint64", "uint64"}, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(decoded)
+
+	var result auction
+	err = abi.Parse(decoded, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(result.Start.Format(time.DateOnly), result.End.Format(time.DateOnly))
+
+	// Output:
+	// [1704067200 1704240000]
+	// 2024-01-01 2024-01-03
+}
+
+type Decimal struct {
+	coefficient *big.Int
+	exponent    int
+}
+
+func (d Decimal) String() string {
+	return new(big.Float).SetInt(d.coefficient).Text('f', 0) + "e" + fmt.Sprint(d.exponent)
+}
+
+type Account struct {
+	address common.Address
+}
+
+func ExampleRegisterConverter() {
+	abi.RegisterConverter(reflect.TypeOf(&big.Int{}), reflect.TypeOf(Decimal{}), func(value any) (any, error) {
+		return Decimal{coefficient: value.(*big.Int), exponent: -18}, nil
+	})
+	defer abi.RegisterConverter(reflect.TypeOf(&big.Int{}), reflect.TypeOf(Decimal{}), nil)
+
+	abi.RegisterConverter(reflect.TypeOf(""), reflect.TypeOf(Account{}), func(value any) (any, error) {
+		return Account{address: common.HexToAddress(value.(string))}, nil
+	})
+	defer abi.RegisterConverter(reflect.TypeOf(""), reflect.TypeOf(Account{}), nil)
+
+	typeStrs := []string{"address", "uint256"}
+	encoded, err := abi.Encode(typeStrs, common.HexToAddress("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"), big.NewInt(2_500_000_000_000_000_000))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var deposit struct {
+		Owner  Account
+		Amount Decimal
+	}
+	err = abi.Parse(decoded, &deposit)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(deposit.Owner.address, deposit.Amount)
+
+	// Output: 0x5B38Da6a701c568545dCfcB03FcB875f56beddC4 2500000000000000000e-18
+}
@@ -0,0 +1,434 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// MarshalJSON converts decoded values of given components into JSON
+// which can be sent over the wire without losing precision. Values are
+// held in an object keyed by the component names (or positions, for
+// unnamed components) in the order of the components, nested tuples
+// become nested objects and arrays become JSON arrays. Integers and
+// fixed point numbers are written as decimal strings, bytes, fixed
+// bytes and functions as 0x prefixed hex strings and addresses as
+// checksummed hex strings, so that the output of the same values is
+// always the same.
+func MarshalJSON(decoded []any, components []Component) ([]byte, error) {
+	var buf bytes.Buffer
+	err := appendJSONTuple(&buf, decoded, components)
+	if err != nil {
+		return nil, fmt.Errorf("[MarshalJSON] %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON converts JSON written by MarshalJSON back into the
+// values returned by Decode for given components, suitable for Parse
+// and Encode. Tuples may also be given as JSON arrays holding their
+// members in order, and integers as JSON numbers or 0x prefixed hex
+// strings.
+func UnmarshalJSON(data []byte, components []Component) ([]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, fmt.Errorf("[UnmarshalJSON] invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("[UnmarshalJSON] invalid JSON: unexpected data after top-level value")
+	}
+
+	decoded, err := fromJSONTuple(value, components)
+	if err != nil {
+		return nil, fmt.Errorf("[UnmarshalJSON] %w", err)
+	}
+
+	return decoded, nil
+}
+
+// jsonKeys returns the keys of given components in JSON objects, which
+// are their names or, for unnamed components, their positions.
+func jsonKeys(components []Component) ([]string, error) {
+	keys := make([]string, len(components))
+	seen := make(map[string]bool, len(components))
+	for i, component := range components {
+		key := component.Name
+		if key == "" {
+			key = strconv.Itoa(i)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate component name %q", key)
+		}
+		seen[key] = true
+		keys[i] = key
+	}
+
+	return keys, nil
+}
+
+// appendJSONTuple writes the members of a tuple as a JSON object.
+func appendJSONTuple(buf *bytes.Buffer, members []any, components []Component) error {
+	if len(members) != len(components) {
+		return fmt.Errorf("number of decoded values (%d) does not match number of components (%d)", len(members), len(components))
+	}
+	keys, err := jsonKeys(components)
+	if err != nil {
+		return err
+	}
+
+	buf.WriteByte('{')
+	for i, component := range components {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		appendJSONString(buf, keys[i])
+		buf.WriteByte(':')
+
+		err = appendJSONValue(buf, members[i], component.Type, component.Components)
+		if err != nil {
+			return fmt.Errorf("error marshaling %s: %w", keys[i], err)
+		}
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// appendJSONValue writes a decoded value of given component type.
+func appendJSONValue(buf *bytes.Buffer, value any, typeStr string, components []Component) error {
+	if strings.HasSuffix(typeStr, "]") {
+		elems, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected array for %s, got %T", typeStr, value)
+		}
+
+		elemTypeStr := typeStr[:strings.LastIndex(typeStr, "[")]
+		buf.WriteByte('[')
+		for i, elem := range elems {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			err := appendJSONValue(buf, elem, elemTypeStr, components)
+			if err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		buf.WriteByte(']')
+
+		return nil
+	}
+
+	if typeStr == "tuple" {
+		members, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected tuple, got %T", value)
+		}
+
+		return appendJSONTuple(buf, members, components)
+	}
+
+	return appendJSONCore(buf, value, typeStr)
+}
+
+// appendJSONCore writes a decoded value of given non-array and
+// non-tuple type.
+func appendJSONCore(buf *bytes.Buffer, value any, typeStr string) error {
+	switch {
+	case typeStr == "address":
+		var address common.Address
+		switch value := value.(type) {
+		case string:
+			if !common.IsHexAddress(value) {
+				return fmt.Errorf("invalid address %q", value)
+			}
+			address = common.HexToAddress(value)
+		case common.Address:
+			address = value
+		case *common.Address:
+			address = *value
+		default:
+			return fmt.Errorf("expected address, got %T", value)
+		}
+		appendJSONString(buf, address.Hex())
+
+	case typeStr == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected bool, got %T", value)
+		}
+		buf.WriteString(strconv.FormatBool(b))
+
+	case typeStr == "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", value)
+		}
+		appendJSONString(buf, s)
+
+	case typeStr == "function":
+		f, ok := value.(FunctionPointer)
+		if !ok {
+			return fmt.Errorf("expected FunctionPointer, got %T", value)
+		}
+		appendJSONString(buf, f.String())
+
+	case strings.HasPrefix(typeStr, "bytes"):
+		var b []byte
+		switch value := value.(type) {
+		case []byte:
+			b = value
+		case common.Hash:
+			b = value.Bytes()
+		default:
+			return fmt.Errorf("expected []byte for %s, got %T", typeStr, value)
+		}
+		if typeStr != "bytes" {
+			size, err := strconv.Atoi(typeStr[len("bytes"):])
+			if err != nil || size < 1 || size > 32 {
+				return fmt.Errorf("invalid type %s", typeStr)
+			}
+			if len(b) < size {
+				return fmt.Errorf("expected at least %d bytes for %s, got %d", size, typeStr, len(b))
+			}
+			b = b[:size]
+		}
+		appendJSONString(buf, hexutil.Encode(b))
+
+	case strings.HasPrefix(typeStr, "int") || strings.HasPrefix(typeStr, "uint"):
+		n, ok := value.(*big.Int)
+		if !ok || n == nil {
+			return fmt.Errorf("expected *big.Int for %s, got %T", typeStr, value)
+		}
+		appendJSONString(buf, n.String())
+
+	case strings.HasPrefix(typeStr, "fixed") || strings.HasPrefix(typeStr, "ufixed"):
+		f, ok := value.(*big.Float)
+		if !ok || f == nil {
+			return fmt.Errorf("expected *big.Float for %s, got %T", typeStr, value)
+		}
+		appendJSONString(buf, f.Text('f', -1))
+
+	default:
+		return fmt.Errorf("unsupported type %s", typeStr)
+	}
+
+	return nil
+}
+
+// appendJSONString writes s as a JSON string.
+func appendJSONString(buf *bytes.Buffer, s string) {
+	encoded, _ := json.Marshal(s) // strings always marshal
+	buf.Write(encoded)
+}
+
+// fromJSONTuple converts a JSON object, or array, into the members of a
+// tuple.
+func fromJSONTuple(value any, components []Component) ([]any, error) {
+	keys, err := jsonKeys(components)
+	if err != nil {
+		return nil, err
+	}
+
+	var elems []any
+	switch value := value.(type) {
+	case map[string]any:
+		if len(value) != len(keys) {
+			return nil, fmt.Errorf("expected %d members, got %d", len(keys), len(value))
+		}
+
+		elems = make([]any, len(keys))
+		for i, key := range keys {
+			elem, ok := value[key]
+			if !ok {
+				return nil, fmt.Errorf("missing member %q", key)
+			}
+			elems[i] = elem
+		}
+	case []any:
+		if len(value) != len(components) {
+			return nil, fmt.Errorf("expected %d members, got %d", len(components), len(value))
+		}
+		elems = value
+	default:
+		return nil, fmt.Errorf("expected JSON object for tuple, got %T", value)
+	}
+
+	result := make([]any, len(components))
+	for i, component := range components {
+		result[i], err = fromJSONValue(elems[i], component.Type, component.Components)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling %s: %w", keys[i], err)
+		}
+	}
+
+	return result, nil
+}
+
+// fromJSONValue converts a JSON value into the decoded value of given
+// component type.
+func fromJSONValue(value any, typeStr string, components []Component) (any, error) {
+	if strings.HasSuffix(typeStr, "]") {
+		elems, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected JSON array for %s, got %T", typeStr, value)
+		}
+
+		open := strings.LastIndex(typeStr, "[")
+		if size := typeStr[open+1 : len(typeStr)-1]; size != "" && size != strconv.Itoa(len(elems)) {
+			return nil, fmt.Errorf("expected %s elements for %s, got %d", size, typeStr, len(elems))
+		}
+
+		result := make([]any, len(elems))
+		for i, elem := range elems {
+			parsed, err := fromJSONValue(elem, typeStr[:open], components)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			result[i] = parsed
+		}
+
+		return result, nil
+	}
+
+	if typeStr == "tuple" {
+		return fromJSONTuple(value, components)
+	}
+
+	return fromJSONCore(value, typeStr)
+}
+
+// fromJSONCore converts a JSON value into the decoded value of given
+// non-array and non-tuple type.
+func fromJSONCore(value any, typeStr string) (any, error) {
+	if typeStr == "bool" {
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected JSON boolean for bool, got %T", value)
+		}
+		return b, nil
+	}
+
+	var s string
+	switch value := value.(type) {
+	case string:
+		s = value
+	case json.Number:
+		if !strings.HasPrefix(typeStr, "int") && !strings.HasPrefix(typeStr, "uint") &&
+			!strings.HasPrefix(typeStr, "fixed") && !strings.HasPrefix(typeStr, "ufixed") {
+			return nil, fmt.Errorf("expected JSON string for %s, got number %s", typeStr, value)
+		}
+		s = value.String()
+	default:
+		return nil, fmt.Errorf("expected JSON string for %s, got %T", typeStr, value)
+	}
+
+	switch {
+	case typeStr == "address":
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %q", s)
+		}
+		return common.HexToAddress(s).Hex(), nil
+
+	case typeStr == "string":
+		return s, nil
+
+	case typeStr == "function":
+		b, err := hexutil.Decode(s)
+		if err != nil || len(b) != functionPointerLength {
+			return nil, fmt.Errorf("invalid function %q: expected %d hex encoded bytes", s, functionPointerLength)
+		}
+		return functionPointerFromBytes(b)
+
+	case typeStr == "bytes":
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytes %q: %w", s, err)
+		}
+		return b, nil
+
+	case strings.HasPrefix(typeStr, "bytes"):
+		size, err := strconv.Atoi(typeStr[len("bytes"):])
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type %s", typeStr)
+		}
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", typeStr, s, err)
+		}
+		if len(b) != size {
+			return nil, fmt.Errorf("expected %d bytes for %s, got %d", size, typeStr, len(b))
+		}
+		return common.RightPadBytes(b, 32), nil
+
+	case strings.HasPrefix(typeStr, "int") || strings.HasPrefix(typeStr, "uint"):
+		bits, isUint := integerBits(typeStr)
+		if bits == 0 {
+			return nil, fmt.Errorf("unsupported type %s", typeStr)
+		}
+
+		n, ok := parseJSONInteger(s)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q for %s", s, typeStr)
+		}
+		if !integerFits(n, bits, isUint) {
+			return nil, fmt.Errorf("integer %s does not fit in %s", n, typeStr)
+		}
+		return n, nil
+
+	case strings.HasPrefix(typeStr, "fixed") || strings.HasPrefix(typeStr, "ufixed"):
+		f, ok := new(big.Float).SetPrec(256).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("invalid fixed point number %q for %s", s, typeStr)
+		}
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", typeStr)
+}
+
+// parseJSONInteger parses a decimal, or 0x prefixed hex, integer.
+func parseJSONInteger(s string) (*big.Int, bool) {
+	digits, negative := strings.CutPrefix(s, "-")
+	base := 10
+	if hex, ok := strings.CutPrefix(digits, "0x"); ok {
+		digits, base = hex, 16
+	}
+	if digits == "" || strings.HasPrefix(digits, "+") || strings.HasPrefix(digits, "-") {
+		return nil, false
+	}
+
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, false
+	}
+	if negative {
+		n.Neg(n)
+	}
+
+	return n, true
+}
+
+// integerFits checks whether n is within the range of an integer of
+// given bit size and signedness.
+func integerFits(n *big.Int, bits int, isUint bool) bool {
+	if isUint {
+		return n.Sign() >= 0 && n.BitLen() <= bits
+	}
+	if n.Sign() < 0 {
+		// -2^(bits-1) <= n is equivalent to ^n = -n-1 < 2^(bits-1).
+		return new(big.Int).Not(n).BitLen() < bits
+	}
+
+	return n.BitLen() < bits
+}
@@ -0,0 +1,46 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleMarshalJSON() {
+	fragment := abi.MustParseFragment(
+		"function fill((address maker, uint256 amount, bytes4 kind) order, int64 deadline, bytes signature)",
+	)
+	typeStrs := abi.ComponentTypes(fragment.Inputs)
+
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	encoded, err := abi.Encode(typeStrs,
+		[]any{"0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789", amount, []byte{0xde, 0xad, 0xbe, 0xef}},
+		big.NewInt(-1),
+		[]byte{0x01, 0x02},
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	data, err := abi.MarshalJSON(decoded, fragment.Inputs)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(string(data))
+
+	values, err := abi.UnmarshalJSON(data, fragment.Inputs)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(abi.DeepEqual(values, decoded))
+
+	// Output:
+	// {"order":{"maker":"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789","amount":"123456789012345678901234567890","kind":"0xdeadbeef"},"deadline":"-1","signature":"0x0102"}
+	// true
+}
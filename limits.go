@@ -0,0 +1,67 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded is returned when decoding or parsing data exceeds
+// one of the configured Limits.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits bounds the resources used to decode and parse untrusted data
+// (i.e. call data of arbitrary transactions), so that corrupt or
+// malicious inputs can't trigger huge allocations or deep recursion.
+// Zero fields are not limited.
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of arrays and tuples.
+	MaxDepth int
+	// MaxElements is the maximum total number of array elements and
+	// tuple members.
+	MaxElements int
+	// MaxBytesLength is the maximum length of a string or bytes value.
+	MaxBytesLength int
+}
+
+// DefaultLimits are sensible limits when handling untrusted data.
+var DefaultLimits = Limits{
+	MaxDepth:       32,
+	MaxElements:    1 << 20,
+	MaxBytesLength: 16 << 20,
+}
+
+// limitState tracks the resources used by a single decoding or parsing
+// call against its limits.
+type limitState struct {
+	limits   Limits
+	elements int
+}
+
+// checkDepth checks whether given nesting depth is allowed.
+func (s *limitState) checkDepth(depth int) error {
+	if s.limits.MaxDepth > 0 && depth > s.limits.MaxDepth {
+		return fmt.Errorf("%w: nesting depth exceeds %d", ErrLimitExceeded, s.limits.MaxDepth)
+	}
+
+	return nil
+}
+
+// addElements records n more array elements or tuple members.
+func (s *limitState) addElements(n int) error {
+	s.elements += n
+	if s.limits.MaxElements > 0 && s.elements > s.limits.MaxElements {
+		return fmt.Errorf("%w: number of elements exceeds %d", ErrLimitExceeded, s.limits.MaxElements)
+	}
+
+	return nil
+}
+
+// checkBytesLength checks whether a string or bytes value of given
+// length is allowed.
+func (s *limitState) checkBytesLength(length uint64) error {
+	if s.limits.MaxBytesLength > 0 && length > uint64(s.limits.MaxBytesLength) {
+		return fmt.Errorf("%w: length %d exceeds %d bytes", ErrLimitExceeded, length, s.limits.MaxBytesLength)
+	}
+
+	return nil
+}
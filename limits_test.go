@@ -0,0 +1,222 @@
+package abi_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleDecodeWithOptions() {
+	typeStrs := []string{"uint256[]"}
+	encoded, err := abi.Encode(typeStrs, []any{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	limits := abi.DefaultLimits
+	limits.MaxElements = 2
+
+	_, err = abi.DecodeWithOptions(typeStrs, encoded, abi.Options{Limits: limits})
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, abi.ErrLimitExceeded))
+
+	// Output:
+	// limit exceeded: number of elements exceeds 2
+	// true
+}
+
+func ExampleParseWithOptions_limits() {
+	type nested struct {
+		Values [][]*big.Int
+	}
+
+	decoded := []any{[]any{[]any{big.NewInt(1)}, []any{big.NewInt(2)}}}
+
+	var v nested
+	err := abi.ParseWithOptions(decoded, &v, abi.Options{Limits: abi.Limits{MaxDepth: 2}})
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, abi.ErrLimitExceeded))
+
+	// Output:
+	// [parseStruct] error parsing field nested.Values[0] (expected []*big.Int, got []interface {}): [parseSlice] limit exceeded: nesting depth exceeds 2
+	// true
+}
+
+// fuzzTypes are the type strings decoded by the fuzz targets, picked
+// by index so that fuzzing explores the data rather than type strings.
+var fuzzTypes = []string{
+	"uint256",
+	"int8",
+	"address",
+	"bool",
+	"bytes32",
+	"bytes",
+	"string",
+	"uint256[]",
+	"uint8[3]",
+	"string[]",
+	"uint256[][]",
+	"(address,uint256)[]",
+	"(uint256,bytes)[2]",
+	"((uint256[],string),bool)",
+}
+
+// fuzzLimits are the limits checked by FuzzDecodeWithOptions, small
+// enough to be exceeded by the fuzzed inputs.
+var fuzzLimits = abi.Limits{MaxDepth: 2, MaxElements: 8, MaxBytesLength: 64}
+
+// addFuzzSeeds seeds a fuzz target with valid encodings of fuzzTypes,
+// some of them exceeding fuzzLimits.
+func addFuzzSeeds(f *testing.F) {
+	address := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	many := make([]any, 9)
+	for i := range many {
+		many[i] = big.NewInt(int64(i))
+	}
+	long := make([]byte, 65)
+
+	seeds := map[string][]any{
+		"uint256":                   {big.NewInt(1000)},
+		"int8":                      {big.NewInt(-5)},
+		"address":                   {&address},
+		"bool":                      {true},
+		"bytes32":                   {[]byte{0xde, 0xad}},
+		"bytes":                     {long},
+		"string":                    {"hello"},
+		"uint256[]":                 {many},
+		"uint8[3]":                  {[]any{big.NewInt(1), big.NewInt(2), big.NewInt(3)}},
+		"string[]":                  {[]any{"a", "b"}},
+		"uint256[][]":               {[]any{[]any{big.NewInt(1)}, []any{}}},
+		"(address,uint256)[]":       {[]any{[]any{&address, big.NewInt(1)}}},
+		"(uint256,bytes)[2]":        {[]any{[]any{big.NewInt(1), []byte{1}}, []any{big.NewInt(2), long}}},
+		"((uint256[],string),bool)": {[]any{[]any{[]any{big.NewInt(1)}, "a"}, false}},
+	}
+	for i, typeStr := range fuzzTypes {
+		encoded, err := abi.Encode([]string{typeStr}, seeds[typeStr]...)
+		if err != nil {
+			f.Fatalf("error encoding %s seed: %v", typeStr, err)
+		}
+		f.Add(uint8(i), encoded)
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, typeIndex uint8, data []byte) {
+		typeStr := fuzzTypes[int(typeIndex)%len(fuzzTypes)]
+		_, _ = abi.Decode([]string{typeStr}, data)
+	})
+}
+
+// malformedTypes are invalid type strings seeding FuzzDecodeTypes.
+var malformedTypes = []string{
+	"uint256[-1]",
+	"uint256[0]",
+	"uint256[+1]",
+	"uint256[0x10]",
+	"uint8[3][-2]",
+	"uint256][",
+	"(uint256,bytes",
+	"(uint256[-1],bool)[2]",
+	"0",
+	"byt",
+	"int512",
+	"uint7",
+	"uint256[288230376151711744]",
+	"uint256[4611686018427387904][4]",
+	"(uint256[144115188075855872],uint256[144115188075855872])",
+}
+
+func FuzzDecodeTypes(f *testing.F) {
+	for _, typeStr := range append(fuzzTypes, malformedTypes...) {
+		f.Add(typeStr, make([]byte, 96))
+	}
+
+	f.Fuzz(func(t *testing.T, typeStr string, data []byte) {
+		_, _ = abi.Decode([]string{typeStr}, data)
+		for _, r := range []io.Reader{bytes.NewReader(data), io.MultiReader(bytes.NewReader(data))} {
+			d, err := abi.NewStreamDecoder(r, []string{typeStr})
+			if err == nil {
+				_, _ = d.Value(0)
+			}
+		}
+	})
+}
+
+func ExampleDecode_invalidArraySize() {
+	for _, typeStr := range []string{"uint256[-1]", "uint256[0]", "uint256[+1]"} {
+		_, err := abi.Decode([]string{typeStr}, make([]byte, 64))
+		fmt.Println(typeStr, err)
+	}
+
+	// Output:
+	// uint256[-1] invalid array definition
+	// uint256[0] invalid array definition
+	// uint256[+1] invalid array definition
+}
+
+func ExampleDecode_oversizedArray() {
+	typeStrs := [][]string{
+		{"uint256[288230376151711744]"},
+		{"uint256[4611686018427387904][4]"},
+		{"(uint256[144115188075855872],uint256[144115188075855872])"},
+	}
+	for _, typeStrs := range typeStrs {
+		_, err := abi.Decode(typeStrs, make([]byte, 64))
+		fmt.Println(err)
+	}
+
+	// Output:
+	// size of uint256[288230376151711744] overflows
+	// size of uint256[4611686018427387904] overflows
+	// size of (uint256[144115188075855872],uint256[144115188075855872]) overflows
+}
+
+func FuzzDecodeWithOptions(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, typeIndex uint8, data []byte) {
+		typeStrs := []string{fuzzTypes[int(typeIndex)%len(fuzzTypes)]}
+		limited, err := abi.DecodeWithOptions(typeStrs, data, abi.Options{Limits: fuzzLimits})
+		unlimited, unlimitedErr := abi.Decode(typeStrs, data)
+
+		switch {
+		case err == nil && unlimitedErr != nil:
+			t.Fatalf("decoded %s with limits but not without: %v", typeStrs[0], unlimitedErr)
+		case err == nil:
+			if !reflect.DeepEqual(limited, unlimited) {
+				t.Fatalf("decoded %v with limits, %v without", limited, unlimited)
+			}
+			checkBytesLengths(t, limited)
+		case unlimitedErr == nil && !errors.Is(err, abi.ErrLimitExceeded):
+			t.Fatalf("expected limit error decoding %s, got %v", typeStrs[0], err)
+		}
+	})
+}
+
+// checkBytesLengths checks that the bytes and string values decoded
+// with fuzzLimits don't exceed their maximum length.
+func checkBytesLengths(t *testing.T, value any) {
+	switch value := value.(type) {
+	case []any:
+		for _, elem := range value {
+			checkBytesLengths(t, elem)
+		}
+	case []byte:
+		if len(value) > fuzzLimits.MaxBytesLength {
+			t.Fatalf("decoded %d bytes with a limit of %d", len(value), fuzzLimits.MaxBytesLength)
+		}
+	case string:
+		if len(value) > fuzzLimits.MaxBytesLength {
+			t.Fatalf("decoded string of %d bytes with a limit of %d", len(value), fuzzLimits.MaxBytesLength)
+		}
+	}
+}
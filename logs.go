@@ -0,0 +1,402 @@
+package abi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ParseLog decodes given event log and parses its values into the
+// struct pointed by v. The event signature must be human-readable
+// with its indexed parameters marked, i.e.
+// `event Transfer(address indexed from, address indexed to, uint256 value)`.
+// Fields tagged with `abi:"name"` are mapped to the event parameter
+// with the same name.
+func ParseLog(log types.Log, eventSig string, v any) error {
+	fragment, err := ParseFragment(eventSig)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := DecodeLog(log, fragment)
+	if err != nil {
+		return err
+	}
+
+	return ParseWithComponents(decoded, fragment.Inputs, v)
+}
+
+// IndexedHash is the keccak256 hash of an indexed event parameter of
+// dynamic type (string, bytes, array or tuple), which is all the log
+// holds of its value. Struct fields of type IndexedHash or common.Hash
+// can be used to parse such parameters.
+type IndexedHash common.Hash
+
+// Hash returns the hash as common.Hash.
+func (h IndexedHash) Hash() common.Hash {
+	return common.Hash(h)
+}
+
+// Hex returns the hex representation of the hash.
+func (h IndexedHash) Hex() string {
+	return common.Hash(h).Hex()
+}
+
+// String implements fmt.Stringer.
+func (h IndexedHash) String() string {
+	return h.Hex()
+}
+
+// BulkOptions controls the behavior of ParseLogs.
+type BulkOptions struct {
+	// Workers is the number of logs decoded concurrently. It defaults
+	// to runtime.NumCPU() when zero or negative.
+	Workers int
+
+	// ParseOptions are the options used to parse each log. Components
+	// are always set to the event parameters.
+	ParseOptions Options
+
+	// Progress, when set, is called after each parsed log with the number
+	// of logs parsed so far. It is called concurrently by the workers.
+	Progress func(parsed int)
+}
+
+// ParseLogs decodes a batch of event logs concurrently with a bounded
+// pool of workers, parsing each log into a new target created with
+// makeTarget (i.e. `func() any { return new(Transfer) }`). The returned
+// targets keep the order of given logs. Decoding stops at the first
+// error, which is returned with the index of the failing log.
+func ParseLogs(logs []types.Log, eventSig string, makeTarget func() any, opts BulkOptions) ([]any, error) {
+	return ParseLogsCtx(context.Background(), logs, eventSig, makeTarget, opts)
+}
+
+// ParseLogsCtx decodes a batch of event logs like ParseLogs, checking
+// ctx before each log and array element. When ctx is done, the workers
+// stop and the returned error wraps ctx.Err().
+func ParseLogsCtx(ctx context.Context, logs []types.Log, eventSig string, makeTarget func() any, opts BulkOptions) ([]any, error) {
+	fragment, err := ParseFragment(eventSig)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(logs) {
+		workers = len(logs)
+	}
+
+	parseOpts := opts.ParseOptions
+	parseOpts.Components = fragment.Inputs
+
+	results := make([]any, len(logs))
+	errs := make([]error, len(logs))
+	var failed atomic.Bool
+	var next, parsed atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !failed.Load() && ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(logs) {
+					return
+				}
+
+				decoded, err := DecodeLog(logs[i], fragment)
+				if err == nil {
+					target := makeTarget()
+					err = ParseWithOptionsCtx(ctx, decoded, target, parseOpts)
+					results[i] = target
+				}
+				if err != nil {
+					errs[i] = err
+					failed.Store(true)
+				} else if opts.Progress != nil {
+					opts.Progress(int(parsed.Add(1)))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("error parsing log %d: %w", i, err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// DecodeLog decodes the topics and data of given event log, returning
+// the values of all event parameters in declaration order. Indexed
+// parameters of dynamic types (strings, bytes, arrays and tuples) are
+// only present in the log through their keccak256 hash, so they are
+// returned as common.Hash, which Parse sets into common.Hash or
+// IndexedHash fields.
+func DecodeLog(log types.Log, event *Fragment) ([]any, error) {
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("log has no topics")
+		}
+		if topics[0] != event.Topic() {
+			return nil, fmt.Errorf("log topic %s does not match event %s", topics[0].Hex(), event.Signature())
+		}
+		topics = topics[1:]
+	}
+
+	var indexedCount int
+	var dataTypes []string
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexedCount++
+		} else {
+			dataTypes = append(dataTypes, input.CanonicalType())
+		}
+	}
+
+	if indexedCount != len(topics) {
+		return nil, fmt.Errorf("expected %d indexed topics for event %s, got %d", indexedCount, event.Signature(), len(topics))
+	}
+
+	var data []any
+	if len(dataTypes) > 0 {
+		var err error
+		data, err = Decode(dataTypes, log.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]any, 0, len(event.Inputs))
+	var topicIndex, dataIndex int
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			result = append(result, data[dataIndex])
+			dataIndex++
+			continue
+		}
+
+		value, err := decodeTopic(input, topics[topicIndex])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding indexed parameter %s: %w", input.Name, err)
+		}
+		result = append(result, value)
+		topicIndex++
+	}
+
+	return result, nil
+}
+
+// decodeTopic decodes the topic of an indexed parameter.
+func decodeTopic(input Component, topic common.Hash) (any, error) {
+	typeStr := input.CanonicalType()
+	hashed, err := isHashedTopic(typeStr)
+	if err != nil {
+		return nil, err
+	}
+	if hashed {
+		return topic, nil
+	}
+
+	decoded, err := Decode([]string{typeStr}, topic.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return decoded[0], nil
+}
+
+// OneOf lists alternative values of an indexed parameter given to
+// BuildTopics, matching logs holding any of them.
+type OneOf []any
+
+// BuildTopics builds the topics filter of given event for
+// `eth_getLogs` (i.e. the Topics of an ethereum.FilterQuery), from the
+// values of its indexed parameters given in declaration order. The event
+// signature must be human-readable with its indexed parameters marked,
+// i.e. `event Transfer(address indexed from, address indexed to, uint256 value)`.
+//
+// Nil arguments match any value, and OneOf arguments match any of their
+// values. Arguments of dynamic types (strings, bytes, arrays and tuples)
+// are hashed as in logs, unless given as their common.Hash or
+// IndexedHash. Trailing arguments can be omitted.
+func BuildTopics(eventSig string, args ...any) ([][]common.Hash, error) {
+	event, err := ParseFragment(eventSig)
+	if err != nil {
+		return nil, err
+	}
+	if event.Type != "event" {
+		return nil, fmt.Errorf("%s is not an event", eventSig)
+	}
+
+	var indexed []Component
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if len(args) > len(indexed) {
+		return nil, fmt.Errorf("expected at most %d indexed arguments for event %s, got %d", len(indexed), event.Signature(), len(args))
+	}
+
+	var topics [][]common.Hash
+	if !event.Anonymous {
+		topics = append(topics, []common.Hash{event.Topic()})
+	}
+
+	for i, arg := range args {
+		values, isOneOf := arg.(OneOf)
+		if !isOneOf && arg != nil {
+			values = OneOf{arg}
+		}
+
+		var position []common.Hash
+		for _, value := range values {
+			topic, err := encodeTopic(indexed[i].CanonicalType(), value)
+			if err != nil {
+				return nil, fmt.Errorf("error encoding indexed argument %s: %w", indexed[i].Name, err)
+			}
+			position = append(position, topic)
+		}
+		topics = append(topics, position)
+	}
+
+	// Trailing wildcards are implied.
+	for len(topics) > 0 && len(topics[len(topics)-1]) == 0 {
+		topics = topics[:len(topics)-1]
+	}
+
+	return topics, nil
+}
+
+// encodeTopic encodes the value of an indexed parameter as a topic.
+func encodeTopic(typeStr string, value any) (common.Hash, error) {
+	hashed, err := isHashedTopic(typeStr)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if !hashed {
+		encoded, err := Encode([]string{typeStr}, value)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return common.BytesToHash(encoded), nil
+	}
+
+	switch value := value.(type) {
+	case common.Hash:
+		return value, nil
+	case IndexedHash:
+		return value.Hash(), nil
+	}
+
+	marshaled, err := marshalValue(reflect.ValueOf(value), typeStr)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	// Strings and bytes are hashed as is, other values are hashed in
+	// their padded in-place encoding.
+	if typeStr == "string" || typeStr == "bytes" {
+		encoded, err := encodePacked(typeStr, marshaled)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return crypto.Keccak256Hash(encoded), nil
+	}
+
+	encoded, err := encodeInPlace(typeStr, marshaled)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// encodeInPlace encodes a marshaled value as the concatenation of its
+// elements, each padded to 32 bytes, without lengths nor offsets. It is
+// the encoding hashed for indexed arrays and tuples.
+func encodeInPlace(typeStr string, value any) ([]byte, error) {
+	isTypeArray, _, err := IsArray(typeStr)
+	if err != nil {
+		return nil, err
+	}
+	isTypeTuple, splitedTypes, err := tupleType(typeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isTypeArray && !isTypeTuple {
+		if typeStr == "string" || typeStr == "bytes" {
+			encoded, err := encodePacked(typeStr, value)
+			if err != nil {
+				return nil, err
+			}
+			padded := make([]byte, (len(encoded)+31)/32*32)
+			copy(padded, encoded)
+			return padded, nil
+		}
+		return Encode([]string{typeStr}, value)
+	}
+
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected list of values for %s, got %T", typeStr, value)
+	}
+
+	memberTypes := splitedTypes
+	if isTypeArray {
+		memberTypes = make([]string, len(values))
+		for i := range memberTypes {
+			memberTypes[i] = typeStr[:strings.LastIndex(typeStr, "[")]
+		}
+	}
+	if len(memberTypes) != len(values) {
+		return nil, fmt.Errorf("expected %d values for %s, got %d", len(memberTypes), typeStr, len(values))
+	}
+
+	var result []byte
+	for i, memberType := range memberTypes {
+		encoded, err := encodeInPlace(memberType, values[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, encoded...)
+	}
+
+	return result, nil
+}
+
+// isHashedTopic checks whether indexed parameters of given type are
+// stored as the keccak256 hash of their value, which is the case of
+// strings, bytes, arrays and tuples.
+func isHashedTopic(typeStr string) (bool, error) {
+	isTypeTuple, _, err := tupleType(typeStr)
+	if err != nil {
+		return false, err
+	}
+	isTypeArray, _, err := IsArray(typeStr)
+	if err != nil {
+		return false, err
+	}
+
+	return isTypeTuple || isTypeArray || IsDynamic(typeStr, false), nil
+}
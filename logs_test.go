@@ -0,0 +1,182 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleParseLog() {
+	eventSig := "event Transfer(address indexed from, address indexed to, uint256 value)"
+	log := types.Log{
+		Topics: []common.Hash{
+			common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+			common.HexToHash("0x0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d2789"),
+			common.HexToHash("0x000000000000000000000000000000000000000000000000000000000000dead"),
+		},
+		Data: common.Hex2Bytes("00000000000000000000000000000000000000000000000000000000000003e8"),
+	}
+
+	var transfer struct {
+		From  common.Address `abi:"from"`
+		To    common.Address `abi:"to"`
+		Value *big.Int       `abi:"value"`
+	}
+	err := abi.ParseLog(log, eventSig, &transfer)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(transfer.From, transfer.To, transfer.Value)
+
+	// Output: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 0x000000000000000000000000000000000000dEaD 1000
+}
+
+func ExampleDecodeLog() {
+	event := abi.MustParseFragment("event Registered(string indexed name, address indexed owner, string name2, uint64 ttl)")
+
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	data, err := abi.Encode([]string{"string", "uint64"}, "vitalik", big.NewInt(3600))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	log := types.Log{
+		Topics: []common.Hash{
+			event.Topic(),
+			common.HexToHash("0xaf2caa1c2ca1d027f1ac823b529d0a67cd144264b2789fa2ea4d63a67c7103cc"),
+			common.BytesToHash(owner.Bytes()),
+		},
+		Data: data,
+	}
+
+	decoded, err := abi.DecodeLog(log, event)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(decoded)
+
+	var registered struct {
+		NameHash common.Hash
+		Owner    common.Address
+		Name     string
+		TTL      *big.Int
+	}
+	err = abi.Parse(decoded, &registered)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(registered.NameHash, registered.Owner, registered.Name, registered.TTL)
+
+	// Output:
+	// [0xaf2caa1c2ca1d027f1ac823b529d0a67cd144264b2789fa2ea4d63a67c7103cc 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 vitalik 3600]
+	// 0xaf2caa1c2ca1d027f1ac823b529d0a67cd144264b2789fa2ea4d63a67c7103cc 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 vitalik 3600
+}
+
+func ExampleParseLogs() {
+	eventSig := "event Transfer(address indexed from, address indexed to, uint256 value)"
+	event := abi.MustParseFragment(eventSig)
+	from := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+	logs := make([]types.Log, 100)
+	for i := range logs {
+		data, err := abi.Encode([]string{"uint256"}, big.NewInt(int64(i)))
+		if err != nil {
+			fmt.Println(err)
+		}
+		logs[i] = types.Log{
+			Topics: []common.Hash{event.Topic(), common.BytesToHash(from.Bytes()), common.BigToHash(big.NewInt(int64(i)))},
+			Data:   data,
+		}
+	}
+
+	type transfer struct {
+		From  common.Address `abi:"from"`
+		To    common.Address `abi:"to"`
+		Value *big.Int       `abi:"value"`
+	}
+
+	results, err := abi.ParseLogs(logs, eventSig, func() any { return new(transfer) }, abi.BulkOptions{Workers: 4})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(len(results), results[0].(*transfer).Value, results[99].(*transfer).Value, results[99].(*transfer).To.Hex())
+
+	// Output: 100 0 99 0x0000000000000000000000000000000000000063
+}
+
+func ExampleIndexedHash() {
+	eventSig := "event Registered(string indexed name, (address owner, uint64 ttl) indexed record, uint256[] indexed ids)"
+	event := abi.MustParseFragment(eventSig)
+
+	log := types.Log{
+		Topics: []common.Hash{
+			event.Topic(),
+			common.HexToHash("0xaf2caa1c2ca1d027f1ac823b529d0a67cd144264b2789fa2ea4d63a67c7103cc"),
+			common.HexToHash("0x01"),
+			common.HexToHash("0x02"),
+		},
+	}
+
+	var registered struct {
+		Name   abi.IndexedHash `abi:"name"`
+		Record abi.IndexedHash `abi:"record"`
+		IDs    common.Hash     `abi:"ids"`
+	}
+	err := abi.ParseLog(log, eventSig, &registered)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(registered.Name == abi.IndexedHash(crypto.Keccak256Hash([]byte("vitalik"))), registered.Record.Hash().Big(), registered.IDs.Big())
+
+	var invalid struct {
+		Name   string      `abi:"name"`
+		Record common.Hash `abi:"record"`
+		IDs    common.Hash `abi:"ids"`
+	}
+	err = abi.ParseLog(log, eventSig, &invalid)
+	fmt.Println(err)
+
+	// Output:
+	// true 1 2
+	// [parseStruct] error parsing field Name (expected string, got common.Hash, abi type string): indexed parameters of dynamic types only hold their keccak256 hash, parse them into common.Hash or IndexedHash instead of string
+}
+
+func ExampleBuildTopics() {
+	from := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	// Transfers from any address to one of two recipients.
+	topics, err := abi.BuildTopics(
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		nil,
+		abi.OneOf{from, to},
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, position := range topics {
+		fmt.Println(position)
+	}
+
+	// Indexed strings are matched through their hash.
+	topics, err = abi.BuildTopics("event Registered(string indexed name, address owner)", "alice")
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(topics[1][0] == crypto.Keccak256Hash([]byte("alice")))
+
+	// Output:
+	// [0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef]
+	// []
+	// [0x0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d2789 0x000000000000000000000000000000000000000000000000000000000000dead]
+	// true
+}
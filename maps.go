@@ -0,0 +1,190 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ParseToMap parses decoded values into a map keyed by the names of
+// given components. Nested tuples become nested maps and arrays become
+// []any holding the parsed elements, so the result is suitable for
+// JSON serialization. Unnamed components are keyed by their position.
+func ParseToMap(decoded []any, components []Component) (map[string]any, error) {
+	if len(decoded) != len(components) {
+		return nil, fmt.Errorf("[ParseToMap] number of decoded values does not match number of components")
+	}
+
+	result := make(map[string]any, len(components))
+	for i, component := range components {
+		key := component.Name
+		if key == "" {
+			key = strconv.Itoa(i)
+		}
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("[ParseToMap] duplicate component name %q", key)
+		}
+
+		value, err := parseMapValue(decoded[i], component.Type, component.Components)
+		if err != nil {
+			return nil, fmt.Errorf("[ParseToMap] error parsing %s: %w", key, err)
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// parseMapValue parses a decoded value of given component type.
+func parseMapValue(value any, typeStr string, components []Component) (any, error) {
+	if strings.HasSuffix(typeStr, "]") {
+		elems, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected array for %s, got %T", typeStr, value)
+		}
+
+		elemTypeStr := typeStr[:strings.LastIndex(typeStr, "[")]
+		result := make([]any, len(elems))
+		for i, elem := range elems {
+			parsed, err := parseMapValue(elem, elemTypeStr, components)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			result[i] = parsed
+		}
+
+		return result, nil
+	}
+
+	if typeStr == "tuple" {
+		members, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected tuple, got %T", value)
+		}
+
+		return ParseToMap(members, components)
+	}
+
+	return value, nil
+}
+
+// newMapKeySetter builds the setter of a map field tagged with
+// `abi:",mapkey=key"`, which receives an array of tuples keyed by the
+// member with given name (resolved with components) or position. Struct
+// values receive whole tuples, other values receive the member that is
+// not the key of tuples of two members.
+func newMapKeySetter(t reflect.Type, key string) (setter, error) {
+	if t.Kind() != reflect.Map {
+		return nil, fmt.Errorf("mapkey fields must be maps, got %s", t)
+	}
+
+	keySet := setterFor(t.Key())
+	valueSet := setterFor(t.Elem())
+	valueType := t.Elem()
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	wholeTuples := (valueType.Kind() == reflect.Struct && !isCoreStruct(valueType)) || isUnmarshaler(t.Elem())
+
+	return func(s *parseState, target reflect.Value, value any, components []Component) error {
+		if value == nil {
+			if s.opts.AllowNil {
+				return nil
+			}
+			return newParseError(fmt.Errorf("nil decoded value for %s", t), t, value)
+		}
+
+		elems, ok := value.([]any)
+		if !ok {
+			return newParseError(newMismatchError("array of tuples for "+t.String(), value), t, value)
+		}
+
+		err := s.enter(len(elems))
+		if err != nil {
+			return newParseError(err, t, value)
+		}
+		defer s.leave()
+
+		result := reflect.MakeMapWithSize(t, len(elems))
+		for i, elem := range elems {
+			err := setMapEntry(s, result, elem, components, key, keySet, valueSet, wholeTuples)
+			if err != nil {
+				return prependPath(err, fmt.Sprintf("[%d]", i), nil)
+			}
+		}
+		target.Set(result)
+
+		return nil
+	}, nil
+}
+
+// setMapEntry parses a decoded tuple into an entry of given map.
+func setMapEntry(s *parseState, result reflect.Value, elem any, components []Component, key string, keySet, valueSet setter, wholeTuples bool) error {
+	t := result.Type()
+	members, ok := elem.([]any)
+	if !ok {
+		return newParseError(newMismatchError("tuple for "+t.String()+" entry", elem), t, elem)
+	}
+
+	position, err := mapKeyPosition(key, components, len(members), s.opts.Naming)
+	if err != nil {
+		return newParseError(err, t, elem)
+	}
+	memberComponents := func(i int) []Component {
+		if components == nil {
+			return nil
+		}
+		return components[i].Components
+	}
+
+	k := reflect.New(t.Key()).Elem()
+	err = keySet(s, k, members[position], memberComponents(position))
+	if err != nil {
+		return prependPath(err, "."+key, nil)
+	}
+	if result.MapIndex(k).IsValid() {
+		return newParseError(fmt.Errorf("duplicate map key %v", k), t, elem)
+	}
+
+	v := reflect.New(t.Elem()).Elem()
+	if wholeTuples {
+		err = valueSet(s, v, members, components)
+	} else {
+		if len(members) != 2 {
+			return newParseError(fmt.Errorf("map values which are not structs need tuples of 2 members, got %d", len(members)), t, elem)
+		}
+		err = valueSet(s, v, members[1-position], memberComponents(1-position))
+	}
+	if err != nil {
+		return err
+	}
+	result.SetMapIndex(k, v)
+
+	return nil
+}
+
+// mapKeyPosition returns the position of the member keying map entries,
+// given by name (among components, matched with given naming strategy)
+// or by position.
+func mapKeyPosition(key string, components []Component, numMembers int, naming NamingStrategy) (int, error) {
+	if position, err := strconv.Atoi(key); err == nil {
+		if position < 0 || position >= numMembers {
+			return 0, fmt.Errorf("mapkey position %d out of range of tuples of %d members", position, numMembers)
+		}
+		return position, nil
+	}
+
+	if components == nil {
+		return 0, fmt.Errorf("mapkey %q needs components to be resolved, use its position instead", key)
+	}
+	position, err := componentPosition(key, components, naming)
+	if err != nil {
+		return 0, fmt.Errorf("mapkey %w", err)
+	}
+	if position == -1 || position >= numMembers {
+		return 0, fmt.Errorf("mapkey %q is not a tuple member", key)
+	}
+
+	return position, nil
+}
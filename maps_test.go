@@ -0,0 +1,34 @@
+package abi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleParseToMap() {
+	fragment := abi.MustParseFragment(
+		"function getOrder() returns (address maker, (address recipient, uint16 bps)[] fees, uint256)",
+	)
+	decoded := []any{
+		"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789",
+		[]any{[]any{"0x000000000000000000000000000000000000dEaD", big.NewInt(30)}},
+		big.NewInt(1000),
+	}
+
+	parsed, err := abi.ParseToMap(decoded, fragment.Outputs)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	encoded, err := json.Marshal(parsed)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(string(encoded))
+
+	// Output: {"2":1000,"fees":[{"bps":30,"recipient":"0x000000000000000000000000000000000000dEaD"}],"maker":"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"}
+}
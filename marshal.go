@@ -0,0 +1,308 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/uint256"
+)
+
+// Marshal encodes the struct v based on the parameter types of given
+// signature, i.e. `(address,uint256,(bytes,bool)[])` or
+// `transfer(address,uint256)`. It follows the same conventions as
+// Parse: struct fields are mapped to parameter types by their
+// positional order (or `abi:"index=N"` tags), nested structs map to
+// tuples and slices/arrays map to arrays. The function selector is not
+// included in the result.
+//
+// When the signature has a single parameter type, v can also be the
+// value of that parameter (i.e. a *big.Int for `(uint256)`).
+func Marshal(v any, signature string) ([]byte, error) {
+	typeStrs, err := GetSigTypes(signature)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	values, err := marshalParams(reflect.ValueOf(v), typeStrs)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return Encode(typeStrs, values...)
+}
+
+// marshalParams converts v into the list of values to be encoded with
+// given type strings.
+func marshalParams(rv reflect.Value, typeStrs []string) ([]any, error) {
+	rv = unwrapValue(rv)
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("[marshalParams] v must not be nil")
+	}
+
+	_, isMarshaler := asMarshaler(rv)
+	if rv.Kind() == reflect.Struct && !isCoreStruct(rv.Type()) && !isMarshaler && structPlanFor(rv.Type()).numValues == len(typeStrs) {
+		return marshalStruct(rv, typeStrs)
+	}
+
+	if len(typeStrs) == 1 {
+		value, err := marshalValue(rv, typeStrs[0])
+		if err != nil {
+			return nil, err
+		}
+		return []any{value}, nil
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		return marshalList(rv, typeStrs)
+	}
+
+	return nil, fmt.Errorf("[marshalParams] cannot marshal %s into %d parameters", rv.Type(), len(typeStrs))
+}
+
+// marshalStruct converts struct fields into the values of a tuple.
+func marshalStruct(rv reflect.Value, typeStrs []string) ([]any, error) {
+	plan := structPlanFor(rv.Type())
+	if plan.numValues != len(typeStrs) {
+		return nil, fmt.Errorf("[marshalStruct] number of struct fields does not match number of types")
+	}
+
+	positions, err := plan.positions(len(typeStrs), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[marshalStruct] %w", err)
+	}
+
+	values := make([]any, len(typeStrs))
+	for i, field := range plan.fields {
+		// Nil embedded struct pointers give invalid (nil) values.
+		fieldVal, _ := rv.FieldByIndexErr(field.index)
+		if field.tag.OmitZero && fieldVal.IsValid() && fieldVal.IsNil() {
+			// Absent values are marshaled as zero values.
+			fieldVal = reflect.New(fieldVal.Type().Elem())
+		}
+
+		position := positions[i]
+		if field.tag.Span > 1 {
+			// Fields spanning several values are marshaled as a tuple.
+			spannedTypes := typeStrs[position : position+field.tag.Span]
+			value, err := marshalValue(fieldVal, "("+strings.Join(spannedTypes, ",")+")")
+			if err != nil {
+				return nil, fmt.Errorf("[marshalStruct] error marshaling field %s: %w", field.name, err)
+			}
+			spanned, ok := value.([]any)
+			if !ok || len(spanned) != field.tag.Span {
+				return nil, fmt.Errorf("[marshalStruct] field %s must marshal into %d values", field.name, field.tag.Span)
+			}
+			copy(values[position:], spanned)
+			continue
+		}
+
+		if field.tag.MapKey != "" {
+			return nil, fmt.Errorf("[marshalStruct] map field %s can't be marshaled, as maps are unordered", field.name)
+		}
+
+		var value any
+		var err error
+		switch {
+		case field.tag.Enum != nil:
+			value, err = marshalEnum(fieldVal, field.tag.Enum)
+		case field.tag.Decimals != -1:
+			value, err = marshalDecimals(fieldVal, field.tag.Decimals)
+		default:
+			value, err = marshalValue(fieldVal, typeStrs[position])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[marshalStruct] error marshaling field %s: %w", field.name, err)
+		}
+		values[position] = value
+	}
+
+	return values, nil
+}
+
+// marshalList converts slice or array elements into the values of a
+// tuple or array.
+func marshalList(rv reflect.Value, typeStrs []string) ([]any, error) {
+	if rv.Len() != len(typeStrs) {
+		return nil, fmt.Errorf("[marshalList] number of elements does not match number of types")
+	}
+
+	values := make([]any, len(typeStrs))
+	for i := range typeStrs {
+		value, err := marshalValue(rv.Index(i), typeStrs[i])
+		if err != nil {
+			return nil, fmt.Errorf("[marshalList] error marshaling element %d: %w", i, err)
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// marshalValue converts a Go value into the value expected by Encode
+// for given type string.
+func marshalValue(rv reflect.Value, typeStr string) (any, error) {
+	rv = unwrapValue(rv)
+	if marshaler, ok := asMarshaler(rv); ok {
+		var err error
+		rv, err = marshalWith(marshaler)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	isTypeArray, arraySize, err := IsArray(typeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTypeArray {
+		if !rv.IsValid() {
+			return []any{}, nil
+		}
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("[marshalValue] cannot marshal %s into %s", rv.Type(), typeStr)
+		}
+		if arraySize != 0 && rv.Len() != arraySize {
+			return nil, fmt.Errorf("[marshalValue] array size mismatch for %s: got %d elements", typeStr, rv.Len())
+		}
+
+		elemTypeStr := typeStr[:strings.LastIndex(typeStr, "[")]
+		elemTypeStrs := make([]string, rv.Len())
+		for i := range elemTypeStrs {
+			elemTypeStrs[i] = elemTypeStr
+		}
+
+		return marshalList(rv, elemTypeStrs)
+	}
+
+	isTypeTuple, splitedTypes, err := tupleType(typeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTypeTuple {
+		if !rv.IsValid() {
+			return nil, nil
+		}
+		switch rv.Kind() {
+		case reflect.Struct:
+			return marshalStruct(rv, splitedTypes)
+		case reflect.Slice, reflect.Array:
+			return marshalList(rv, splitedTypes)
+		default:
+			return nil, fmt.Errorf("[marshalValue] cannot marshal %s into %s", rv.Type(), typeStr)
+		}
+	}
+
+	return marshalCoreValue(rv, typeStr)
+}
+
+// marshalCoreValue converts a Go value into the value expected by
+// Encode for given non-array and non-tuple type string.
+func marshalCoreValue(rv reflect.Value, typeStr string) (any, error) {
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("[marshalCoreValue] nil value for %s", typeStr)
+	}
+	if marshaler, ok := asMarshaler(rv); ok {
+		var err error
+		rv, err = marshalWith(marshaler)
+		if err != nil {
+			return nil, err
+		}
+		if !rv.IsValid() {
+			return nil, fmt.Errorf("[marshalCoreValue] nil value for %s", typeStr)
+		}
+	}
+
+	switch {
+	case typeStr == "address":
+		switch {
+		case rv.Type() == addressType:
+			address := rv.Interface().(common.Address)
+			return &address, nil
+		case rv.Kind() == reflect.String && common.IsHexAddress(rv.String()):
+			address := common.HexToAddress(rv.String())
+			return &address, nil
+		case rv.Kind() == reflect.Array && rv.Len() == common.AddressLength && rv.Type().Elem().Kind() == reflect.Uint8:
+			address := common.BytesToAddress(bytesOf(rv))
+			return &address, nil
+		}
+
+	case typeStr == "bool":
+		if rv.Kind() == reflect.Bool {
+			return rv.Bool(), nil
+		}
+
+	case typeStr == "string":
+		if rv.Kind() == reflect.String {
+			return rv.String(), nil
+		}
+
+	case typeStr == "function":
+		switch {
+		case rv.Type() == functionPointerType:
+			b := rv.Interface().(FunctionPointer).Bytes()
+			return b[:], nil
+		case isBytesValue(rv) && rv.Len() == functionPointerLength:
+			return bytesOf(rv), nil
+		}
+
+	case strings.HasPrefix(typeStr, "int") || strings.HasPrefix(typeStr, "uint"):
+		switch {
+		case rv.Type() == bigIntType:
+			return new(big.Int).Set(rv.Addr().Interface().(*big.Int)), nil
+		case rv.Type() == uint256Type:
+			return rv.Addr().Interface().(*uint256.Int).ToBig(), nil
+		case rv.CanInt():
+			return big.NewInt(rv.Int()), nil
+		case rv.CanUint():
+			return new(big.Int).SetUint64(rv.Uint()), nil
+		}
+
+	case strings.HasPrefix(typeStr, "bytes"):
+		switch {
+		case isBytesValue(rv):
+			return bytesOf(rv), nil
+		case rv.Kind() == reflect.String && strings.HasPrefix(rv.String(), "0x"):
+			b, err := hexutil.Decode(rv.String())
+			if err != nil {
+				return nil, fmt.Errorf("[marshalCoreValue] invalid hex string for %s: %w", typeStr, err)
+			}
+			return b, nil
+		case typeStr == "bytes32" && rv.Type() == bigIntType:
+			bi := rv.Addr().Interface().(*big.Int)
+			if bi.Sign() < 0 || bi.BitLen() > 256 {
+				return nil, fmt.Errorf("[marshalCoreValue] value %s does not fit in bytes32", bi)
+			}
+			return common.BigToHash(bi).Bytes(), nil
+		case typeStr == "bytes32" && rv.Type() == uint256Type:
+			b := rv.Addr().Interface().(*uint256.Int).Bytes32()
+			return b[:], nil
+		case typeStr == "bytes24" && rv.Type() == functionPointerType:
+			b := rv.Interface().(FunctionPointer).Bytes()
+			return b[:], nil
+		}
+
+	case isFixedType(typeStr):
+		switch {
+		case rv.Type() == bigFloatType:
+			return new(big.Float).Copy(rv.Addr().Interface().(*big.Float)), nil
+		case rv.Type() == bigRatType:
+			return new(big.Rat).Set(rv.Addr().Interface().(*big.Rat)), nil
+		case rv.CanFloat():
+			return big.NewFloat(rv.Float()), nil
+		}
+	}
+
+	return nil, fmt.Errorf("[marshalCoreValue] cannot marshal %s into %s", rv.Type(), typeStr)
+}
+
+// isCoreStruct checks whether given struct type maps to a single ABI
+// value instead of a tuple (i.e. big.Int).
+func isCoreStruct(t reflect.Type) bool {
+	return t == bigIntType || t == bigFloatType || t == bigRatType || t == functionPointerType
+}
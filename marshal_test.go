@@ -0,0 +1,68 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleMarshal() {
+	type fee struct {
+		Recipient common.Address
+		Bps       uint16
+	}
+	type order struct {
+		Maker   common.Address
+		Amounts []*big.Int
+		Fees    []fee
+		Salt    common.Hash
+		Memo    string
+	}
+
+	signature := "(address,uint256[],(address,uint16)[],bytes32,string)"
+	maker := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	expected := order{
+		Maker:   maker,
+		Amounts: []*big.Int{big.NewInt(100), big.NewInt(352)},
+		Fees:    []fee{{Recipient: maker, Bps: 30}},
+		Salt:    common.HexToHash("0x01"),
+		Memo:    "marshaled",
+	}
+
+	encoded, err := abi.Marshal(expected, signature)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	typeStrs, _ := abi.GetSigTypes(signature)
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(decoded)
+
+	// Output: [0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 [100 352] [[0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 30]] [0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 1] marshaled]
+}
+
+func ExampleMarshal_withSignature() {
+	transfer := struct {
+		To     common.Address
+		Amount *big.Int
+	}{
+		To:     common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+		Amount: big.NewInt(1000),
+	}
+
+	signature := "transfer(address,uint256)"
+	encoded, err := abi.Marshal(transfer, signature)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(common.Bytes2Hex(append(abi.EncodeSignature(signature), encoded...)))
+
+	// Output: a9059cbb0000000000000000000000005ff137d4b0fdcd49dca30c7cf57e578a026d278900000000000000000000000000000000000000000000000000000000000003e8
+}
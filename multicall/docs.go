@@ -0,0 +1,4 @@
+// Package multicall builds Multicall3 `aggregate3` payloads from
+// individual calls and parses the returned results with the abi
+// package.
+package multicall
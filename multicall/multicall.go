@@ -0,0 +1,158 @@
+package multicall
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"github.com/omnes-tech/abi"
+)
+
+// Address is the address Multicall3 is deployed at on most EVM chains.
+var Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+const (
+	aggregate3Signature = "aggregate3((address,bool,bytes)[])"
+	resultsType         = "(bool,bytes)[]"
+)
+
+// Call is a single call aggregated with aggregate3. Outputs describe its
+// return values and are used by ParseResults.
+type Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+	Outputs      []abi.Component
+}
+
+// Result is the result of a single aggregated call. The return data of
+// failed calls holds their revert data, which can be decoded with
+// abi.ParseRevert.
+type Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// aggregate3Return holds the return values of `aggregate3`.
+type aggregate3Return struct {
+	Results []Result
+}
+
+// NewCall creates a call to given target from a human-readable function
+// signature, i.e. `function balanceOf(address owner) returns (uint256 balance)`,
+// and its arguments. The return values of the signature are kept to
+// parse the result of the call.
+func NewCall(target common.Address, signature string, args ...any) (Call, error) {
+	fragment, err := abi.ParseFragment(signature)
+	if err != nil {
+		return Call{}, err
+	}
+
+	callData, err := abi.EncodeWithSelector(fragment.Selector(), fragment.InputTypes(), args...)
+	if err != nil {
+		return Call{}, fmt.Errorf("error encoding call to %s: %w", fragment.Signature(), err)
+	}
+
+	return Call{Target: target, CallData: callData, Outputs: fragment.Outputs}, nil
+}
+
+// EncodeAggregate3 encodes the call data of `aggregate3` with given
+// calls.
+func EncodeAggregate3(calls []Call) ([]byte, error) {
+	values := make([]any, len(calls))
+	for i := range calls {
+		values[i] = []any{&calls[i].Target, calls[i].AllowFailure, calls[i].CallData}
+	}
+
+	return abi.EncodeWithSignature(aggregate3Signature, values)
+}
+
+// DecodeAggregate3 decodes the return data of `aggregate3`.
+func DecodeAggregate3(data []byte) ([]Result, error) {
+	decoded, err := abi.Decode([]string{resultsType}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result aggregate3Return
+	err = abi.Parse(decoded, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Results, nil
+}
+
+// ParseResults decodes the return data of `aggregate3` and parses the
+// return values of each successful call into the struct pointed by the
+// target at the same position, using the outputs of the call. Targets
+// of calls with a single output can also point to the value of that
+// output (i.e. a *big.Int for `balanceOf`). Nil targets are skipped,
+// and so are failed calls, whose targets are left untouched.
+func ParseResults(calls []Call, data []byte, targets ...any) ([]Result, error) {
+	if len(targets) != len(calls) {
+		return nil, fmt.Errorf("expected %d targets, got %d", len(calls), len(targets))
+	}
+
+	results, err := DecodeAggregate3(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(calls), len(results))
+	}
+
+	for i, result := range results {
+		if targets[i] == nil || !result.Success {
+			continue
+		}
+
+		decoded, err := abi.Decode(abi.ComponentTypes(calls[i].Outputs), result.ReturnData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding result %d: %w", i, err)
+		}
+
+		err = parseResult(decoded, calls[i].Outputs, targets[i])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing result %d: %w", i, err)
+		}
+	}
+
+	return results, nil
+}
+
+// valueStructTypes are the struct types parsed from a single value
+// rather than from a tuple.
+var valueStructTypes = map[reflect.Type]bool{
+	reflect.TypeOf(big.Int{}):             true,
+	reflect.TypeOf(big.Float{}):           true,
+	reflect.TypeOf(big.Rat{}):             true,
+	reflect.TypeOf(uint256.Int{}):         true,
+	reflect.TypeOf(abi.FunctionPointer{}): true,
+}
+
+// parseResult parses the decoded return values of a call into target,
+// which points either to a struct or, for calls with a single output,
+// to the value of that output.
+func parseResult(decoded []any, outputs []abi.Component, target any) error {
+	rv := reflect.ValueOf(target)
+	if len(outputs) != 1 || rv.Kind() != reflect.Ptr || rv.IsNil() ||
+		(rv.Elem().Kind() == reflect.Struct && !valueStructTypes[rv.Elem().Type()]) {
+		return abi.ParseWithComponents(decoded, outputs, target)
+	}
+
+	// The value is parsed as the single field of a struct, so that any
+	// target supported by the abi package can be used.
+	wrapper := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: rv.Type()},
+	}))
+	err := abi.Parse(decoded, wrapper.Interface())
+	if err != nil {
+		return err
+	}
+	rv.Elem().Set(wrapper.Elem().Field(0).Elem())
+
+	return nil
+}
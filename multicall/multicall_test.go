@@ -0,0 +1,95 @@
+package multicall_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+	"github.com/omnes-tech/abi/multicall"
+)
+
+func ExampleParseResults() {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+	balanceOf, err := multicall.NewCall(token, "function balanceOf(address owner) view returns (uint256 balance)", owner)
+	if err != nil {
+		fmt.Println(err)
+	}
+	symbol, err := multicall.NewCall(token, "function symbol() view returns (string)")
+	if err != nil {
+		fmt.Println(err)
+	}
+	symbol.AllowFailure = true
+
+	calls := []multicall.Call{balanceOf, symbol}
+	callData, err := multicall.EncodeAggregate3(calls)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(common.Bytes2Hex(callData[:4]))
+
+	// Return data of aggregate3, as returned by eth_call to multicall.Address.
+	balanceData, _ := abi.Encode([]string{"uint256"}, big.NewInt(1000))
+	symbolData, _ := abi.Encode([]string{"string"}, "USDC")
+	returnData, err := abi.Encode([]string{"(bool,bytes)[]"}, []any{[]any{true, balanceData}, []any{true, symbolData}})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var balance struct {
+		Balance *big.Int `abi:"balance"`
+	}
+	var tokenSymbol struct {
+		Symbol string
+	}
+	results, err := multicall.ParseResults(calls, returnData, &balance, &tokenSymbol)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(len(results), results[0].Success, balance.Balance, tokenSymbol.Symbol)
+
+	// Output:
+	// 82ad56cb
+	// 2 true 1000 USDC
+}
+
+func ExampleParseResults_singleOutput() {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+	balanceOf, err := multicall.NewCall(token, "function balanceOf(address owner) view returns (uint256)", owner)
+	if err != nil {
+		fmt.Println(err)
+	}
+	decimals, err := multicall.NewCall(token, "function decimals() view returns (uint8)")
+	if err != nil {
+		fmt.Println(err)
+	}
+	calls := []multicall.Call{balanceOf, decimals, balanceOf}
+
+	balanceData, _ := abi.Encode([]string{"uint256"}, big.NewInt(1000))
+	decimalsData, _ := abi.Encode([]string{"uint8"}, big.NewInt(6))
+	returnData, err := abi.Encode([]string{"(bool,bytes)[]"}, []any{
+		[]any{true, balanceData},
+		[]any{true, decimalsData},
+		[]any{true, balanceData},
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var balance *big.Int
+	var tokenDecimals uint8
+	allocated := new(big.Int)
+	_, err = multicall.ParseResults(calls, returnData, &balance, &tokenDecimals, allocated)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(balance, tokenDecimals, allocated)
+
+	// Output: 1000 6 1000
+}
@@ -0,0 +1,5 @@
+// Package multisend encodes and decodes the packed transaction bundles
+// executed by the Gnosis Safe MultiSend contracts, whose format is not
+// standard ABI, so that each bundled call can then be decoded with the
+// abi package.
+package multisend
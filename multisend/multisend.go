@@ -0,0 +1,165 @@
+package multisend
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+const multiSendSignature = "multiSend(bytes)"
+
+// headerLength is the length of the packed fields preceding the data of
+// a transaction: its operation (1 byte), target (20 bytes), value and
+// data length (32 bytes each).
+const headerLength = 1 + common.AddressLength + 32 + 32
+
+// packedTypes are the types of the packed fields of a transaction.
+var packedTypes = []string{"uint8", "address", "uint256", "uint256", "bytes"}
+
+// Operation is the kind of call made for a bundled transaction.
+type Operation uint8
+
+const (
+	// Call is a regular call.
+	Call Operation = 0
+	// DelegateCall is a delegate call, executed in the context of the
+	// Safe. MultiSendCallOnly rejects them.
+	DelegateCall Operation = 1
+)
+
+// String returns the name of the operation.
+func (o Operation) String() string {
+	switch o {
+	case Call:
+		return "call"
+	case DelegateCall:
+		return "delegatecall"
+	}
+
+	return fmt.Sprintf("operation(%d)", uint8(o))
+}
+
+// SafeTx is a transaction bundled in a multiSend payload. A nil value
+// is a zero value.
+type SafeTx struct {
+	Operation Operation
+	To        common.Address
+	Value     *big.Int
+	Data      []byte
+}
+
+// NewTx creates a call to given target from a function signature (see
+// abi.ParseFragment) and its arguments, sending given value.
+func NewTx(to common.Address, value *big.Int, signature string, args ...any) (SafeTx, error) {
+	fragment, err := abi.ParseFragment(signature)
+	if err != nil {
+		return SafeTx{}, err
+	}
+
+	data, err := abi.EncodeWithSelector(fragment.Selector(), fragment.InputTypes(), args...)
+	if err != nil {
+		return SafeTx{}, fmt.Errorf("error encoding call to %s: %w", fragment.Signature(), err)
+	}
+
+	return SafeTx{Operation: Call, To: to, Value: value, Data: data}, nil
+}
+
+// DecodeCall resolves the selector of the transaction data in given
+// registry, returning the name of the called method and its decoded
+// arguments (see abi.DecodeCalldata).
+func (tx SafeTx) DecodeCall(registry *abi.Registry) (string, []any, error) {
+	return abi.DecodeCalldata(tx.Data, registry)
+}
+
+// EncodeTransactions packs given transactions, each one being encoded
+// as `abi.encodePacked(operation, to, value, data.length, data)`.
+func EncodeTransactions(txs []SafeTx) ([]byte, error) {
+	var packed []byte
+	for i, tx := range txs {
+		if tx.Operation > DelegateCall {
+			return nil, fmt.Errorf("invalid operation %d of transaction %d", tx.Operation, i)
+		}
+		value := tx.Value
+		if value == nil {
+			value = new(big.Int)
+		}
+
+		encoded, err := abi.EncodePacked(packedTypes,
+			big.NewInt(int64(tx.Operation)),
+			&tx.To,
+			value,
+			big.NewInt(int64(len(tx.Data))),
+			tx.Data,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding transaction %d: %w", i, err)
+		}
+		packed = append(packed, encoded...)
+	}
+
+	return packed, nil
+}
+
+// DecodeTransactions unpacks transactions packed by EncodeTransactions.
+func DecodeTransactions(packed []byte) ([]SafeTx, error) {
+	var txs []SafeTx
+	for offset := 0; offset < len(packed); {
+		if len(packed)-offset < headerLength {
+			return nil, fmt.Errorf("transaction %d at offset %d is truncated: %d bytes left, expected at least %d", len(txs), offset, len(packed)-offset, headerLength)
+		}
+		header := packed[offset : offset+headerLength]
+
+		operation := Operation(header[0])
+		if operation > DelegateCall {
+			return nil, fmt.Errorf("invalid operation %d of transaction %d at offset %d", header[0], len(txs), offset)
+		}
+
+		dataLength := new(big.Int).SetBytes(header[1+common.AddressLength+32:])
+		remaining := len(packed) - offset - headerLength
+		if !dataLength.IsInt64() || dataLength.Int64() > int64(remaining) {
+			return nil, fmt.Errorf("data length %s of transaction %d exceeds the %d bytes left", dataLength, len(txs), remaining)
+		}
+		dataStart := offset + headerLength
+		dataEnd := dataStart + int(dataLength.Int64())
+
+		txs = append(txs, SafeTx{
+			Operation: operation,
+			To:        common.BytesToAddress(header[1 : 1+common.AddressLength]),
+			Value:     new(big.Int).SetBytes(header[1+common.AddressLength : 1+common.AddressLength+32]),
+			Data:      bytes.Clone(packed[dataStart:dataEnd]),
+		})
+		offset = dataEnd
+	}
+
+	return txs, nil
+}
+
+// EncodeMultiSend encodes the call data of `multiSend(bytes)` bundling
+// given transactions.
+func EncodeMultiSend(txs []SafeTx) ([]byte, error) {
+	packed, err := EncodeTransactions(txs)
+	if err != nil {
+		return nil, err
+	}
+
+	return abi.EncodeWithSignature(multiSendSignature, packed)
+}
+
+// DecodeMultiSend decodes the call data of `multiSend(bytes)` into the
+// bundled transactions.
+func DecodeMultiSend(callData []byte) ([]SafeTx, error) {
+	decoded, err := abi.DecodeWithSignature(multiSendSignature, callData)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", multiSendSignature, err)
+	}
+
+	packed, ok := decoded[0].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("expected bytes argument, got %T", decoded[0])
+	}
+
+	return DecodeTransactions(packed)
+}
@@ -0,0 +1,68 @@
+package multisend_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+	"github.com/omnes-tech/abi/multisend"
+)
+
+func ExampleDecodeMultiSend() {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	recipient := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+	transfer, err := multisend.NewTx(token, nil, "function transfer(address to, uint256 amount)", recipient, big.NewInt(1000))
+	if err != nil {
+		fmt.Println(err)
+	}
+	payment := multisend.SafeTx{To: recipient, Value: big.NewInt(1e18)}
+
+	callData, err := multisend.EncodeMultiSend([]multisend.SafeTx{transfer, payment})
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(common.Bytes2Hex(callData[:4]))
+
+	txs, err := multisend.DecodeMultiSend(callData)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	registry, _ := abi.NewRegistry("function transfer(address to, uint256 amount)")
+	for _, tx := range txs {
+		fmt.Println(tx.Operation, tx.To.Hex(), tx.Value, len(tx.Data))
+		if len(tx.Data) > 0 {
+			method, args, err := tx.DecodeCall(registry)
+			fmt.Println(method, args, err)
+		}
+	}
+
+	// Output:
+	// 8d80ff0a
+	// call 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 0 68
+	// transfer [0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1000] <nil>
+	// call 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1000000000000000000 0
+}
+
+func ExampleDecodeTransactions() {
+	packed, err := multisend.EncodeTransactions([]multisend.SafeTx{
+		{Operation: multisend.DelegateCall, To: common.HexToAddress("0x01"), Data: []byte{0xde, 0xad}},
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(len(packed))
+
+	txs, err := multisend.DecodeTransactions(packed)
+	fmt.Println(txs[0].Operation, txs[0].To.Hex(), common.Bytes2Hex(txs[0].Data), err)
+
+	_, err = multisend.DecodeTransactions(packed[:len(packed)-1])
+	fmt.Println(err)
+
+	// Output:
+	// 87
+	// delegatecall 0x0000000000000000000000000000000000000001 dead <nil>
+	// data length 2 of transaction 0 exceeds the 1 bytes left
+}
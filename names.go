@@ -0,0 +1,54 @@
+package abi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamingStrategy normalizes the names of struct fields and ABI
+// parameters, which match when their normalized names are equal. It is
+// set in Options.Naming to map untagged fields by name (i.e. a TokenID
+// field to a tokenId parameter) and to match tagged names loosely.
+type NamingStrategy func(name string) string
+
+// FoldCase is a NamingStrategy matching names case-insensitively, i.e.
+// `tokenId` and `TokenID`.
+func FoldCase(name string) string {
+	return strings.ToLower(name)
+}
+
+// FoldSnakeCase is a NamingStrategy matching names case-insensitively
+// and ignoring underscores, i.e. `tokenId`, `TokenID`, `token_id` and
+// `_tokenId`.
+func FoldSnakeCase(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// componentPosition returns the position of the component with given
+// name, or -1 when there is none. Exact matches are preferred, then
+// components matching with the naming strategy, when set, which must be
+// unique.
+func componentPosition(name string, components []Component, naming NamingStrategy) (int, error) {
+	for i, component := range components {
+		if component.Name == name {
+			return i, nil
+		}
+	}
+	if naming == nil {
+		return -1, nil
+	}
+
+	position := -1
+	normalized := naming(name)
+	for i, component := range components {
+		if component.Name == "" || naming(component.Name) != normalized {
+			continue
+		}
+		if position != -1 {
+			return -1, fmt.Errorf("name %q matches both %q and %q", name, components[position].Name, component.Name)
+		}
+		position = i
+	}
+
+	return position, nil
+}
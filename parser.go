@@ -1,179 +1,930 @@
 package abi
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/uint256"
 )
 
+// Options controls the behavior of ParseWithOptions. The zero value
+// gives the strict behavior of Parse.
+type Options struct {
+	// Components describe the decoded values, and are used to map fields
+	// tagged with `abi:"name"` to the value of the component with the
+	// same name. Components of nested tuples are used for nested structs.
+	Components []Component
+
+	// AllowExtraValues allows decoded tuples to hold more values than the
+	// fields of the target struct, so that partial structs can be parsed.
+	// Values without a corresponding field are ignored.
+	AllowExtraValues bool
+
+	// AllowNarrowing allows numeric conversions that do not preserve the
+	// decoded value (i.e. parsing 300 into a uint8 field), silently
+	// truncating it instead of returning an error wrapping ErrOverflow.
+	AllowNarrowing bool
+
+	// StrictAddresses rejects address strings which are not 40 hex
+	// digits, optionally 0x prefixed, or whose mixed case fails their
+	// EIP-55 checksum, returning an error wrapping ErrInvalidAddress
+	// instead of the padded or truncated address given by
+	// common.HexToAddress. Addresses decoded by Decode are always valid,
+	// so it matters for values built by hand or by other decoders.
+	StrictAddresses bool
+
+	// AllowNil allows nil decoded values, leaving the corresponding field
+	// untouched instead of returning an error.
+	AllowNil bool
+
+	// Limits bounds the nesting depth, number of elements and bytes
+	// length of decoded values, for DecodeWithOptions and when parsing.
+	// Exceeding them returns an error wrapping ErrLimitExceeded.
+	Limits Limits
+
+	// Naming, when set, matches the names of struct fields and ABI
+	// parameters loosely (i.e. FoldSnakeCase matches a TokenID field to a
+	// tokenId or token_id parameter), both for fields tagged with
+	// `abi:"name"` or `abi:",mapkey=name"` and for untagged fields, which
+	// are mapped to the component matching their field name or, when
+	// none matches, by their positional order. It requires Components.
+	Naming NamingStrategy
+
+	// Progress, when set, is called after each parsed array element with
+	// the number of array elements parsed so far, nested ones included,
+	// so that parsing huge arrays can be observed.
+	Progress func(parsed int)
+}
+
+// parseState holds the state of a single Parse call.
+type parseState struct {
+	opts   Options
+	limits limitState
+	depth  int
+	ctx    context.Context // nil when parsing without a context
+	parsed int             // number of array elements parsed
+}
+
+// Parse parses decoded values into the struct pointed by v. Values are
+// mapped to struct fields by their positional order, unless fields are
+// tagged with an explicit `abi:"index=N"` position. Unexported fields
+// are skipped, but the fields promoted from unexported embedded structs
+// are parsed. Decoded integers
+// are converted to integer fields (i.e. `type OrderStatus uint8`) when
+// they fit, and fields tagged with `abi:",enum=Open|Closed"` receive
+// either the index or the name of a Solidity enum member. Decoded
+// integers are divided by 10^N when parsed into *big.Rat, *big.Float or
+// decimal fields tagged with `abi:",decimals=N"` (i.e. token amounts),
+// and fixed point numbers are parsed into *big.Float or *big.Rat fields.
+// Decoded fixed bytes (i.e. bytes32) are parsed into []byte, [32]byte,
+// common.Hash, string (as hex) or *big.Int fields depending on the
+// field type. Pointer fields and elements (i.e. []*Order for a tuple
+// array) are allocated for each decoded value, so that large tuples
+// can be shared without copies, while pointer fields tagged with
+// `abi:",omitzero"` are left nil when receiving zero values.
 func Parse(decoded []any, v any) error {
-	return parseStruct(decoded, v)
+	return ParseWithOptions(decoded, v, Options{})
 }
 
-// parseStruct parses decoded values into a struct
-func parseStruct(decoded []any, structVal any) error {
-	rv := reflect.ValueOf(structVal)
-	if rv.Kind() != reflect.Ptr || rv.IsNil() {
-		return fmt.Errorf("[parseStruct] v must be a pointer")
+// ParseWithComponents parses decoded values into the struct pointed by
+// v, using given components to map fields tagged with `abi:"name"` to
+// the decoded value of the component with the same name. Components
+// of nested tuples are used for nested structs.
+func ParseWithComponents(decoded []any, components []Component, v any) error {
+	return ParseWithOptions(decoded, v, Options{Components: components})
+}
+
+// ParseWithOptions parses decoded values into the struct pointed by v
+// following given options.
+func ParseWithOptions(decoded []any, v any, opts Options) error {
+	s := &parseState{opts: opts, limits: limitState{limits: opts.Limits}}
+	return parseStruct(s, decoded, opts.Components, v)
+}
+
+// ParseCtx parses decoded values into the struct pointed by v like
+// Parse, checking ctx before each array element so that parsing huge
+// arrays can be aborted. When ctx is done, the returned error wraps
+// ctx.Err() (i.e. errors.Is(err, context.Canceled)) and v is left
+// partially parsed.
+func ParseCtx(ctx context.Context, decoded []any, v any) error {
+	return ParseWithOptionsCtx(ctx, decoded, v, Options{})
+}
+
+// ParseWithOptionsCtx parses decoded values into the struct pointed by
+// v following given options, checking ctx like ParseCtx.
+func ParseWithOptionsCtx(ctx context.Context, decoded []any, v any, opts Options) error {
+	s := &parseState{opts: opts, limits: limitState{limits: opts.Limits}, ctx: ctx}
+	return parseStruct(s, decoded, opts.Components, v)
+}
+
+// ParseAs parses decoded values into a new value of type T, which must
+// be either a struct or a pointer to a struct. Pointer types are
+// allocated before parsing. On error, the zero value of T is returned.
+func ParseAs[T any](decoded []any) (T, error) {
+	var result T
+
+	rt := reflect.TypeOf(&result).Elem()
+	switch {
+	case rt.Kind() == reflect.Struct:
+		err := parseStruct(&parseState{}, decoded, nil, &result)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+	case rt.Kind() == reflect.Ptr && rt.Elem().Kind() == reflect.Struct:
+		target := reflect.New(rt.Elem())
+		err := parseStruct(&parseState{}, decoded, nil, target.Interface())
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		result = target.Interface().(T)
+	default:
+		return result, fmt.Errorf("[ParseAs] T must be a struct or a struct pointer, got %s", rt)
 	}
 
-	rve := rv.Elem()
-	if rve.Kind() != reflect.Struct {
-		return fmt.Errorf("[parseStruct] v must be a struct pointer")
+	return result, nil
+}
+
+// setter parses a decoded value into target, which must be settable.
+// Components describe the members of tuple values.
+type setter func(s *parseState, target reflect.Value, value any, components []Component) error
+
+// structPlan holds the precomputed parsing plan of a struct type.
+type structPlan struct {
+	fields    []fieldPlan
+	numValues int   // number of decoded values mapped to the fields
+	err       error // error found while reading the struct tags
+}
+
+// fieldPlan holds the precomputed parsing plan of a struct field.
+type fieldPlan struct {
+	index []int  // index path of the field in the struct
+	name  string // name of the field in the struct
+	tag   FieldTag
+	set   setter
+}
+
+// setters caches the setter of each parsed type, and structPlans the
+// plan of each parsed struct type. Both are reset whenever a decoder
+// or a converter is registered, since registered hooks change the plans.
+var (
+	setters     sync.Map // map[reflect.Type]setter
+	structPlans sync.Map // map[reflect.Type]*structPlan
+)
+
+var (
+	anySliceType    = reflect.TypeOf([]any{})
+	bigIntPtrType   = reflect.TypeOf(&big.Int{})
+	bigFloatPtrType = reflect.TypeOf(&big.Float{})
+	addressPtrType  = reflect.TypeOf(&common.Address{})
+
+	uint256Type    = reflect.TypeOf(uint256.Int{})
+	uint256PtrType = reflect.TypeOf(&uint256.Int{})
+)
+
+// resetPlans drops all cached setters and struct plans.
+func resetPlans() {
+	setters.Range(func(key, _ any) bool {
+		setters.Delete(key)
+		return true
+	})
+	structPlans.Range(func(key, _ any) bool {
+		structPlans.Delete(key)
+		return true
+	})
+}
+
+// setterFor returns the cached setter for given type, building it when
+// needed. Recursive types are supported by storing a forwarding setter
+// while the actual one is built.
+func setterFor(t reflect.Type) setter {
+	if cached, ok := setters.Load(t); ok {
+		return cached.(setter)
 	}
 
-	if len(decoded) != rve.NumField() && rve.Type().String() != "big.Int" && rve.Type().String() != "common.Address" {
-		return fmt.Errorf("[parseStruct] number of decoded values does not match number of struct fields")
+	var (
+		wg    sync.WaitGroup
+		built setter
+	)
+	wg.Add(1)
+	cached, loaded := setters.LoadOrStore(t, setter(func(s *parseState, target reflect.Value, value any, components []Component) error {
+		wg.Wait()
+		return built(s, target, value, components)
+	}))
+	if loaded {
+		return cached.(setter)
 	}
 
-	for i := 0; i < rve.NumField(); i++ {
-		field := rve.Field(i)
-		vType := reflect.TypeOf(decoded[i])
-		if field.Kind() == reflect.Ptr && field.Type().Elem().String() != "big.Int" && field.Type().Elem().String() != "common.Address" {
-			var err error
-			if field.Type().Elem().Kind() == reflect.Struct {
-				if field.IsNil() {
-					field.Set(reflect.New(field.Type().Elem()))
-				}
-				err = parseStruct(decoded[i].([]any), field.Interface())
-			} else {
-				err = parsePointer([]any{decoded[i]}, field)
-			}
+	built = newSetter(t)
+	wg.Done()
+	setters.Store(t, built)
+
+	return built
+}
+
+// newSetter builds the setter for given type.
+func newSetter(t reflect.Type) setter {
+	if hook, ok := lookupDecoder(t); ok {
+		return func(_ *parseState, target reflect.Value, value any, _ []Component) error {
+			err := setWithDecoder(target, value, hook)
 			if err != nil {
-				return fmt.Errorf("[parseStruct] error parsing pointer field %s: %w", field.Type().Name(), err)
+				return newParseError(fmt.Errorf("error decoding %s with registered decoder: %w", t, err), t, value)
 			}
-		} else if field.Kind() == reflect.Struct {
-			err := parseStruct(decoded[i].([]any), field.Addr().Interface())
+			return nil
+		}
+	}
+
+	unmarshaler := isUnmarshaler(t)
+
+	var converters map[reflect.Type]ConvertFunc
+	if !unmarshaler {
+		converters = convertersTo(t)
+	}
+
+	var set setter
+	switch {
+	case unmarshaler:
+		set = setUnmarshaler
+	case t == bigIntPtrType:
+		set = setBigInt
+	case t == bigRatPtrType:
+		set = setBigRat
+	case t == addressType:
+		set = setAddress
+	case t == addressPtrType:
+		set = setAddressPointer
+	case t == uint256Type || t == uint256PtrType:
+		set = setUint256
+	case t == functionPointerType:
+		set = setFunctionPointer
+	case t.Kind() == reflect.Ptr:
+		set = newPointerSetter(t)
+	case t.Kind() == reflect.Struct:
+		set = newStructSetter(t)
+	case t.Kind() == reflect.Slice:
+		set = newSliceSetter(t)
+	case t.Kind() == reflect.Array:
+		set = newArraySetter(t)
+	default:
+		set = setConvertible
+	}
+
+	return func(s *parseState, target reflect.Value, value any, components []Component) error {
+		if value == nil {
+			if s.opts.AllowNil {
+				return nil
+			}
+			return newParseError(fmt.Errorf("nil decoded value for %s", t), t, value)
+		}
+
+		err := s.checkBytesLength(value)
+		if err != nil {
+			return newParseError(err, t, value)
+		}
+
+		vType := reflect.TypeOf(value)
+		if convert, ok := converters[vType]; ok {
+			err = setWithDecoder(target, value, DecoderFunc(convert))
 			if err != nil {
-				return fmt.Errorf("[parseStruct] error parsing struct field %s: %w", field.Type().Name(), err)
+				return newParseError(fmt.Errorf("error converting %s to %s with registered converter: %w", vType, t, err), t, value)
 			}
-		} else if field.Kind() == reflect.Slice || field.Kind() == reflect.Array {
-			if vType.String() == "[]uint8" || vType.String() == "[]byte" {
-				field.Set(reflect.ValueOf(decoded[i].([]byte)))
-			} else if vType.String() == "string" {
-				fieldName := field.Type().String()
-				if strings.TrimPrefix(fieldName, "*") == "common.Address" {
-					field.Set(reflect.ValueOf(common.HexToAddress(decoded[i].(string))))
-				} else {
-					field.Set(reflect.ValueOf(decoded[i]))
-				}
-			} else {
-				err := parseSlice(decoded[i].([]any), field.Addr().Interface())
-				if err != nil {
-					return fmt.Errorf("[parseStruct] error parsing slice field %s: %w", field.Type().Name(), err)
-				}
+			return nil
+		}
+		if !unmarshaler && vType != anySliceType && vType.AssignableTo(t) {
+			target.Set(reflect.ValueOf(value))
+			return nil
+		}
+
+		err = set(s, target, value, components)
+		if err != nil {
+			return newParseError(err, t, value)
+		}
+		return nil
+	}
+}
+
+// enter records parsing a tuple or array of n decoded values, one
+// level deeper, checking the limits of the options.
+func (s *parseState) enter(n int) error {
+	s.depth++
+	err := s.limits.checkDepth(s.depth)
+	if err != nil {
+		return err
+	}
+
+	return s.limits.addElements(n)
+}
+
+// leave records the end of parsing a tuple or array.
+func (s *parseState) leave() {
+	s.depth--
+}
+
+// checkContext returns the error of the context of the state once it is
+// done.
+func (s *parseState) checkContext() error {
+	if s.ctx == nil {
+		return nil
+	}
+
+	return s.ctx.Err()
+}
+
+// elementParsed records a parsed array element, reporting the progress
+// to the options.
+func (s *parseState) elementParsed() {
+	s.parsed++
+	if s.opts.Progress != nil {
+		s.opts.Progress(s.parsed)
+	}
+}
+
+// checkBytesLength checks the length of decoded string and bytes values
+// against the limits of the options.
+func (s *parseState) checkBytesLength(value any) error {
+	switch value := value.(type) {
+	case string:
+		return s.limits.checkBytesLength(uint64(len(value)))
+	case []byte:
+		return s.limits.checkBytesLength(uint64(len(value)))
+	}
+
+	return nil
+}
+
+// setBigInt sets a decoded *big.Int value, or decoded fixed bytes (i.e.
+// a bytes32 value) read as a big-endian unsigned integer.
+func setBigInt(_ *parseState, target reflect.Value, value any, _ []Component) error {
+	var bi *big.Int
+	switch value := value.(type) {
+	case *big.Int:
+		bi = value
+	case []byte:
+		bi = new(big.Int).SetBytes(value)
+	default:
+		return newMismatchError("*big.Int", value)
+	}
+	target.Set(reflect.ValueOf(bi))
+
+	return nil
+}
+
+// setAddress sets a decoded address value.
+func setAddress(s *parseState, target reflect.Value, value any, _ []Component) error {
+	address, err := parseAddress(s, value)
+	if err != nil {
+		return err
+	}
+	target.Set(reflect.ValueOf(address))
+
+	return nil
+}
+
+// setAddressPointer sets a decoded address value into a
+// *common.Address target.
+func setAddressPointer(s *parseState, target reflect.Value, value any, _ []Component) error {
+	address, err := parseAddress(s, value)
+	if err != nil {
+		return err
+	}
+	target.Set(reflect.ValueOf(&address))
+
+	return nil
+}
+
+// parseAddress converts a decoded address string into an address,
+// validating it when parsing with Options.StrictAddresses.
+func parseAddress(s *parseState, value any) (common.Address, error) {
+	addressStr, ok := value.(string)
+	if !ok {
+		return common.Address{}, newMismatchError("address string", value)
+	}
+	if !s.opts.StrictAddresses {
+		return common.HexToAddress(addressStr), nil
+	}
+
+	if !common.IsHexAddress(addressStr) {
+		return common.Address{}, fmt.Errorf("%w %q: expected 40 hex digits", ErrInvalidAddress, addressStr)
+	}
+	address := common.HexToAddress(addressStr)
+	digits := addressStr[len(addressStr)-2*common.AddressLength:]
+	if strings.ToLower(digits) != digits && strings.ToUpper(digits) != digits && address.Hex()[2:] != digits {
+		return common.Address{}, fmt.Errorf("%w %q: bad EIP-55 checksum", ErrInvalidAddress, addressStr)
+	}
+
+	return address, nil
+}
+
+// setUint256 sets a decoded *big.Int value (or fixed bytes read as a
+// big-endian integer) into a uint256.Int or *uint256.Int target,
+// reusing the target when already allocated.
+func setUint256(_ *parseState, target reflect.Value, value any, _ []Component) error {
+	bi, ok := value.(*big.Int)
+	if b, isBytes := value.([]byte); isBytes {
+		bi, ok = new(big.Int).SetBytes(b), true
+	}
+	if !ok {
+		return newMismatchError("*big.Int", value)
+	}
+	if bi.Sign() < 0 {
+		return fmt.Errorf("negative value %s does not fit in %s", bi, target.Type())
+	}
+
+	var u *uint256.Int
+	if target.Type() == uint256PtrType {
+		if target.IsNil() {
+			target.Set(reflect.New(uint256Type))
+		}
+		u = target.Interface().(*uint256.Int)
+	} else {
+		u = target.Addr().Interface().(*uint256.Int)
+	}
+
+	if u.SetFromBig(bi) {
+		return newOverflowError(bi, target.Type())
+	}
+
+	return nil
+}
+
+// setConvertible sets a decoded value, converting it to the target type
+// when they do not match. Decoded bytes are set into string targets as
+// 0x-prefixed hex.
+func setConvertible(s *parseState, target reflect.Value, value any, _ []Component) error {
+	if value == nil {
+		return fmt.Errorf("cannot convert nil to %s", target.Type())
+	}
+	if _, ok := value.(common.Hash); ok && (target.Kind() == reflect.String || target.Kind() == reflect.Slice) {
+		return fmt.Errorf("indexed parameters of dynamic types only hold their keccak256 hash, parse them into common.Hash or IndexedHash instead of %s", target.Type())
+	}
+	if b, ok := value.([]byte); ok && target.Kind() == reflect.String {
+		target.SetString(hexutil.Encode(b))
+		return nil
+	}
+
+	if bi, ok := value.(*big.Int); ok && (isIntKind(target.Kind()) || isUintKind(target.Kind())) {
+		return setInteger(s, target, bi)
+	}
+
+	val := reflect.ValueOf(value)
+	if val.Type() != target.Type() {
+		if !val.CanConvert(target.Type()) {
+			return fmt.Errorf("cannot convert %T to %s", value, target.Type())
+		}
+		if !s.opts.AllowNarrowing && isNarrowing(val, target.Type()) {
+			return newOverflowError(value, target.Type())
+		}
+		val = val.Convert(target.Type())
+	}
+	target.Set(val)
+
+	return nil
+}
+
+// setInteger sets a decoded *big.Int value into an integer target (i.e.
+// uint8 or a named `type OrderStatus uint8`), checking that it fits
+// unless narrowing is allowed.
+func setInteger(s *parseState, target reflect.Value, bi *big.Int) error {
+	switch {
+	case isIntKind(target.Kind()):
+		if !bi.IsInt64() || target.OverflowInt(bi.Int64()) {
+			if !s.opts.AllowNarrowing {
+				return newOverflowError(bi, target.Type())
+			}
+		}
+		target.SetInt(bi.Int64())
+	default:
+		if !bi.IsUint64() || target.OverflowUint(bi.Uint64()) {
+			if !s.opts.AllowNarrowing {
+				return newOverflowError(bi, target.Type())
 			}
+		}
+		target.SetUint(bi.Uint64())
+	}
+
+	return nil
+}
+
+// isNarrowing checks whether converting the integer value val to the
+// integer (or float) type t would not preserve the value.
+func isNarrowing(val reflect.Value, t reflect.Type) bool {
+	if t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64 {
+		return isInexactFloat(val, t)
+	}
+
+	switch {
+	case val.CanInt() && isIntKind(t.Kind()):
+		return reflect.Zero(t).OverflowInt(val.Int())
+	case val.CanInt() && isUintKind(t.Kind()):
+		return val.Int() < 0 || reflect.Zero(t).OverflowUint(uint64(val.Int()))
+	case val.CanUint() && isIntKind(t.Kind()):
+		return val.Uint() > math.MaxInt64 || reflect.Zero(t).OverflowInt(int64(val.Uint()))
+	case val.CanUint() && isUintKind(t.Kind()):
+		return reflect.Zero(t).OverflowUint(val.Uint())
+	}
+
+	return false
+}
+
+// isInexactFloat checks whether converting the integer value val to the
+// float type t rounds it.
+func isInexactFloat(val reflect.Value, t reflect.Type) bool {
+	var bi *big.Int
+	switch {
+	case val.CanInt():
+		bi = big.NewInt(val.Int())
+	case val.CanUint():
+		bi = new(big.Int).SetUint64(val.Uint())
+	default:
+		return false
+	}
+
+	converted := val.Convert(t).Float()
+	exact, accuracy := new(big.Float).SetFloat64(converted).Int(nil)
+
+	return accuracy != big.Exact || exact.Cmp(bi) != 0
+}
+
+// isIntKind checks whether given kind is a signed integer kind.
+func isIntKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+// isUintKind checks whether given kind is an unsigned integer kind.
+func isUintKind(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uintptr
+}
+
+// newPointerSetter builds the setter for a pointer type, which
+// allocates the pointed value and parses the decoded value into it.
+func newPointerSetter(t reflect.Type) setter {
+	elemSet := setterFor(t.Elem())
+	return func(s *parseState, target reflect.Value, value any, components []Component) error {
+		return parsePointer(s, value, components, target, elemSet)
+	}
+}
+
+// newOmitZeroSetter builds the setter of a pointer field tagged with
+// `abi:",omitzero"`, which parses decoded values with set into a new
+// pointer and leaves the field nil when the pointed value is zero.
+func newOmitZeroSetter(t reflect.Type, set setter) setter {
+	return func(s *parseState, target reflect.Value, value any, components []Component) error {
+		parsed := reflect.New(t).Elem()
+		err := set(s, parsed, value, components)
+		if err != nil {
+			return err
+		}
+
+		if isZeroValue(parsed) {
+			target.Set(reflect.Zero(t))
 		} else {
-			fieldName := field.Type().String()
-			var val reflect.Value
-
-			// Handle pointer fields that were excluded above
-			if field.Kind() == reflect.Ptr {
-				if field.Type().Elem().String() == "big.Int" {
-					// *big.Int - decoded[i] should already be *big.Int
-					if bi, ok := decoded[i].(*big.Int); ok {
-						field.Set(reflect.ValueOf(bi))
-					} else {
-						return fmt.Errorf("[parseStruct] expected *big.Int, got %T", decoded[i])
-					}
-				} else if field.Type().Elem().String() == "common.Address" {
-					// *common.Address
-					addr := common.HexToAddress(decoded[i].(string))
-					field.Set(reflect.ValueOf(&addr))
-				} else {
-					return fmt.Errorf("[parseStruct] unsupported pointer type: %s", field.Type())
-				}
-			} else if strings.TrimPrefix(fieldName, "*") == "common.Address" {
-				val = reflect.ValueOf(common.HexToAddress(decoded[i].(string)))
-				field.Set(val)
-			} else {
-				val = reflect.ValueOf(decoded[i])
-				// Try to convert if types don't match
-				if val.Type() != field.Type() {
-					if val.CanConvert(field.Type()) {
-						val = val.Convert(field.Type())
-					} else {
-						return fmt.Errorf("[parseStruct] cannot convert %T to %s", decoded[i], field.Type())
-					}
+			target.Set(parsed)
+		}
+
+		return nil
+	}
+}
+
+// isZeroValue checks whether a parsed value is the zero value of its
+// type, big numbers being zero when equal to 0 and structs when all
+// their fields are zero.
+func isZeroValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil() || isZeroValue(v.Elem())
+	case reflect.Struct:
+		switch v.Type() {
+		case bigIntType, bigFloatType, bigRatType:
+			return unwrapValue(v).Addr().Interface().(interface{ Sign() int }).Sign() == 0
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if !v.Type().Field(i).IsExported() {
+				if !field.IsZero() {
+					return false
 				}
-				field.Set(val)
+			} else if !isZeroValue(field) {
+				return false
 			}
 		}
+		return true
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isZeroValue(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
 	}
 
-	return nil
+	return v.IsZero()
 }
 
-func parseSlice(decoded []any, sliceVal any) error {
-	rv := reflect.ValueOf(sliceVal)
-	if rv.Kind() != reflect.Ptr || rv.IsNil() {
-		return fmt.Errorf("[parseSlice] v must be a pointer")
+// newStructSetter builds the setter for a struct type, which expects a
+// decoded tuple.
+func newStructSetter(t reflect.Type) setter {
+	return func(s *parseState, target reflect.Value, value any, components []Component) error {
+		decoded, ok := value.([]any)
+		if !ok {
+			return newMismatchError("tuple for "+t.String(), value)
+		}
+		return parseStructValue(s, decoded, components, target)
 	}
+}
 
-	rve := rv.Elem()
-	if rve.Kind() != reflect.Slice {
-		return fmt.Errorf("[parseSlice] v must be a slice pointer")
+// newSliceSetter builds the setter for a slice type, which expects a
+// decoded array.
+func newSliceSetter(t reflect.Type) setter {
+	elemSet := setterFor(t.Elem())
+	return func(s *parseState, target reflect.Value, value any, components []Component) error {
+		decoded, ok := value.([]any)
+		if !ok {
+			return setConvertible(s, target, value, components)
+		}
+		return parseSlice(s, decoded, components, target, elemSet)
 	}
+}
 
-	arrElem := rve.Type().Elem()
-	for i := range decoded {
-		if arrElem.Kind() == reflect.Ptr && arrElem.Elem().String() != "big.Int" && arrElem.Elem().String() != "common.Address" {
-			// Create a new pointer element (e.g., *big.Int)
-			newPtr := reflect.New(arrElem.Elem())
-			err := parsePointer(decoded[i].([]any), newPtr)
-			if err != nil {
-				return fmt.Errorf("[parseSlice] error parsing pointer field %s: %w", rve.Type().Name(), err)
+// newArraySetter builds the setter for a fixed-size array type, which
+// expects either a decoded array of the same length, an array value
+// convertible to it (i.e. the common.Hash of an indexed event parameter
+// parsed into IndexedHash) or, for byte arrays (i.e. [32]byte or
+// common.Hash), decoded bytes.
+func newArraySetter(t reflect.Type) setter {
+	elemSet := setterFor(t.Elem())
+	return func(s *parseState, target reflect.Value, value any, components []Component) error {
+		if b, ok := value.([]byte); ok && t.Elem().Kind() == reflect.Uint8 {
+			return parseByteArray(b, target)
+		}
+		if val := reflect.ValueOf(value); val.Kind() == reflect.Array && val.Type().ConvertibleTo(t) {
+			target.Set(val.Convert(t))
+			return nil
+		}
+
+		decoded, ok := value.([]any)
+		if !ok {
+			return newMismatchError("array for "+t.String(), value)
+		}
+		return parseSlice(s, decoded, components, target, elemSet)
+	}
+}
+
+// structPlanFor returns the cached plan for given struct type, building
+// it when needed.
+func structPlanFor(t reflect.Type) *structPlan {
+	if cached, ok := structPlans.Load(t); ok {
+		return cached.(*structPlan)
+	}
+
+	plan := &structPlan{}
+	plan.addFields(t, nil, map[reflect.Type]bool{t: true})
+
+	cached, _ := structPlans.LoadOrStore(t, plan)
+
+	return cached.(*structPlan)
+}
+
+// addFields adds the plans of the fields of struct type t, whose index
+// path from the planned struct is parent. The fields of untagged
+// embedded structs are promoted, as Solidity tuples are flat. Types
+// being promoted are held by embedding to detect cycles.
+func (p *structPlan) addFields(t reflect.Type, parent []int, embedding map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+		tag, err := ParseFieldTag(field)
+		if err != nil && p.err == nil {
+			p.err = err
+		}
+		if tag.Skip {
+			continue
+		}
+
+		if embedded, ok := promotedStruct(field, tag); ok {
+			if embedding[embedded] {
+				if p.err == nil {
+					p.err = fmt.Errorf("embedded field %s of %s is recursive", field.Name, t)
+				}
+				continue
 			}
-			rve.Set(reflect.Append(rve, newPtr))
-		} else if arrElem.Kind() == reflect.Struct {
-			newStruct := reflect.New(arrElem)
-			err := parseStruct(decoded[i].([]any), newStruct.Interface())
-			if err != nil {
-				return fmt.Errorf("[parseSlice] error parsing struct field %s: %w", rve.Type().Name(), err)
+			embedding[embedded] = true
+			p.addFields(embedded, index, embedding)
+			delete(embedding, embedded)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		set := setterFor(field.Type)
+		if tag.Enum != nil {
+			set, err = newEnumSetter(field.Type, tag.Enum)
+			if err != nil && p.err == nil {
+				p.err = fmt.Errorf("invalid abi tag on field %s: %w", field.Name, err)
 			}
-			rve.Set(reflect.Append(rve, newStruct.Elem()))
-		} else if arrElem.Kind() == reflect.Slice || arrElem.Kind() == reflect.Array {
-			err := parseSlice(decoded[i].([]any), rve.Addr().Interface())
-			if err != nil {
-				return fmt.Errorf("[parseSlice] error parsing slice field %s: %w", rve.Type().Name(), err)
+		}
+		if tag.MapKey != "" {
+			set, err = newMapKeySetter(field.Type, tag.MapKey)
+			if err != nil && p.err == nil {
+				p.err = fmt.Errorf("invalid abi tag on field %s: %w", field.Name, err)
 			}
-		} else {
-			newElem := reflect.ValueOf(decoded[i])
-			rve.Set(reflect.Append(rve, newElem))
 		}
+		if tag.Decimals != -1 {
+			set, err = newDecimalsSetter(field.Type, tag.Decimals)
+			if err != nil && p.err == nil {
+				p.err = fmt.Errorf("invalid abi tag on field %s: %w", field.Name, err)
+			}
+		}
+		if tag.OmitZero {
+			set = newOmitZeroSetter(field.Type, set)
+		}
+		p.fields = append(p.fields, fieldPlan{index: index, name: field.Name, tag: tag, set: set})
+		p.numValues += tag.Span
 	}
+}
 
-	return nil
+// promotedStruct returns the struct type of an embedded field whose
+// fields are promoted, which is the case of untagged embedded structs
+// (or struct pointers) not parsed as a single value.
+func promotedStruct(field reflect.StructField, tag FieldTag) (reflect.Type, bool) {
+	if !field.Anonymous || tag.Name != "" || tag.Index != -1 || tag.Type != "" || tag.Enum != nil || tag.MapKey != "" || tag.Decimals != -1 || tag.OmitZero {
+		return nil, false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		if !field.IsExported() {
+			return nil, false
+		}
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isCoreStruct(t) || isUnmarshaler(field.Type) {
+		return nil, false
+	}
+	if _, ok := lookupDecoder(field.Type); ok {
+		return nil, false
+	}
+
+	return t, true
 }
 
-func parsePointer(decoded []any, pointerVal reflect.Value) error {
-	if pointerVal.Kind() != reflect.Ptr {
-		return fmt.Errorf("[parsePointer] v must be a pointer")
+// fieldByIndex returns the field of the struct value v at given index
+// path, allocating the nil embedded struct pointers along the path.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, fieldIndex := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(fieldIndex)
 	}
 
-	elemType := pointerVal.Type().Elem()
+	return v
+}
 
-	if pointerVal.IsNil() {
-		pointerVal.Set(reflect.New(elemType))
+// parseStruct parses decoded values into a struct
+func parseStruct(s *parseState, decoded []any, components []Component, structVal any) error {
+	rv := reflect.ValueOf(structVal)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("[parseStruct] v must be a pointer")
 	}
 
-	switch elemType.Kind() {
-	case reflect.Struct:
-		err := parseStruct(decoded, pointerVal.Interface())
+	if unmarshaler, ok := structVal.(ABIUnmarshaler); ok {
+		return unmarshaler.UnmarshalABI(decoded)
+	}
+
+	rve := rv.Elem()
+	if rve.Kind() != reflect.Struct {
+		return fmt.Errorf("[parseStruct] v must be a struct pointer")
+	}
+
+	err := parseStructValue(s, decoded, components, rve)
+	if parseErr, ok := err.(*ParseError); ok && rve.Type().Name() != "" {
+		parseErr.Path = rve.Type().Name() + parseErr.Path
+	}
+
+	return err
+}
+
+// parseStructValue parses decoded values into the struct value rve
+// following its cached plan. Field errors are returned as *ParseError
+// holding the path of the field from rve.
+func parseStructValue(s *parseState, decoded []any, components []Component, rve reflect.Value) error {
+	plan := structPlanFor(rve.Type())
+	if len(decoded) != plan.numValues && (!s.opts.AllowExtraValues || len(decoded) < plan.numValues) {
+		return fmt.Errorf("[parseStruct] number of decoded values does not match number of struct fields")
+	}
+
+	if components != nil && len(components) != len(decoded) {
+		return fmt.Errorf("[parseStruct] number of decoded values does not match number of components")
+	}
+
+	err := s.enter(len(decoded))
+	if err != nil {
+		return fmt.Errorf("[parseStruct] %w", err)
+	}
+	defer s.leave()
+
+	positions, err := plan.positions(len(decoded), components, s.opts.Naming)
+	if err != nil {
+		return fmt.Errorf("[parseStruct] %w", err)
+	}
+
+	for i := range plan.fields {
+		field := &plan.fields[i]
+		value, fieldComponents, component := field.values(decoded, components, positions[i])
+
+		err := field.set(s, fieldByIndex(rve, field.index), value, fieldComponents)
 		if err != nil {
-			return fmt.Errorf("[parsePointer] error parsing struct field %s: %w", elemType.Name(), err)
+			return prependPath(err, "."+field.name, component)
 		}
-	case reflect.Slice, reflect.Array:
-		err := parseSlice(decoded, pointerVal.Addr().Interface())
-		if err != nil {
-			return fmt.Errorf("[parsePointer] error parsing slice field %s: %w", elemType.Name(), err)
+	}
+
+	return nil
+}
+
+// parseSlice parses decoded values into a slice or fixed-size array,
+// setting one element per decoded value. Components describe the
+// members of tuple elements. Fixed-size arrays must have as many
+// elements as decoded values.
+func parseSlice(s *parseState, decoded []any, components []Component, sliceVal reflect.Value, elemSet setter) error {
+	var result reflect.Value
+	switch sliceVal.Kind() {
+	case reflect.Slice:
+		result = reflect.MakeSlice(sliceVal.Type(), len(decoded), len(decoded))
+	case reflect.Array:
+		if sliceVal.Len() != len(decoded) {
+			return fmt.Errorf("[parseSlice] number of decoded values (%d) does not match array length %d", len(decoded), sliceVal.Len())
 		}
+		result = reflect.New(sliceVal.Type()).Elem()
 	default:
-		pointerVal.Elem().Set(reflect.ValueOf(decoded))
+		return fmt.Errorf("[parseSlice] v must be a slice or an array")
+	}
+
+	err := s.enter(len(decoded))
+	if err != nil {
+		return fmt.Errorf("[parseSlice] %w", err)
+	}
+	defer s.leave()
+
+	for i := range decoded {
+		err := s.checkContext()
+		if err == nil {
+			err = elemSet(s, result.Index(i), decoded[i], components)
+		}
+		if err != nil {
+			return prependPath(err, fmt.Sprintf("[%d]", i), nil)
+		}
+		s.elementParsed()
+	}
+	sliceVal.Set(result)
+
+	return nil
+}
+
+// parseByteArray copies decoded bytes into a byte array. Fixed bytes
+// are decoded right-padded to 32 bytes, so longer inputs are accepted
+// as long as the bytes exceeding the array length are zero.
+func parseByteArray(b []byte, arrayVal reflect.Value) error {
+	length := arrayVal.Len()
+	if len(b) < length {
+		return fmt.Errorf("expected %d bytes for %s, got %d", length, arrayVal.Type(), len(b))
+	}
+	for _, extra := range b[length:] {
+		if extra != 0 {
+			return fmt.Errorf("%d bytes do not fit in %s", len(b), arrayVal.Type())
+		}
 	}
+	reflect.Copy(arrayVal, reflect.ValueOf(b[:length]))
 
 	return nil
 }
+
+// parsePointer parses a decoded value into the value pointed by
+// pointerVal, allocating it when nil.
+func parsePointer(s *parseState, decoded any, components []Component, pointerVal reflect.Value, elemSet setter) error {
+	if pointerVal.Kind() != reflect.Ptr {
+		return fmt.Errorf("[parsePointer] v must be a pointer")
+	}
+
+	if pointerVal.IsNil() {
+		pointerVal.Set(reflect.New(pointerVal.Type().Elem()))
+	}
+
+	return elemSet(s, pointerVal.Elem(), decoded, components)
+}
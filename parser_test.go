@@ -0,0 +1,788 @@
+package abi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"github.com/omnes-tech/abi"
+)
+
+type exampleItem struct {
+	Owner  common.Address
+	Amount *big.Int
+	Data   []byte
+}
+
+type exampleOrder struct {
+	Maker   common.Address
+	Amounts []*big.Int
+	Items   []exampleItem
+	Active  bool
+	Comment string
+}
+
+func ExampleParse() {
+	maker := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	typeStrs := []string{"address", "uint256[]", "(address,uint256,bytes)[]", "bool", "string"}
+
+	encoded, err := abi.Encode(
+		typeStrs,
+		&maker,
+		[]any{big.NewInt(100), big.NewInt(352)},
+		[]any{[]any{&maker, big.NewInt(7), []byte("item")}},
+		true,
+		"first order",
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var order exampleOrder
+	err = abi.Parse(decoded, &order)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(order.Maker, order.Amounts, order.Items[0].Amount, string(order.Items[0].Data), order.Active, order.Comment)
+
+	// Output: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 [100 352] 7 item true first order
+}
+
+func ExampleParse_structPointers() {
+	maker := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	typeStrs := []string{"(address,uint256,bytes)[]", "(address,uint256,bytes)[2]"}
+
+	encoded, err := abi.Encode(
+		typeStrs,
+		[]any{[]any{&maker, big.NewInt(7), []byte("first")}, []any{&maker, big.NewInt(9), []byte("second")}},
+		[]any{[]any{&maker, big.NewInt(1), []byte{}}, []any{&maker, big.NewInt(2), []byte{}}},
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Each element is allocated, and can be shared without copying it.
+	var result struct {
+		Items []*exampleItem
+		Pair  [2]*exampleItem
+	}
+	err = abi.Parse(decoded, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	byAmount := make(map[int64]*exampleItem)
+	for _, item := range result.Items {
+		byAmount[item.Amount.Int64()] = item
+	}
+	fmt.Println(string(byAmount[9].Data), byAmount[9] == result.Items[1], result.Pair[1].Amount)
+
+	// Output: second true 2
+}
+
+func ExampleParse_roundTrip() {
+	maker := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	expected := exampleOrder{
+		Maker:   maker,
+		Amounts: []*big.Int{big.NewInt(1), big.NewInt(2)},
+		Items: []exampleItem{
+			{Owner: maker, Amount: big.NewInt(3), Data: []byte{0x01, 0x02}},
+			{Owner: common.Address{}, Amount: big.NewInt(0), Data: []byte{}},
+		},
+		Active:  false,
+		Comment: "round trip",
+	}
+
+	typeStrs := []string{"address", "uint256[]", "(address,uint256,bytes)[]", "bool", "string"}
+	encoded, err := abi.Encode(
+		typeStrs,
+		&expected.Maker,
+		[]any{expected.Amounts[0], expected.Amounts[1]},
+		[]any{
+			[]any{&expected.Items[0].Owner, expected.Items[0].Amount, expected.Items[0].Data},
+			[]any{&expected.Items[1].Owner, expected.Items[1].Amount, expected.Items[1].Data},
+		},
+		expected.Active,
+		expected.Comment,
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var parsed exampleOrder
+	err = abi.Parse(decoded, &parsed)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(abi.DeepEqual(parsed, expected), abi.DeepEqual(decoded, expected))
+
+	// Output: true true
+}
+
+func ExampleParse_taggedIndex() {
+	decoded := []any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", big.NewInt(500), true}
+
+	var transfer struct {
+		Amount    *big.Int       `abi:"index=1"`
+		Recipient common.Address `abi:"index=0"`
+		Success   bool           `abi:"index=2"`
+	}
+	err := abi.Parse(decoded, &transfer)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(transfer.Recipient, transfer.Amount, transfer.Success)
+
+	// Output: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 500 true
+}
+
+func ExampleParseWithComponents() {
+	components := []abi.Component{
+		{Name: "to", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+		{Name: "meta", Type: "tuple", Components: []abi.Component{
+			{Name: "memo", Type: "string"},
+			{Name: "nonce", Type: "uint64"},
+		}},
+	}
+	decoded := []any{
+		"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789",
+		big.NewInt(500),
+		[]any{"rent", big.NewInt(3)},
+	}
+
+	var transfer struct {
+		Meta struct {
+			Nonce *big.Int `abi:"nonce"`
+			Memo  string   `abi:"memo"`
+		} `abi:"meta"`
+		Amount *big.Int       `abi:"amount"`
+		To     common.Address `abi:"to"`
+	}
+	err := abi.ParseWithComponents(decoded, components, &transfer)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(transfer.To, transfer.Amount, transfer.Meta.Memo, transfer.Meta.Nonce)
+
+	var invalid struct {
+		To     common.Address `abi:"to"`
+		Amount *big.Int       `abi:"value"`
+		Meta   []any          `abi:"meta"`
+	}
+	err = abi.ParseWithComponents(decoded, components, &invalid)
+	fmt.Println(err)
+
+	// Output:
+	// 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 500 rent 3
+	// [parseStruct] field Amount tagged with name "value" has no corresponding decoded value
+}
+
+func ExampleParseAs() {
+	decoded := []any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", big.NewInt(500)}
+
+	type transfer struct {
+		To     common.Address
+		Amount *big.Int
+	}
+
+	value, err := abi.ParseAs[transfer](decoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	pointer, err := abi.ParseAs[*transfer](decoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	invalid, err := abi.ParseAs[*transfer](decoded[:1])
+	fmt.Println(value.To, value.Amount, pointer.Amount, invalid == nil, err)
+
+	// Output: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 500 500 true [parseStruct] number of decoded values does not match number of struct fields
+}
+
+func ExampleParseWithOptions() {
+	decoded := []any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", nil, uint64(300), "ignored", true}
+
+	var partial struct {
+		Owner common.Address
+		Label *string
+		Fee   uint8
+	}
+
+	err := abi.Parse(decoded, &partial)
+	fmt.Println(err)
+
+	err = abi.ParseWithOptions(decoded, &partial, abi.Options{AllowExtraValues: true, AllowNil: true})
+	fmt.Println(err)
+
+	err = abi.ParseWithOptions(decoded, &partial, abi.Options{AllowExtraValues: true, AllowNil: true, AllowNarrowing: true})
+	fmt.Println(err, partial.Owner, partial.Label, partial.Fee)
+
+	// Output:
+	// [parseStruct] number of decoded values does not match number of struct fields
+	// [parseStruct] error parsing field Fee (expected uint8, got uint64): integer overflow: value 300 of type uint64 does not fit in uint8 (range 0 to 255)
+	// <nil> 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 <nil> 44
+}
+
+func ExampleParseWithOptions_strictAddresses() {
+	var transfer struct {
+		To     common.Address
+		Amount *big.Int
+	}
+
+	// Without StrictAddresses, a truncated address is silently padded.
+	err := abi.Parse([]any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d27", big.NewInt(1)}, &transfer)
+	fmt.Println(transfer.To, err)
+
+	strict := abi.Options{StrictAddresses: true}
+	for _, to := range []string{
+		"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d27",
+		"0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789",
+		"0x5FF137D4b0FDCD49DcA30c7CF57E578a026D2789",
+	} {
+		err = abi.ParseWithOptions([]any{to, big.NewInt(1)}, &transfer, strict)
+		fmt.Println(errors.Is(err, abi.ErrInvalidAddress), err)
+	}
+
+	// Output:
+	// 0x005ff137D4b0FDCd49dCa30C7Cf57E578a026d27 <nil>
+	// true [parseStruct] error parsing field To (expected common.Address, got string): invalid address "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d27": expected 40 hex digits
+	// false <nil>
+	// true [parseStruct] error parsing field To (expected common.Address, got string): invalid address "0x5FF137D4b0FDCD49DcA30c7CF57E578a026D2789": bad EIP-55 checksum
+}
+
+func ExampleParseWithOptionsCtx() {
+	amounts := make([]any, 1000)
+	for i := range amounts {
+		amounts[i] = big.NewInt(int64(i))
+	}
+	decoded := []any{amounts}
+
+	var result struct {
+		Amounts []*big.Int
+	}
+
+	// Cancel parsing once 3 elements were parsed, i.e. when a deadline
+	// expires or a client disconnects.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opts := abi.Options{
+		Progress: func(parsed int) {
+			if parsed == 3 {
+				cancel()
+			}
+		},
+	}
+
+	err := abi.ParseWithOptionsCtx(ctx, decoded, &result, opts)
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, context.Canceled))
+
+	// Output:
+	// [parseStruct] error parsing field Amounts[3]: context canceled
+	// true
+}
+
+func ExampleParse_fixedArrays() {
+	type signers struct {
+		Owners [3]common.Address
+		Root   common.Hash
+		Tag    [4]byte
+		Names  [2]string
+	}
+
+	owners := [3]common.Address{
+		common.HexToAddress("0x01"),
+		common.HexToAddress("0x02"),
+		common.HexToAddress("0x03"),
+	}
+	root := common.HexToHash("0xabcd")
+
+	typeStrs := []string{"address[3]", "bytes32", "bytes4", "string[2]"}
+	encoded, err := abi.Encode(
+		typeStrs,
+		[]any{&owners[0], &owners[1], &owners[2]},
+		root.Bytes(),
+		[]byte{0xde, 0xad, 0xbe, 0xef},
+		[]any{"alice", "bob"},
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var result signers
+	err = abi.Parse(decoded, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(result.Owners[2].Hex())
+	fmt.Println(result.Root == root, common.Bytes2Hex(result.Tag[:]), result.Names)
+
+	// Output:
+	// 0x0000000000000000000000000000000000000003
+	// true deadbeef [alice bob]
+}
+
+func ExampleParseError() {
+	components := []abi.Component{
+		{Name: "maker", Type: "address"},
+		{Name: "items", Type: "tuple[]", Components: []abi.Component{
+			{Name: "owner", Type: "address"},
+			{Name: "amount", Type: "uint256"},
+			{Name: "data", Type: "bytes"},
+		}},
+	}
+	decoded := []any{
+		"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789",
+		[]any{
+			[]any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", big.NewInt(1), []byte{}},
+			[]any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", "2", []byte{}},
+		},
+	}
+
+	type order struct {
+		Maker common.Address
+		Items []exampleItem
+	}
+
+	var result order
+	err := abi.ParseWithComponents(decoded, components, &result)
+	fmt.Println(err)
+
+	var parseErr *abi.ParseError
+	if errors.As(err, &parseErr) {
+		fmt.Println(parseErr.Path, parseErr.Type, parseErr.ValueType, parseErr.ABIType)
+	}
+
+	// Output:
+	// [parseStruct] error parsing field order.Items[1].Amount (expected *big.Int, got string, abi type uint256)
+	// order.Items[1].Amount *big.Int string uint256
+}
+
+func ExampleParse_uint256() {
+	type balance struct {
+		Amount  *uint256.Int
+		Reserve uint256.Int
+	}
+
+	encoded, err := abi.Marshal(balance{Amount: uint256.NewInt(1000), Reserve: *uint256.NewInt(42)}, "(uint256,uint128)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode([]string{"uint256", "uint128"}, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var result balance
+	err = abi.Parse(decoded, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(result.Amount, &result.Reserve)
+
+	// Output: 1000 42
+}
+
+type exampleBaseOrder struct {
+	Maker  common.Address
+	Amount *big.Int
+}
+
+type exampleLimitOrder struct {
+	exampleBaseOrder
+	Price  *big.Int
+	Expiry uint64
+}
+
+func ExampleParse_embedded() {
+	maker := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+	// The fields of embedded structs are promoted, matching the flat
+	// `(address,uint256,uint256,uint64)` tuple.
+	encoded, err := abi.Marshal(exampleLimitOrder{
+		exampleBaseOrder: exampleBaseOrder{Maker: maker, Amount: big.NewInt(100)},
+		Price:            big.NewInt(25),
+		Expiry:           1700000000,
+	}, "(address,uint256,uint256,uint64)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode([]string{"address", "uint256", "uint256", "uint64"}, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var order exampleLimitOrder
+	err = abi.Parse(decoded, &order)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(order.Maker, order.Amount, order.Price, order.Expiry)
+
+	// Output: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 100 25 1700000000
+}
+
+// exampleTokenAmount collapses a raw amount and its decimals into a
+// decimal string.
+type exampleTokenAmount string
+
+func (a *exampleTokenAmount) UnmarshalABI(v any) error {
+	values, ok := v.([]any)
+	if !ok || len(values) != 2 {
+		return fmt.Errorf("expected amount and decimals, got %v", v)
+	}
+	amount, okAmount := values[0].(*big.Int)
+	decimals, okDecimals := values[1].(*big.Int)
+	if !okAmount || !okDecimals {
+		return fmt.Errorf("expected amount and decimals, got %v", v)
+	}
+
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), decimals, nil))
+	*a = exampleTokenAmount(new(big.Float).Quo(new(big.Float).SetInt(amount), scale).Text('f', -1))
+	return nil
+}
+
+type exampleBalanceRow struct {
+	ID        int64 `abi:"-"`
+	Owner     common.Address
+	Balance   exampleTokenAmount `abi:",span=2"`
+	UpdatedAt string             `abi:"-"`
+}
+
+func ExampleParse_unexported() {
+	type audit struct {
+		Checked bool
+	}
+	type transfer struct {
+		To     common.Address
+		note   string
+		Amount *big.Int
+		audit
+	}
+
+	decoded := []any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", big.NewInt(1000), true}
+
+	var t transfer
+	err := abi.Parse(decoded, &t)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(t.To, t.Amount, t.note == "", t.Checked)
+
+	// Output: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1000 true true
+}
+
+func ExampleParse_skipAndSpan() {
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	typeStrs := []string{"address", "uint256", "uint8"}
+	encoded, err := abi.Encode(typeStrs, &owner, big.NewInt(1500000), big.NewInt(6))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// ID and UpdatedAt are skipped, and Balance receives both the amount
+	// and its decimals.
+	row := exampleBalanceRow{ID: 42}
+	err = abi.Parse(decoded, &row)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(row.ID, row.Owner, row.Balance)
+
+	// Output: 42 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1.5
+}
+
+type exampleCommitment struct {
+	Hash   common.Hash
+	Raw    [32]byte
+	Hex    string
+	Number *big.Int
+}
+
+func ExampleParse_bytes32() {
+	root := common.HexToHash("0x00000000000000000000000000000000000000000000000000000000000004d2")
+
+	// Hex strings and *big.Int values are also accepted for bytes32.
+	encoded, err := abi.Marshal(exampleCommitment{
+		Hash:   root,
+		Raw:    root,
+		Hex:    root.Hex(),
+		Number: big.NewInt(1234),
+	}, "(bytes32,bytes32,bytes32,bytes32)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode([]string{"bytes32", "bytes32", "bytes32", "bytes32"}, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var commitment exampleCommitment
+	err = abi.Parse(decoded, &commitment)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(commitment.Hash == root, commitment.Raw == root)
+	fmt.Println(commitment.Hex)
+	fmt.Println(commitment.Number)
+
+	// Output:
+	// true true
+	// 0x00000000000000000000000000000000000000000000000000000000000004d2
+	// 1234
+}
+
+type exampleLock struct {
+	Owner  common.Address `abi:"owner"`
+	Amount *big.Int       `abi:"balance"`
+}
+
+func ExampleParse_mapKey() {
+	alice := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	bob := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	fragment := abi.MustParseFragment("function balances() returns ((address owner, uint256 balance)[] balances, (address owner, uint256 balance)[] locked)")
+
+	encoded, err := abi.Encode(fragment.OutputTypes(),
+		[]any{[]any{&alice, big.NewInt(100)}, []any{&bob, big.NewInt(250)}},
+		[]any{[]any{&bob, big.NewInt(50)}},
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(fragment.OutputTypes(), encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Tuples are keyed by their owner member, resolved from the outputs.
+	var result struct {
+		Balances map[common.Address]*big.Int    `abi:"balances,mapkey=owner"`
+		Locked   map[common.Address]exampleLock `abi:"locked,mapkey=owner"`
+	}
+	err = abi.ParseWithComponents(decoded, fragment.Outputs, &result)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(result.Balances[alice], result.Balances[bob], result.Locked[bob].Amount)
+
+	// Without components, the key is given by its position.
+	var positional struct {
+		Balances map[common.Address]*big.Int `abi:",mapkey=0"`
+		Locked   map[common.Address]*big.Int `abi:",mapkey=0"`
+	}
+	err = abi.Parse(decoded, &positional)
+	fmt.Println(len(positional.Balances), positional.Locked[bob], err)
+
+	// Output:
+	// 100 250 50
+	// 2 50 <nil>
+}
+
+func ExampleParse_overflow() {
+	typeStrs := []string{"uint256", "int64"}
+	encoded, err := abi.Encode(typeStrs, big.NewInt(1<<40), big.NewInt(-1<<40))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var narrow struct {
+		Amount uint32
+		Delta  int16
+	}
+	err = abi.Parse(decoded, &narrow)
+	fmt.Println(errors.Is(err, abi.ErrOverflow))
+	fmt.Println(err)
+
+	var wide struct {
+		Amount uint64
+		Delta  int64
+	}
+	err = abi.Parse(decoded, &wide)
+	fmt.Println(wide.Amount, wide.Delta, err)
+
+	// Output:
+	// true
+	// [parseStruct] error parsing field Amount (expected uint32, got *big.Int): integer overflow: value 1099511627776 of type *big.Int does not fit in uint32 (range 0 to 4294967295)
+	// 1099511627776 -1099511627776 <nil>
+}
+
+type exampleReferral struct {
+	Referrer common.Address `json:"referrer"`
+	Share    uint16         `json:"share"`
+}
+
+type exampleRegistration struct {
+	Owner    common.Address   `json:"owner"`
+	Fee      *big.Int         `abi:",omitzero" json:"fee,omitempty"`
+	Referral *exampleReferral `abi:",omitzero" json:"referral,omitempty"`
+}
+
+func ExampleParse_omitZero() {
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	referrer := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	typeStrs := []string{"address", "uint256", "(address,uint16)"}
+
+	for _, values := range [][]any{
+		{&owner, big.NewInt(0), []any{&common.Address{}, big.NewInt(0)}},
+		{&owner, big.NewInt(1000), []any{&referrer, big.NewInt(250)}},
+	} {
+		encoded, err := abi.Encode(typeStrs, values...)
+		if err != nil {
+			fmt.Println(err)
+		}
+		decoded, err := abi.Decode(typeStrs, encoded)
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		// Zero fees and referrals are left nil, so they are omitted in
+		// JSON instead of being written as 0 and the zero referral.
+		var registration exampleRegistration
+		err = abi.Parse(decoded, &registration)
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		b, err := json.Marshal(registration)
+		if err != nil {
+			fmt.Println(err)
+		}
+		fmt.Println(string(b))
+	}
+
+	// Output:
+	// {"owner":"0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789"}
+	// {"owner":"0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789","fee":1000,"referral":{"referrer":"0x000000000000000000000000000000000000dead","share":250}}
+}
+
+// exampleTransferRow is a row of a database table, whose fields are
+// declared in another order than the event parameters.
+type exampleTransferRow struct {
+	TokenID *big.Int
+	From    common.Address
+	To      common.Address
+}
+
+func ExampleParseWithOptions_naming() {
+	event := abi.MustParseFragment("event Transfer(address indexed _from, address indexed _to, uint256 indexed token_id)")
+	decoded := []any{"0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789", "0x000000000000000000000000000000000000dEaD", big.NewInt(42)}
+
+	var row exampleTransferRow
+	err := abi.ParseWithOptions(decoded, &row, abi.Options{Components: event.Inputs, Naming: abi.FoldSnakeCase})
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(row.TokenID, row.From, row.To)
+
+	// Output: 42 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 0x000000000000000000000000000000000000dEaD
+}
+
+// benchmarkParse decodes given values and parses them into a fresh T on
+// each iteration.
+func benchmarkParse[T any](b *testing.B, typeStrs []string, values ...any) {
+	encoded, err := abi.Encode(typeStrs, values...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var target T
+		if err := abi.Parse(decoded, &target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	item := []any{&owner, big.NewInt(7), []byte("item")}
+
+	b.Run("struct", func(b *testing.B) {
+		benchmarkParse[exampleItem](b, []string{"address", "uint256", "bytes"}, item...)
+	})
+
+	b.Run("nested", func(b *testing.B) {
+		type nested struct {
+			Item  exampleItem
+			Inner struct {
+				Item  exampleItem
+				Count *big.Int
+			}
+		}
+		benchmarkParse[nested](b,
+			[]string{"(address,uint256,bytes)", "((address,uint256,bytes),uint256)"},
+			item,
+			[]any{item, big.NewInt(2)},
+		)
+	})
+
+	b.Run("sliceOfStruct", func(b *testing.B) {
+		items := make([]any, 64)
+		for i := range items {
+			items[i] = item
+		}
+		benchmarkParse[exampleOrder](b,
+			[]string{"address", "uint256[]", "(address,uint256,bytes)[]", "bool", "string"},
+			&owner,
+			[]any{big.NewInt(100), big.NewInt(352)},
+			items,
+			true,
+			"benchmark order",
+		)
+	})
+}
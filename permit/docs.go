@@ -0,0 +1,6 @@
+// Package permit builds the EIP-712 messages of gasless token approvals:
+// EIP-2612 `permit` of ERC-20 tokens (Permit) and Uniswap Permit2
+// (PermitSingle, PermitBatch). Each message computes the digest signed by
+// the token owner and encodes the `permit` call data submitted with the
+// signature, usually by a relayer.
+package permit
@@ -0,0 +1,100 @@
+package permit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/omnes-tech/abi"
+	"github.com/omnes-tech/abi/eip712"
+)
+
+const permitSignature = "permit(address,address,uint256,uint256,uint8,bytes32,bytes32)"
+
+// Permit is the EIP-2612 message allowing spender to transfer value
+// tokens of owner until deadline (a unix timestamp in seconds). Nonce is
+// the current `nonces(owner)` of the token.
+type Permit struct {
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// permitArgs holds the arguments of `permit` of EIP-2612 tokens.
+type permitArgs struct {
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Deadline *big.Int
+	V        uint8
+	R        [32]byte
+	S        [32]byte
+}
+
+// TokenDomain returns the EIP-712 domain of an EIP-2612 token, whose name
+// and version are the ones of its `eip712Domain()` (or the token name
+// and "1" for most OpenZeppelin tokens).
+func TokenDomain(name, version string, chainID *big.Int, token common.Address) eip712.Domain {
+	return eip712.Domain{
+		Name:              name,
+		Version:           version,
+		ChainID:           chainID,
+		VerifyingContract: &token,
+	}
+}
+
+// Digest computes the EIP-712 digest of the permit to be signed by the
+// owner, for the domain of the token (see TokenDomain).
+func (p *Permit) Digest(domain eip712.Domain) (common.Hash, error) {
+	return eip712.Digest(domain, p)
+}
+
+// EncodeCall encodes the call data of `permit` of the token with the
+// 65 bytes [R || S || V] signature of the permit digest, as returned by
+// crypto.Sign (V being 0 or 1) or by wallets (V being 27 or 28).
+func (p *Permit) EncodeCall(signature []byte) ([]byte, error) {
+	signature, err := normalizeSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	args := permitArgs{
+		Owner:    p.Owner,
+		Spender:  p.Spender,
+		Value:    p.Value,
+		Deadline: p.Deadline,
+		V:        signature[crypto.RecoveryIDOffset],
+		R:        [32]byte(signature[:32]),
+		S:        [32]byte(signature[32:64]),
+	}
+	encoded, err := abi.Marshal(args, permitSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(abi.EncodeSignature(permitSignature), encoded...), nil
+}
+
+// normalizeSignature checks that given signature holds 65 bytes and
+// returns it with V set to 27 or 28, as expected by ecrecover.
+func normalizeSignature(signature []byte) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length: expected %d bytes, got %d", crypto.SignatureLength, len(signature))
+	}
+
+	v := signature[crypto.RecoveryIDOffset]
+	if v >= 27 {
+		return signature, nil
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("invalid signature recovery id: %d", v)
+	}
+
+	normalized := append([]byte{}, signature...)
+	normalized[crypto.RecoveryIDOffset] += 27
+
+	return normalized, nil
+}
@@ -0,0 +1,117 @@
+package permit
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+	"github.com/omnes-tech/abi/eip712"
+)
+
+// Permit2Address is the address of the Uniswap Permit2 contract, deployed
+// at the same address on most EVM chains.
+var Permit2Address = common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+const (
+	permitDetailsType = "(address,uint160,uint48,uint48)"
+
+	permitSingleSignature = "permit(address,(" + permitDetailsType + ",address,uint256),bytes)"
+	permitBatchSignature  = "permit(address,(" + permitDetailsType + "[],address,uint256),bytes)"
+)
+
+// PermitDetails is the allowance of a token granted by a Permit2
+// message: amount tokens until expiration (a unix timestamp in seconds).
+// Nonce is the current nonce of the owner, token and spender allowance,
+// read from `allowance(owner, token, spender)` of Permit2.
+type PermitDetails struct {
+	Token      common.Address
+	Amount     *big.Int `abi:",type=uint160"`
+	Expiration uint64   `abi:",type=uint48"`
+	Nonce      uint64   `abi:",type=uint48"`
+}
+
+// PermitSingle is the Permit2 message granting an allowance of a single
+// token to spender, valid until sigDeadline.
+type PermitSingle struct {
+	Details     PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// PermitBatch is the Permit2 message granting allowances of several
+// tokens to spender, valid until sigDeadline.
+type PermitBatch struct {
+	Details     []PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// permitSingleArgs holds the arguments of `permit` of Permit2 for a
+// single token.
+type permitSingleArgs struct {
+	Owner     common.Address
+	Permit    PermitSingle
+	Signature []byte
+}
+
+// permitBatchArgs holds the arguments of `permit` of Permit2 for several
+// tokens.
+type permitBatchArgs struct {
+	Owner     common.Address
+	Permit    PermitBatch
+	Signature []byte
+}
+
+// Permit2Domain returns the EIP-712 domain of the Permit2 contract on
+// given chain.
+func Permit2Domain(chainID *big.Int) eip712.Domain {
+	return eip712.Domain{
+		Name:              "Permit2",
+		ChainID:           chainID,
+		VerifyingContract: &Permit2Address,
+	}
+}
+
+// Digest computes the EIP-712 digest of the permit to be signed by the
+// owner, for the domain of Permit2 (see Permit2Domain).
+func (p *PermitSingle) Digest(domain eip712.Domain) (common.Hash, error) {
+	return eip712.Digest(domain, p)
+}
+
+// EncodeCall encodes the call data of `permit` of Permit2 with the owner
+// and the 65 bytes signature of the permit digest.
+func (p *PermitSingle) EncodeCall(owner common.Address, signature []byte) ([]byte, error) {
+	signature, err := normalizeSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := abi.Marshal(permitSingleArgs{Owner: owner, Permit: *p, Signature: signature}, permitSingleSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(abi.EncodeSignature(permitSingleSignature), encoded...), nil
+}
+
+// Digest computes the EIP-712 digest of the permit to be signed by the
+// owner, for the domain of Permit2 (see Permit2Domain).
+func (p *PermitBatch) Digest(domain eip712.Domain) (common.Hash, error) {
+	return eip712.Digest(domain, p)
+}
+
+// EncodeCall encodes the call data of `permit` of Permit2 with the owner
+// and the 65 bytes signature of the permit digest.
+func (p *PermitBatch) EncodeCall(owner common.Address, signature []byte) ([]byte, error) {
+	signature, err := normalizeSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := abi.Marshal(permitBatchArgs{Owner: owner, Permit: *p, Signature: signature}, permitBatchSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(abi.EncodeSignature(permitBatchSignature), encoded...), nil
+}
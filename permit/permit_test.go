@@ -0,0 +1,113 @@
+package permit_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/omnes-tech/abi/permit"
+)
+
+func ExamplePermit() {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	p := permit.Permit{
+		Owner:    crypto.PubkeyToAddress(key.PublicKey),
+		Spender:  common.HexToAddress("0x000000000000000000000000000000000000dEaD"),
+		Value:    big.NewInt(1000),
+		Nonce:    big.NewInt(0),
+		Deadline: big.NewInt(1700000000),
+	}
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	digest, err := p.Digest(permit.TokenDomain("USD Coin", "2", big.NewInt(1), usdc))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	signature, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	calldata, err := p.EncodeCall(signature)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(digest)
+	fmt.Printf("%x\n", calldata[:4])
+	fmt.Println(len(calldata))
+
+	// Output:
+	// 0xdd3139493eeb95d46b29c4f519ec16b8cd90c49ba2d4152a636abaa5ee05cf93
+	// d505accf
+	// 228
+}
+
+func ExamplePermitSingle() {
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	p := permit.PermitSingle{
+		Details: permit.PermitDetails{
+			Token:      common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+			Amount:     big.NewInt(1000),
+			Expiration: 1700000000,
+			Nonce:      3,
+		},
+		Spender:     common.HexToAddress("0x000000000000000000000000000000000000dEaD"),
+		SigDeadline: big.NewInt(1700000000),
+	}
+
+	digest, err := p.Digest(permit.Permit2Domain(big.NewInt(1)))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	signature, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	calldata, err := p.EncodeCall(crypto.PubkeyToAddress(key.PublicKey), signature)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(digest)
+	fmt.Printf("%x\n", calldata[:4])
+
+	// Output:
+	// 0x10c40ee17976fe45b224fdfcb1ca321dc471fba1a6c48dc1d7703ade37e9d716
+	// 2b67b570
+}
+
+func ExamplePermitBatch() {
+	details := permit.PermitDetails{
+		Token:      common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		Amount:     big.NewInt(1000),
+		Expiration: 1700000000,
+		Nonce:      3,
+	}
+	p := permit.PermitBatch{
+		Details:     []permit.PermitDetails{details, details},
+		Spender:     common.HexToAddress("0x000000000000000000000000000000000000dEaD"),
+		SigDeadline: big.NewInt(1700000000),
+	}
+
+	digest, err := p.Digest(permit.Permit2Domain(big.NewInt(1)))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(digest)
+
+	// Output: 0x730d03b85d41c6d19237108e3075b69967691a41f04c4d3138afccad1c850e89
+}
@@ -0,0 +1,164 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSelectorCollision is returned when registering a signature whose
+// selector is already used by a different signature.
+var ErrSelectorCollision = errors.New("selector collision")
+
+// Selector returns the 4-byte selector of given function signature,
+// either human-readable or canonical. Signatures are normalized before
+// hashing (whitespace, parameter names and modifiers are dropped, type
+// aliases such as uint are expanded and tuple(...) becomes (...)), so
+// `function transfer(address to, uint amount)` and
+// `transfer(address,uint256)` have the same selector. Signatures that
+// cannot be parsed are hashed as given.
+func Selector(signature string) [4]byte {
+	if fragment, err := ParseFragment(signature); err == nil {
+		signature = fragment.Signature()
+	}
+
+	return [4]byte(EncodeSignature(signature))
+}
+
+// Registry stores function fragments keyed by their 4-byte selector,
+// detecting selector collisions between different signatures. It is
+// safe for concurrent use. A nil registry holds no functions and can be
+// queried, i.e. by DecodeCalldata.
+type Registry struct {
+	mu        sync.RWMutex
+	fragments map[[4]byte]*Fragment
+}
+
+// NewRegistry creates a registry holding given function signatures,
+// either human-readable (i.e. `function transfer(address to, uint256 amount)`)
+// or canonical (i.e. `transfer(address,uint256)`).
+func NewRegistry(signatures ...string) (*Registry, error) {
+	registry := &Registry{fragments: make(map[[4]byte]*Fragment)}
+	err := registry.Register(signatures...)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Register adds given function signatures to the registry.
+func (r *Registry) Register(signatures ...string) error {
+	for _, signature := range signatures {
+		fragment, err := ParseFragment(signature)
+		if err != nil {
+			return err
+		}
+
+		err = r.RegisterFragment(fragment)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterFragment adds given function fragment to the registry.
+// Registering a signature again is a no-op, while registering a
+// signature whose selector is used by another one fails with
+// ErrSelectorCollision.
+func (r *Registry) RegisterFragment(fragment *Fragment) error {
+	if fragment.Type != "function" {
+		return fmt.Errorf("registry only holds functions, got %s %s", fragment.Type, fragment.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fragments == nil {
+		r.fragments = make(map[[4]byte]*Fragment)
+	}
+	selector := [4]byte(fragment.Selector())
+	if existing, ok := r.fragments[selector]; ok {
+		if existing.Signature() == fragment.Signature() {
+			return nil
+		}
+		return fmt.Errorf("%w: 0x%s is the selector of both %s and %s", ErrSelectorCollision, common.Bytes2Hex(selector[:]), existing.Signature(), fragment.Signature())
+	}
+	r.fragments[selector] = fragment
+
+	return nil
+}
+
+// Lookup returns the function fragment registered for given selector.
+func (r *Registry) Lookup(selector [4]byte) (*Fragment, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fragment, ok := r.fragments[selector]
+	return fragment, ok
+}
+
+// Signature returns the canonical signature registered for given
+// selector.
+func (r *Registry) Signature(selector [4]byte) (string, bool) {
+	fragment, ok := r.Lookup(selector)
+	if !ok {
+		return "", false
+	}
+
+	return fragment.Signature(), true
+}
+
+// Signatures returns the canonical signatures held by the registry,
+// sorted alphabetically.
+func (r *Registry) Signatures() []string {
+	if r == nil {
+		return []string{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	signatures := make([]string, 0, len(r.fragments))
+	for _, fragment := range r.fragments {
+		signatures = append(signatures, fragment.Signature())
+	}
+	sort.Strings(signatures)
+
+	return signatures
+}
+
+// Merge adds the functions of other registries to the registry, i.e.
+// those of the facets behind a diamond proxy. Selectors used by
+// different signatures are all reported in the returned error, wrapping
+// ErrSelectorCollision, and the other functions are still added.
+func (r *Registry) Merge(others ...*Registry) error {
+	var collisions []error
+	for _, other := range others {
+		other.mu.RLock()
+		fragments := make([]*Fragment, 0, len(other.fragments))
+		for _, fragment := range other.fragments {
+			fragments = append(fragments, fragment)
+		}
+		other.mu.RUnlock()
+
+		sort.Slice(fragments, func(i, j int) bool {
+			return fragments[i].Signature() < fragments[j].Signature()
+		})
+		for _, fragment := range fragments {
+			err := r.RegisterFragment(fragment)
+			if err != nil {
+				collisions = append(collisions, err)
+			}
+		}
+	}
+
+	return errors.Join(collisions...)
+}
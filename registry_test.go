@@ -0,0 +1,57 @@
+package abi_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleSelector() {
+	fmt.Printf("%x\n", abi.Selector("transfer(address,uint256)"))
+	fmt.Printf("%x\n", abi.Selector("function transfer(address to, uint amount) returns (bool)"))
+	fmt.Printf("%x\n", abi.Selector("fill(tuple(address maker, uint256 amount) order)"))
+
+	// Output:
+	// a9059cbb
+	// a9059cbb
+	// f867a389
+}
+
+func ExampleRegistry() {
+	registry, err := abi.NewRegistry("function transferFrom(address from, address to, uint256 amount)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	signature, ok := registry.Signature(abi.Selector("transferFrom(address,address,uint256)"))
+	fmt.Println(signature, ok)
+
+	err = registry.Register("gasprice_bit_ether(int128)")
+	fmt.Println(errors.Is(err, abi.ErrSelectorCollision))
+	fmt.Println(err)
+
+	fmt.Println(registry.Signatures())
+
+	// Output:
+	// transferFrom(address,address,uint256) true
+	// true
+	// selector collision: 0x23b872dd is the selector of both transferFrom(address,address,uint256) and gasprice_bit_ether(int128)
+	// [transferFrom(address,address,uint256)]
+}
+
+func ExampleRegistry_Merge() {
+	token, _ := abi.NewRegistry("function transferFrom(address from, address to, uint256 amount)")
+	facet, _ := abi.NewRegistry(
+		"function balanceOf(address owner) view returns (uint256)",
+		"function gasprice_bit_ether(int128)",
+	)
+
+	err := token.Merge(facet)
+	fmt.Println(errors.Is(err, abi.ErrSelectorCollision))
+	fmt.Println(token.Signatures())
+
+	// Output:
+	// true
+	// [balanceOf(address) transferFrom(address,address,uint256)]
+}
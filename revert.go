@@ -0,0 +1,79 @@
+package abi
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// builtinErrors are the errors emitted by Solidity's `revert("...")`,
+// `require(..., "...")` and failed assertions/arithmetic checks.
+var builtinErrors = []*Fragment{
+	MustParseFragment("error Error(string message)"),
+	MustParseFragment("error Panic(uint256 code)"),
+}
+
+// ParseRevert decodes revert data returned by a failed call. It
+// recognizes the built-in `Error(string)` and `Panic(uint256)` errors
+// and the custom errors given as human-readable fragments in errorABI
+// (i.e. `error InsufficientBalance(uint256 available, uint256 required)`),
+// matching them by selector. It returns the error name and its decoded
+// arguments, which can be parsed into a struct with Parse.
+func ParseRevert(data []byte, errorABI ...string) (string, []any, error) {
+	fragment, args, err := decodeRevert(data, errorABI)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fragment.Name, args, nil
+}
+
+// ParseRevertInto decodes revert data like ParseRevert and parses the
+// error arguments into the struct pointed by v. Fields tagged with
+// `abi:"name"` are mapped to the error parameter with the same name.
+// It returns the error name.
+func ParseRevertInto(data []byte, v any, errorABI ...string) (string, error) {
+	fragment, args, err := decodeRevert(data, errorABI)
+	if err != nil {
+		return "", err
+	}
+
+	err = ParseWithComponents(args, fragment.Inputs, v)
+	if err != nil {
+		return fragment.Name, err
+	}
+
+	return fragment.Name, nil
+}
+
+// decodeRevert finds the error fragment matching the selector of given
+// revert data and decodes its arguments.
+func decodeRevert(data []byte, errorABI []string) (*Fragment, []any, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("revert data is too short to contain an error selector. Length: %d", len(data))
+	}
+
+	fragments := append([]*Fragment{}, builtinErrors...)
+	for _, errorSig := range errorABI {
+		fragment, err := ParseFragment(errorSig)
+		if err != nil {
+			return nil, nil, err
+		}
+		fragments = append(fragments, fragment)
+	}
+
+	for _, fragment := range fragments {
+		if !isSelectorIsEqual(fragment.Selector(), data[:4]) {
+			continue
+		}
+
+		args, err := Decode(fragment.InputTypes(), data[4:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("error decoding %s: %w", fragment.Signature(), err)
+		}
+
+		return fragment, args, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown error selector: 0x%s", common.Bytes2Hex(data[:4]))
+}
@@ -0,0 +1,54 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleParseRevert() {
+	data := common.Hex2Bytes("08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000001a4e6f7420656e6f7567682045746865722070726f76696465642e000000000000")
+
+	name, args, err := abi.ParseRevert(data)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(name, args)
+
+	data = common.Hex2Bytes("4e487b710000000000000000000000000000000000000000000000000000000000000011")
+
+	name, args, err = abi.ParseRevert(data)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(name, args)
+
+	// Output:
+	// Error [Not enough Ether provided.]
+	// Panic [17]
+}
+
+func ExampleParseRevertInto() {
+	errorSig := "error InsufficientBalance(uint256 available, uint256 required)"
+	data, err := abi.EncodeWithSignature("InsufficientBalance(uint256,uint256)", big.NewInt(10), big.NewInt(25))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var insufficient struct {
+		Required  *big.Int `abi:"required"`
+		Available *big.Int `abi:"available"`
+	}
+	name, err := abi.ParseRevertInto(data, &insufficient, errorSig)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(name, insufficient.Available, insufficient.Required)
+
+	// Output: InsufficientBalance 10 25
+}
@@ -0,0 +1,279 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// sprintMaxBytes is the number of bytes of byte blobs written by Sprint
+// before they are truncated.
+const sprintMaxBytes = 32
+
+// Sprint renders decoded values of given components in a human-readable
+// form for logs and debugging output, one value per line prefixed with
+// its component name (or position, for unnamed components). Nested
+// tuples and arrays are indented, addresses are checksummed, integers
+// are written in decimal, strings are quoted and byte blobs longer than
+// 32 bytes are truncated, i.e.
+//
+//	to: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789
+//	amounts: [
+//	  [0]: 1000
+//	  [1]: 2000
+//	]
+//
+// Components may be nil, in which case values are named by position and
+// rendered following their Go type, decoded addresses being rendered as
+// the strings they are decoded into. Values not matching their component
+// are rendered with %v.
+func Sprint(decoded []any, components []Component) string {
+	p := &printer{}
+	p.tuple(decoded, components)
+
+	return p.String()
+}
+
+// SprintStruct renders a Go value holding parsed values (i.e. a struct,
+// or a pointer to it) like Sprint, struct fields being named by their
+// `abi:"name"` tag or their field name. Fields tagged with `abi:"-"`
+// and unexported fields are skipped.
+func SprintStruct(v any) string {
+	p := &printer{}
+	rv := unwrapValue(reflect.ValueOf(v))
+	if rv.IsValid() && rv.Kind() == reflect.Struct && !isCoreStruct(rv.Type()) {
+		p.structFields(rv)
+	} else {
+		p.line("", goLeaf(rv))
+	}
+
+	return p.String()
+}
+
+// printer writes the lines rendered by Sprint.
+type printer struct {
+	strings.Builder
+	depth int
+}
+
+// line writes a line at the current depth, prefixed with given name
+// when not empty.
+func (p *printer) line(name, text string) {
+	p.WriteString(strings.Repeat("  ", p.depth))
+	if name != "" {
+		p.WriteString(name + ": ")
+	}
+	p.WriteString(text + "\n")
+}
+
+// open writes the opening line of a nested list, or the whole list when
+// it is empty, returning whether members follow.
+func (p *printer) open(name, delimiters string, length int) bool {
+	if length == 0 {
+		p.line(name, delimiters)
+		return false
+	}
+	p.line(name, delimiters[:1])
+	p.depth++
+
+	return true
+}
+
+// close writes the closing line of a nested list.
+func (p *printer) close(delimiters string) {
+	p.depth--
+	p.line("", delimiters[1:])
+}
+
+// tuple writes the members of a decoded tuple, one per line.
+func (p *printer) tuple(members []any, components []Component) {
+	for i, member := range members {
+		name := strconv.Itoa(i)
+		var component *Component
+		if len(components) == len(members) {
+			component = &components[i]
+			if component.Name != "" {
+				name = component.Name
+			}
+		}
+		p.value(name, member, component)
+	}
+}
+
+// value writes a decoded value of given component, nil when unknown.
+func (p *printer) value(name string, value any, component *Component) {
+	typeStr := ""
+	if component != nil {
+		typeStr = component.Type
+	}
+	list, isList := value.([]any)
+
+	switch {
+	case isList && strings.HasSuffix(typeStr, "]"):
+		elem := *component
+		elem.Type = typeStr[:strings.LastIndex(typeStr, "[")]
+		if p.open(name, "[]", len(list)) {
+			for i, item := range list {
+				p.value("["+strconv.Itoa(i)+"]", item, &elem)
+			}
+			p.close("[]")
+		}
+	case isList && typeStr == "tuple":
+		if p.open(name, "()", len(list)) {
+			p.tuple(list, component.Components)
+			p.close("()")
+		}
+	case isList && component == nil:
+		// Without components, lists are rendered as tuples of unknown
+		// members.
+		if p.open(name, "()", len(list)) {
+			p.tuple(list, nil)
+			p.close("()")
+		}
+	default:
+		p.line(name, sprintLeaf(value, typeStr))
+	}
+}
+
+// structFields writes the exported fields of a struct value, one per
+// line.
+func (p *printer) structFields(rv reflect.Value) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, _ := ParseFieldTag(field)
+		if tag.Skip {
+			continue
+		}
+
+		name := field.Name
+		if tag.Name != "" {
+			name = tag.Name
+		}
+		p.goField(name, rv.Field(i))
+	}
+}
+
+// goField writes a Go value holding parsed values.
+func (p *printer) goField(name string, rv reflect.Value) {
+	rv = unwrapValue(rv)
+	if !rv.IsValid() {
+		p.line(name, "<nil>")
+		return
+	}
+
+	switch {
+	case rv.Kind() == reflect.Struct && !isCoreStruct(rv.Type()) && rv.Type() != uint256Type:
+		if p.open(name, "()", rv.NumField()) {
+			p.structFields(rv)
+			p.close("()")
+		}
+	case (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && !isBytesValue(rv):
+		if p.open(name, "[]", rv.Len()) {
+			for i := 0; i < rv.Len(); i++ {
+				p.goField("["+strconv.Itoa(i)+"]", rv.Index(i))
+			}
+			p.close("[]")
+		}
+	case rv.Kind() == reflect.Map:
+		keys := rv.MapKeys()
+		texts := make([]string, len(keys))
+		for i, key := range keys {
+			texts[i] = goLeaf(key)
+		}
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool { return texts[order[i]] < texts[order[j]] })
+		if p.open(name, "{}", len(keys)) {
+			for _, i := range order {
+				p.goField(texts[i], rv.MapIndex(keys[i]))
+			}
+			p.close("{}")
+		}
+	default:
+		p.line(name, goLeaf(rv))
+	}
+}
+
+// goLeaf renders a Go value which is not a list nor a struct.
+func goLeaf(rv reflect.Value) string {
+	rv = unwrapValue(rv)
+	switch {
+	case !rv.IsValid():
+		return "<nil>"
+	case rv.Type() == reflect.TypeOf(IndexedHash{}):
+		return common.Hash(rv.Interface().(IndexedHash)).Hex()
+	case rv.Type() == uint256Type:
+		return rv.Addr().Interface().(*uint256.Int).Dec()
+	case isCoreStruct(rv.Type()):
+		return sprintLeaf(rv.Addr().Interface(), "")
+	case rv.Type() != addressType && rv.Type() != reflect.TypeOf(common.Hash{}) && isBytesValue(rv):
+		return sprintBytes(bytesOf(rv))
+	}
+
+	return sprintLeaf(rv.Interface(), "")
+}
+
+// sprintLeaf renders a decoded value which is not a list, of given core
+// type when known.
+func sprintLeaf(value any, typeStr string) string {
+	switch value := value.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		if typeStr == "address" && common.IsHexAddress(value) {
+			return common.HexToAddress(value).Hex()
+		}
+		return strconv.Quote(value)
+	case *big.Int:
+		return value.String()
+	case *big.Float:
+		return value.Text('f', -1)
+	case *big.Rat:
+		if value.IsInt() {
+			return value.Num().String()
+		}
+		return value.RatString()
+	case []byte:
+		if strings.HasPrefix(typeStr, "bytes") && typeStr != "bytes" {
+			size, err := strconv.Atoi(typeStr[len("bytes"):])
+			if err == nil && size <= len(value) {
+				value = value[:size]
+			}
+		}
+		return sprintBytes(value)
+	case common.Address:
+		return value.Hex()
+	case *common.Address:
+		return value.Hex()
+	case common.Hash:
+		return value.Hex()
+	case FunctionPointer:
+		return value.String()
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// sprintBytes renders a byte blob as 0x prefixed hex, truncated after
+// sprintMaxBytes bytes.
+func sprintBytes(b []byte) string {
+	if len(b) <= sprintMaxBytes {
+		return "0x" + common.Bytes2Hex(b)
+	}
+
+	return fmt.Sprintf("0x%s… (%d bytes)", common.Bytes2Hex(b[:sprintMaxBytes]), len(b))
+}
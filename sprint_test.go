@@ -0,0 +1,93 @@
+package abi_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleSprint() {
+	fragment := abi.MustParseFragment(
+		"function fill((address maker, uint256 amount, bytes4 kind) order, uint256[] fees, bytes signature)",
+	)
+	typeStrs := abi.ComponentTypes(fragment.Inputs)
+
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	encoded, err := abi.Encode(typeStrs,
+		[]any{"0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789", amount, []byte{0xde, 0xad, 0xbe, 0xef}},
+		[]any{big.NewInt(1000), big.NewInt(2000)},
+		bytes.Repeat([]byte{0xab}, 65),
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.Decode(typeStrs, encoded)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Print(abi.Sprint(decoded, fragment.Inputs))
+
+	// Output:
+	// order: (
+	//   maker: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789
+	//   amount: 123456789012345678901234567890
+	//   kind: 0xdeadbeef
+	// )
+	// fees: [
+	//   [0]: 1000
+	//   [1]: 2000
+	// ]
+	// signature: 0xabababababababababababababababababababababababababababababababab… (65 bytes)
+}
+
+func ExampleSprint_noComponents() {
+	maker := common.HexToAddress("0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789")
+	fmt.Print(abi.Sprint([]any{
+		"0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789",
+		maker,
+		big.NewInt(7),
+	}, nil))
+
+	// Output:
+	// 0: "0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789"
+	// 1: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789
+	// 2: 7
+}
+
+func ExampleSprintStruct() {
+	type Order struct {
+		Maker  common.Address `abi:"maker"`
+		Amount *big.Int       `abi:"amount"`
+		Note   string         `abi:"-"`
+	}
+	type Fill struct {
+		Order Order
+		Fees  []*big.Int `abi:"fees"`
+		Memo  string     `abi:"memo"`
+	}
+
+	fmt.Print(abi.SprintStruct(&Fill{
+		Order: Order{
+			Maker:  common.HexToAddress("0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789"),
+			Amount: big.NewInt(42),
+			Note:   "skipped",
+		},
+		Fees: []*big.Int{big.NewInt(1000)},
+		Memo: "gm",
+	}))
+
+	// Output:
+	// Order: (
+	//   maker: 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789
+	//   amount: 42
+	// )
+	// fees: [
+	//   [0]: 1000
+	// ]
+	// memo: "gm"
+}
@@ -0,0 +1,5 @@
+// Package standards holds ready-made ABIs of the ERC-20, ERC-721 and
+// ERC-1155 token standards, built with the abi package, along with
+// typed structs of their events, so that token calls and logs can be
+// encoded and decoded without declaring their signatures.
+package standards
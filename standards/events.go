@@ -0,0 +1,154 @@
+package standards
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/omnes-tech/abi"
+)
+
+// ERC20Transfer is the Transfer event of ERC-20 tokens.
+type ERC20Transfer struct {
+	From  common.Address `abi:"from"`
+	To    common.Address `abi:"to"`
+	Value *big.Int       `abi:"value"`
+}
+
+// ERC20Approval is the Approval event of ERC-20 tokens.
+type ERC20Approval struct {
+	Owner   common.Address `abi:"owner"`
+	Spender common.Address `abi:"spender"`
+	Value   *big.Int       `abi:"value"`
+}
+
+// ERC721Transfer is the Transfer event of ERC-721 tokens, which shares
+// its topic with ERC20Transfer but indexes the token ID.
+type ERC721Transfer struct {
+	From    common.Address `abi:"from"`
+	To      common.Address `abi:"to"`
+	TokenID *big.Int       `abi:"tokenId"`
+}
+
+// ERC721Approval is the Approval event of ERC-721 tokens, which shares
+// its topic with ERC20Approval but indexes the token ID.
+type ERC721Approval struct {
+	Owner    common.Address `abi:"owner"`
+	Approved common.Address `abi:"approved"`
+	TokenID  *big.Int       `abi:"tokenId"`
+}
+
+// ApprovalForAll is the ApprovalForAll event of ERC-721 and ERC-1155
+// tokens, whose ERC-1155 owner is named account.
+type ApprovalForAll struct {
+	Owner    common.Address `abi:"owner"`
+	Operator common.Address `abi:"operator"`
+	Approved bool           `abi:"approved"`
+}
+
+// TransferSingle is the TransferSingle event of ERC-1155 tokens.
+type TransferSingle struct {
+	Operator common.Address `abi:"operator"`
+	From     common.Address `abi:"from"`
+	To       common.Address `abi:"to"`
+	ID       *big.Int       `abi:"id"`
+	Value    *big.Int       `abi:"value"`
+}
+
+// TransferBatch is the TransferBatch event of ERC-1155 tokens.
+type TransferBatch struct {
+	Operator common.Address `abi:"operator"`
+	From     common.Address `abi:"from"`
+	To       common.Address `abi:"to"`
+	IDs      []*big.Int     `abi:"ids"`
+	Values   []*big.Int     `abi:"values"`
+}
+
+// URI is the URI event of ERC-1155 tokens.
+type URI struct {
+	Value string   `abi:"value"`
+	ID    *big.Int `abi:"id"`
+}
+
+// event is a standard event along with the constructor of its struct.
+type event struct {
+	fragment  *abi.Fragment
+	newTarget func() any
+}
+
+// events holds the standard events keyed by their topic. Events sharing
+// a topic are told apart by their number of indexed parameters.
+var events = make(map[common.Hash][]event)
+
+func init() {
+	for _, e := range []struct {
+		contract  *abi.Contract
+		name      string
+		newTarget func() any
+	}{
+		{ERC20, "Transfer", func() any { return new(ERC20Transfer) }},
+		{ERC20, "Approval", func() any { return new(ERC20Approval) }},
+		{ERC721, "Transfer", func() any { return new(ERC721Transfer) }},
+		{ERC721, "Approval", func() any { return new(ERC721Approval) }},
+		{ERC721, "ApprovalForAll", func() any { return new(ApprovalForAll) }},
+		{ERC1155, "TransferSingle", func() any { return new(TransferSingle) }},
+		{ERC1155, "TransferBatch", func() any { return new(TransferBatch) }},
+		{ERC1155, "URI", func() any { return new(URI) }},
+	} {
+		fragment, err := e.contract.Event(e.name)
+		if err != nil {
+			panic(err)
+		}
+		events[fragment.Topic()] = append(events[fragment.Topic()], event{fragment: fragment, newTarget: e.newTarget})
+	}
+}
+
+// DecodeEvent decodes a log emitted by a token contract into the struct
+// of its standard event, returned as a pointer (i.e. *ERC20Transfer or
+// *TransferBatch), which callers switch on. Transfer and Approval logs
+// are decoded as ERC-20 or ERC-721 events depending on their number of
+// topics.
+func DecodeEvent(log types.Log) (any, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log has no topics")
+	}
+
+	candidates, ok := events[log.Topics[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown event topic: %s", log.Topics[0].Hex())
+	}
+
+	for _, candidate := range candidates {
+		if indexedCount(candidate.fragment)+1 != len(log.Topics) {
+			continue
+		}
+
+		decoded, err := abi.DecodeLog(log, candidate.fragment)
+		if err != nil {
+			return nil, err
+		}
+
+		target := candidate.newTarget()
+		err = abi.ParseWithComponents(decoded, candidate.fragment.Inputs, target)
+		if err != nil {
+			return nil, err
+		}
+
+		return target, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %d topics for event %s", len(log.Topics), candidates[0].fragment.Name)
+}
+
+// indexedCount returns the number of indexed parameters of an event.
+func indexedCount(event *abi.Fragment) int {
+	var count int
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,104 @@
+package standards
+
+import (
+	"github.com/omnes-tech/abi"
+)
+
+// ERC20Signatures holds the methods and events of the ERC-20 standard.
+var ERC20Signatures = []string{
+	"function name() view returns (string)",
+	"function symbol() view returns (string)",
+	"function decimals() view returns (uint8)",
+	"function totalSupply() view returns (uint256)",
+	"function balanceOf(address owner) view returns (uint256)",
+	"function allowance(address owner, address spender) view returns (uint256)",
+	"function transfer(address to, uint256 value) returns (bool)",
+	"function approve(address spender, uint256 value) returns (bool)",
+	"function transferFrom(address from, address to, uint256 value) returns (bool)",
+	"event Transfer(address indexed from, address indexed to, uint256 value)",
+	"event Approval(address indexed owner, address indexed spender, uint256 value)",
+}
+
+// ERC721Signatures holds the methods and events of the ERC-721
+// standard, including its metadata extension.
+var ERC721Signatures = []string{
+	"function name() view returns (string)",
+	"function symbol() view returns (string)",
+	"function tokenURI(uint256 tokenId) view returns (string)",
+	"function balanceOf(address owner) view returns (uint256)",
+	"function ownerOf(uint256 tokenId) view returns (address)",
+	"function getApproved(uint256 tokenId) view returns (address)",
+	"function isApprovedForAll(address owner, address operator) view returns (bool)",
+	"function approve(address to, uint256 tokenId)",
+	"function setApprovalForAll(address operator, bool approved)",
+	"function transferFrom(address from, address to, uint256 tokenId)",
+	"function safeTransferFrom(address from, address to, uint256 tokenId)",
+	"function safeTransferFrom(address from, address to, uint256 tokenId, bytes data)",
+	"event Transfer(address indexed from, address indexed to, uint256 indexed tokenId)",
+	"event Approval(address indexed owner, address indexed approved, uint256 indexed tokenId)",
+	"event ApprovalForAll(address indexed owner, address indexed operator, bool approved)",
+}
+
+// ERC1155Signatures holds the methods and events of the ERC-1155
+// standard, including its metadata URI extension.
+var ERC1155Signatures = []string{
+	"function uri(uint256 id) view returns (string)",
+	"function balanceOf(address account, uint256 id) view returns (uint256)",
+	"function balanceOfBatch(address[] accounts, uint256[] ids) view returns (uint256[])",
+	"function isApprovedForAll(address account, address operator) view returns (bool)",
+	"function setApprovalForAll(address operator, bool approved)",
+	"function safeTransferFrom(address from, address to, uint256 id, uint256 value, bytes data)",
+	"function safeBatchTransferFrom(address from, address to, uint256[] ids, uint256[] values, bytes data)",
+	"event TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)",
+	"event TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)",
+	"event ApprovalForAll(address indexed account, address indexed operator, bool approved)",
+	"event URI(string value, uint256 indexed id)",
+}
+
+// Contracts of the token standards, whose methods are called with
+// EncodeCall and DecodeReturn (i.e. `ERC20.EncodeCall("balanceOf", owner)`).
+// They are shared and must not be modified.
+var (
+	ERC20   = mustContract(ERC20Signatures)
+	ERC721  = mustContract(ERC721Signatures)
+	ERC1155 = mustContract(ERC1155Signatures)
+)
+
+// NewRegistry creates a registry holding the methods of all token
+// standards, suitable for abi.DecodeCalldata and abi.DecodeTransaction.
+// Methods shared by several standards (i.e. transferFrom, shared by
+// ERC-20 and ERC-721) are registered once, with the outputs of the
+// first one.
+func NewRegistry() *abi.Registry {
+	registry, err := abi.NewRegistry()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, contract := range []*abi.Contract{ERC20, ERC721, ERC1155} {
+		for _, method := range contract.Methods {
+			err = registry.RegisterFragment(method)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	return registry
+}
+
+// mustContract creates the contract of given signatures, which are
+// known to be valid.
+func mustContract(signatures []string) *abi.Contract {
+	fragments := make([]*abi.Fragment, len(signatures))
+	for i, signature := range signatures {
+		fragments[i] = abi.MustParseFragment(signature)
+	}
+
+	contract, err := abi.NewContract(fragments...)
+	if err != nil {
+		panic(err)
+	}
+
+	return contract
+}
@@ -0,0 +1,63 @@
+package standards_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/omnes-tech/abi/standards"
+)
+
+func ExampleDecodeEvent() {
+	transferTopic := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	from := common.HexToHash("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	to := common.HexToHash("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	logs := []types.Log{
+		{Topics: []common.Hash{transferTopic, from, to}, Data: common.BigToHash(big.NewInt(1_000_000)).Bytes()},
+		{Topics: []common.Hash{transferTopic, from, to, common.BigToHash(big.NewInt(42))}},
+	}
+
+	for _, log := range logs {
+		event, err := standards.DecodeEvent(log)
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		switch event := event.(type) {
+		case *standards.ERC20Transfer:
+			fmt.Println("ERC-20", event.From, event.To, event.Value)
+		case *standards.ERC721Transfer:
+			fmt.Println("ERC-721", event.From, event.To, event.TokenID)
+		}
+	}
+
+	// Output:
+	// ERC-20 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 1000000
+	// ERC-721 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 42
+}
+
+func ExampleERC20() {
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+	call, err := standards.ERC20.EncodeCall("balanceOf", owner)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(common.Bytes2Hex(call[:4]))
+
+	// The return data of eth_call.
+	data := common.BigToHash(big.NewInt(2_500_000)).Bytes()
+
+	var balance *big.Int
+	err = standards.ERC20.DecodeReturn("balanceOf", data, &balance)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(balance)
+
+	// Output:
+	// 70a08231
+	// 2500000
+}
@@ -0,0 +1,5 @@
+// Package storage decodes contract state from raw storage slots (i.e.
+// read with eth_getStorageAt) following the storage layout emitted by
+// solc, handling packed slots, mappings, dynamic arrays, strings and
+// structs, and parses it into Go values with the abi package.
+package storage
@@ -0,0 +1,656 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/omnes-tech/abi"
+)
+
+// maxLength bounds the length of the dynamic arrays, and the number of
+// slots of strings and bytes, read at once, since each element is a
+// request to the node and corrupt lengths may be huge. Elements of
+// longer arrays can be read one by one with an index in the path.
+const maxLength = 1 << 16
+
+// Layout is the storage layout of a contract, as emitted by solc with
+// the `storageLayout` output selection.
+type Layout struct {
+	Storage []Variable       `json:"storage"`
+	Types   map[string]*Type `json:"types"`
+}
+
+// Variable is a state variable, or a member of a struct, stored from
+// given slot and byte offset within that slot (from its lower-order,
+// right-aligned end).
+type Variable struct {
+	AstID    int    `json:"astId"`
+	Contract string `json:"contract"`
+	Label    string `json:"label"`
+	Offset   int    `json:"offset"`
+	Slot     string `json:"slot"`
+	Type     string `json:"type"`
+}
+
+// Type is a storage type. Its encoding is either `inplace` (value
+// types, structs and static arrays), `mapping`, `dynamic_array` or
+// `bytes` (strings and bytes).
+type Type struct {
+	Encoding      string     `json:"encoding"`
+	Label         string     `json:"label"`
+	NumberOfBytes string     `json:"numberOfBytes"`
+	Key           string     `json:"key,omitempty"`
+	Value         string     `json:"value,omitempty"`
+	Base          string     `json:"base,omitempty"`
+	Members       []Variable `json:"members,omitempty"`
+}
+
+// Location is where a value is stored: the slot it starts from, and
+// for packed value types its byte offset within that slot.
+type Location struct {
+	Slot   common.Hash
+	Offset int
+	Type   *Type
+}
+
+// StorageReader reads the raw storage of accounts. It is implemented by
+// ethclient.Client.
+type StorageReader interface {
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// Slots reads the raw value of a storage slot.
+type Slots func(ctx context.Context, slot common.Hash) (common.Hash, error)
+
+// At reads the storage slots of given account at given block, or at the
+// latest block when nil.
+func At(reader StorageReader, account common.Address, blockNumber *big.Int) Slots {
+	return func(ctx context.Context, slot common.Hash) (common.Hash, error) {
+		value, err := reader.StorageAt(ctx, account, slot, blockNumber)
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		return common.BytesToHash(value), nil
+	}
+}
+
+// Load parses a storage layout, given either as emitted by solc or
+// nested in the `storageLayout` field of a build artifact.
+func Load(r io.Reader) (*Layout, error) {
+	var artifact struct {
+		Layout
+		StorageLayout *Layout `json:"storageLayout"`
+	}
+	err := json.NewDecoder(r).Decode(&artifact)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding storage layout: %w", err)
+	}
+
+	layout := &artifact.Layout
+	if artifact.StorageLayout != nil {
+		layout = artifact.StorageLayout
+	}
+	for _, variable := range layout.Storage {
+		if _, err := layout.typeOf(variable.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	return layout, nil
+}
+
+// Locate returns the location of the state variable with given label,
+// following given path through its value: a key for mappings, an index
+// for arrays and a member label for structs. Array indexes are not
+// checked against the length of dynamic arrays.
+func (l *Layout) Locate(label string, path ...any) (Location, error) {
+	variable, err := l.variable(label)
+	if err != nil {
+		return Location{}, err
+	}
+
+	loc, err := l.member(uint256.NewInt(0), variable)
+	if err != nil {
+		return Location{}, err
+	}
+
+	for _, step := range path {
+		base := new(uint256.Int).SetBytes32(loc.Slot[:])
+
+		switch {
+		case loc.Type.Encoding == "mapping":
+			loc, err = l.mappingValue(loc, step)
+
+		case loc.Type.Encoding == "dynamic_array":
+			loc, err = l.element(new(uint256.Int).SetBytes(crypto.Keccak256(loc.Slot[:])), loc.Type, step)
+
+		case loc.Type.Encoding == "inplace" && loc.Type.Base != "":
+			loc, err = l.element(base, loc.Type, step)
+
+		case loc.Type.Encoding == "inplace" && loc.Type.Members != nil:
+			name, ok := step.(string)
+			if !ok {
+				return Location{}, fmt.Errorf("expected member name of %s, got %T", loc.Type.Label, step)
+			}
+			var found bool
+			for _, member := range loc.Type.Members {
+				if member.Label == name {
+					loc, err = l.member(base, member)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return Location{}, fmt.Errorf("%s has no member %s", loc.Type.Label, name)
+			}
+
+		default:
+			return Location{}, fmt.Errorf("%s can't be indexed", loc.Type.Label)
+		}
+		if err != nil {
+			return Location{}, err
+		}
+	}
+
+	return loc, nil
+}
+
+// Value reads and decodes the value found at given label and path (see
+// Locate). Values are decoded in the same forms as abi.Decode: structs
+// and arrays are []any, without the mapping members of structs, which
+// can only be read with a key.
+func (l *Layout) Value(ctx context.Context, slots Slots, label string, path ...any) (any, error) {
+	loc, err := l.Locate(label, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{ctx: ctx, slots: slots, cache: make(map[common.Hash]common.Hash)}
+	return l.decode(r, loc)
+}
+
+// Read reads the value found at given label and path (see Locate) and
+// parses it into the value pointed by v, following the conventions of
+// abi.Parse. Struct fields tagged with `abi:"name"` are mapped to the
+// struct member with the same label.
+func (l *Layout) Read(ctx context.Context, slots Slots, v any, label string, path ...any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("expected non-nil pointer, got %T", v)
+	}
+
+	loc, err := l.Locate(label, path...)
+	if err != nil {
+		return err
+	}
+
+	component, err := l.component(loc.Type)
+	if err != nil {
+		return err
+	}
+
+	r := &reader{ctx: ctx, slots: slots, cache: make(map[common.Hash]common.Hash)}
+	value, err := l.decode(r, loc)
+	if err != nil {
+		return err
+	}
+
+	// Values are parsed as the single field of a struct, so that any
+	// target supported by the abi package can be used.
+	wrapper := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: rv.Type().Elem(), Tag: `abi:"value"`},
+	}))
+	component.Name = "value"
+	err = abi.ParseWithComponents([]any{value}, []abi.Component{component}, wrapper.Interface())
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %w", label, err)
+	}
+	rv.Elem().Set(wrapper.Elem().Field(0))
+
+	return nil
+}
+
+// reader reads the slots needed to decode a value, reading each slot
+// once since packed values share slots.
+type reader struct {
+	ctx   context.Context
+	slots Slots
+	cache map[common.Hash]common.Hash
+}
+
+// read reads the value of given slot.
+func (r *reader) read(slot common.Hash) (common.Hash, error) {
+	if value, ok := r.cache[slot]; ok {
+		return value, nil
+	}
+
+	value, err := r.slots(r.ctx, slot)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error reading slot %s: %w", slot.Hex(), err)
+	}
+	r.cache[slot] = value
+
+	return value, nil
+}
+
+// decode reads and decodes the value at given location.
+func (l *Layout) decode(r *reader, loc Location) (any, error) {
+	t := loc.Type
+	switch {
+	case t.Encoding == "mapping":
+		return nil, fmt.Errorf("%s can only be read with a key", t.Label)
+
+	case t.Encoding == "bytes":
+		return l.decodeBytes(r, loc)
+
+	case t.Encoding == "dynamic_array":
+		word, err := r.read(loc.Slot)
+		if err != nil {
+			return nil, err
+		}
+		length := new(big.Int).SetBytes(word[:])
+		if length.Cmp(big.NewInt(maxLength)) > 0 {
+			return nil, fmt.Errorf("length %s of %s exceeds %d, read its elements by index", length, t.Label, maxLength)
+		}
+		base := new(uint256.Int).SetBytes(crypto.Keccak256(loc.Slot[:]))
+
+		return l.decodeElements(r, base, t, int(length.Int64()))
+
+	case t.Encoding == "inplace" && t.Base != "":
+		length, err := arrayLength(t)
+		if err != nil {
+			return nil, err
+		}
+
+		return l.decodeElements(r, new(uint256.Int).SetBytes32(loc.Slot[:]), t, length)
+
+	case t.Encoding == "inplace" && t.Members != nil:
+		base := new(uint256.Int).SetBytes32(loc.Slot[:])
+		values := make([]any, 0, len(t.Members))
+		for _, member := range t.Members {
+			memberLoc, err := l.member(base, member)
+			if err != nil {
+				return nil, err
+			}
+			if memberLoc.Type.Encoding == "mapping" {
+				continue
+			}
+
+			value, err := l.decode(r, memberLoc)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+
+	case t.Encoding == "inplace":
+		return l.decodeValueType(r, loc)
+	}
+
+	return nil, fmt.Errorf("unsupported storage encoding %q of %s", t.Encoding, t.Label)
+}
+
+// decodeElements decodes the elements of an array stored from base.
+func (l *Layout) decodeElements(r *reader, base *uint256.Int, array *Type, length int) ([]any, error) {
+	values := make([]any, length)
+	for i := range values {
+		loc, err := l.element(base, array, i)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i], err = l.decode(r, loc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// decodeBytes decodes a string or bytes value. Values shorter than 32
+// bytes are stored in their slot along with their length, and longer
+// ones from the keccak256 hash of their slot.
+func (l *Layout) decodeBytes(r *reader, loc Location) (any, error) {
+	word, err := r.read(loc.Slot)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if word[31]&1 == 0 {
+		length := int(word[31] / 2)
+		if length > 31 {
+			return nil, fmt.Errorf("invalid short length %d of %s", length, loc.Type.Label)
+		}
+		data = append([]byte{}, word[:length]...)
+	} else {
+		length := new(big.Int).Rsh(new(big.Int).SetBytes(word[:]), 1)
+		if length.Cmp(big.NewInt(maxLength*32)) > 0 {
+			return nil, fmt.Errorf("length %s of %s exceeds %d bytes", length, loc.Type.Label, maxLength*32)
+		}
+
+		data = make([]byte, 0, length.Int64()+31)
+		slot := new(uint256.Int).SetBytes(crypto.Keccak256(loc.Slot[:]))
+		for int64(len(data)) < length.Int64() {
+			word, err := r.read(slot.Bytes32())
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, word[:]...)
+			slot.AddUint64(slot, 1)
+		}
+		data = data[:length.Int64()]
+	}
+
+	if loc.Type.Label == "string" {
+		return string(data), nil
+	}
+
+	return data, nil
+}
+
+// decodeValueType decodes a value type packed in a slot, by decoding
+// its bytes as an ABI word.
+func (l *Layout) decodeValueType(r *reader, loc Location) (any, error) {
+	typeStr, err := valueType(loc.Type)
+	if err != nil {
+		return nil, err
+	}
+	size, err := typeSize(loc.Type)
+	if err != nil {
+		return nil, err
+	}
+	if size > 32 || loc.Offset+size > 32 {
+		return nil, fmt.Errorf("%s at offset %d does not fit in a slot", loc.Type.Label, loc.Offset)
+	}
+
+	word, err := r.read(loc.Slot)
+	if err != nil {
+		return nil, err
+	}
+	value := word[32-loc.Offset-size : 32-loc.Offset]
+
+	// Fixed bytes are left-aligned in ABI words, other values are
+	// right-aligned.
+	var encoded [32]byte
+	if strings.HasPrefix(typeStr, "bytes") {
+		copy(encoded[:], value)
+	} else {
+		copy(encoded[32-size:], value)
+	}
+
+	decoded, err := abi.Decode([]string{typeStr}, encoded[:])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", loc.Type.Label, err)
+	}
+
+	return decoded[0], nil
+}
+
+// variable returns the state variable with given label.
+func (l *Layout) variable(label string) (Variable, error) {
+	for _, variable := range l.Storage {
+		if variable.Label == label {
+			return variable, nil
+		}
+	}
+
+	return Variable{}, fmt.Errorf("state variable %s not found", label)
+}
+
+// typeOf returns the type with given identifier.
+func (l *Layout) typeOf(id string) (*Type, error) {
+	t, ok := l.Types[id]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in storage layout", id)
+	}
+
+	return t, nil
+}
+
+// member returns the location of a variable, or of a struct member,
+// whose slot is relative to given base slot.
+func (l *Layout) member(base *uint256.Int, variable Variable) (Location, error) {
+	slot, err := uint256.FromDecimal(variable.Slot)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid slot %q of %s: %w", variable.Slot, variable.Label, err)
+	}
+	t, err := l.typeOf(variable.Type)
+	if err != nil {
+		return Location{}, err
+	}
+
+	return Location{Slot: slot.Add(slot, base).Bytes32(), Offset: variable.Offset, Type: t}, nil
+}
+
+// mappingValue returns the location of the value of a mapping stored at
+// loc for given key, which is stored at the keccak256 hash of the key
+// (padded to 32 bytes for value types) followed by the mapping slot.
+func (l *Layout) mappingValue(loc Location, key any) (Location, error) {
+	keyType, err := l.typeOf(loc.Type.Key)
+	if err != nil {
+		return Location{}, err
+	}
+	valueType, err := l.typeOf(loc.Type.Value)
+	if err != nil {
+		return Location{}, err
+	}
+
+	var encodedKey []byte
+	if keyType.Encoding == "bytes" {
+		switch key := key.(type) {
+		case string:
+			encodedKey = []byte(key)
+		case []byte:
+			encodedKey = key
+		default:
+			return Location{}, fmt.Errorf("expected string or []byte key of %s, got %T", loc.Type.Label, key)
+		}
+	} else {
+		typeStr, err := abiType(keyType)
+		if err != nil {
+			return Location{}, err
+		}
+		encodedKey, err = abi.Encode([]string{typeStr}, key)
+		if err != nil {
+			return Location{}, fmt.Errorf("error encoding key of %s: %w", loc.Type.Label, err)
+		}
+	}
+
+	return Location{Slot: crypto.Keccak256Hash(encodedKey, loc.Slot[:]), Type: valueType}, nil
+}
+
+// element returns the location of an element of an array whose
+// elements are stored from given base slot. Elements smaller than 32
+// bytes are packed, others start a new slot.
+func (l *Layout) element(base *uint256.Int, array *Type, index any) (Location, error) {
+	elem, err := l.typeOf(array.Base)
+	if err != nil {
+		return Location{}, err
+	}
+	i, err := toIndex(index)
+	if err != nil {
+		return Location{}, err
+	}
+	if array.Encoding == "inplace" {
+		length, err := arrayLength(array)
+		if err != nil {
+			return Location{}, err
+		}
+		if i >= uint64(length) {
+			return Location{}, fmt.Errorf("index %d out of range of %s", i, array.Label)
+		}
+	}
+
+	size, err := typeSize(elem)
+	if err != nil {
+		return Location{}, err
+	}
+
+	slot := new(uint256.Int)
+	var offset int
+	if size < 32 {
+		perSlot := uint64(32 / size)
+		slot.SetUint64(i / perSlot)
+		offset = int(i%perSlot) * size
+	} else {
+		slot.Mul(uint256.NewInt(i), uint256.NewInt(uint64((size+31)/32)))
+	}
+
+	return Location{Slot: slot.Add(slot, base).Bytes32(), Offset: offset, Type: elem}, nil
+}
+
+// component returns the abi component describing values of given type,
+// with the members of structs (but mappings) as tuple components.
+func (l *Layout) component(t *Type) (abi.Component, error) {
+	switch {
+	case t.Encoding == "dynamic_array" || (t.Encoding == "inplace" && t.Base != ""):
+		elem, err := l.typeOf(t.Base)
+		if err != nil {
+			return abi.Component{}, err
+		}
+		component, err := l.component(elem)
+		if err != nil {
+			return abi.Component{}, err
+		}
+
+		if t.Encoding == "dynamic_array" {
+			component.Type += "[]"
+		} else {
+			length, err := arrayLength(t)
+			if err != nil {
+				return abi.Component{}, err
+			}
+			component.Type += "[" + strconv.Itoa(length) + "]"
+		}
+		return component, nil
+
+	case t.Encoding == "inplace" && t.Members != nil:
+		component := abi.Component{Type: "tuple", Components: []abi.Component{}}
+		for _, member := range t.Members {
+			memberType, err := l.typeOf(member.Type)
+			if err != nil {
+				return abi.Component{}, err
+			}
+			if memberType.Encoding == "mapping" {
+				continue
+			}
+
+			memberComponent, err := l.component(memberType)
+			if err != nil {
+				return abi.Component{}, err
+			}
+			memberComponent.Name = member.Label
+			component.Components = append(component.Components, memberComponent)
+		}
+		return component, nil
+	}
+
+	typeStr, err := abiType(t)
+	if err != nil {
+		return abi.Component{}, err
+	}
+
+	return abi.Component{Type: typeStr}, nil
+}
+
+// abiType returns the abi type of strings, bytes and value types.
+func abiType(t *Type) (string, error) {
+	switch t.Encoding {
+	case "bytes":
+		if t.Label == "string" {
+			return "string", nil
+		}
+		return "bytes", nil
+	case "inplace":
+		return valueType(t)
+	}
+
+	return "", fmt.Errorf("%s is not a value type", t.Label)
+}
+
+// valueType returns the abi type of a value type. Contracts are
+// addresses, enums are uint8 and user-defined value types are unsigned
+// integers of their size.
+func valueType(t *Type) (string, error) {
+	size, err := typeSize(t)
+	if err != nil {
+		return "", err
+	}
+
+	label := t.Label
+	switch {
+	case label == "address" || label == "address payable" || strings.HasPrefix(label, "contract "):
+		return "address", nil
+	case label == "bool":
+		return "bool", nil
+	case strings.HasPrefix(label, "enum "):
+		return "uint8", nil
+	case strings.HasPrefix(label, "function "):
+		return "bytes24", nil
+	case strings.HasPrefix(label, "uint") || strings.HasPrefix(label, "int") || strings.HasPrefix(label, "bytes"):
+		return label, nil
+	case t.Members == nil && t.Base == "" && size <= 32:
+		return "uint" + strconv.Itoa(size*8), nil
+	}
+
+	return "", fmt.Errorf("%s is not a value type", t.Label)
+}
+
+// typeSize returns the number of bytes taken by values of given type.
+func typeSize(t *Type) (int, error) {
+	size, err := strconv.Atoi(t.NumberOfBytes)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid number of bytes %q of %s", t.NumberOfBytes, t.Label)
+	}
+
+	return size, nil
+}
+
+// arrayLength returns the length of a static array, given at the end of
+// its label (i.e. `uint256[3]`).
+func arrayLength(t *Type) (int, error) {
+	open := strings.LastIndex(t.Label, "[")
+	if open == -1 || !strings.HasSuffix(t.Label, "]") {
+		return 0, fmt.Errorf("invalid static array %s", t.Label)
+	}
+
+	length, err := strconv.Atoi(t.Label[open+1 : len(t.Label)-1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid static array %s", t.Label)
+	}
+
+	return length, nil
+}
+
+// toIndex converts an array index given in a path.
+func toIndex(index any) (uint64, error) {
+	if bi, ok := index.(*big.Int); ok {
+		if bi.Sign() < 0 || !bi.IsUint64() {
+			return 0, fmt.Errorf("invalid array index %s", bi)
+		}
+		return bi.Uint64(), nil
+	}
+
+	rv := reflect.ValueOf(index)
+	switch {
+	case rv.CanUint():
+		return rv.Uint(), nil
+	case rv.CanInt() && rv.Int() >= 0:
+		return uint64(rv.Int()), nil
+	}
+
+	return 0, fmt.Errorf("invalid array index %v", index)
+}
@@ -0,0 +1,169 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/omnes-tech/abi/storage"
+)
+
+// vaultLayout is the storage layout emitted by solc for:
+//
+//	contract Vault {
+//	    struct Position { uint128 amount; uint64 start; address token; }
+//
+//	    address owner;
+//	    uint96 fee;
+//	    bool paused;
+//	    mapping(address => uint256) balances;
+//	    uint256[] deposits;
+//	    string name;
+//	    mapping(address => Position) positions;
+//	    uint16[3] rates;
+//	}
+const vaultLayout = `{
+	"storage": [
+		{"astId": 3, "contract": "Vault.sol:Vault", "label": "owner", "offset": 0, "slot": "0", "type": "t_address"},
+		{"astId": 5, "contract": "Vault.sol:Vault", "label": "fee", "offset": 20, "slot": "0", "type": "t_uint96"},
+		{"astId": 7, "contract": "Vault.sol:Vault", "label": "paused", "offset": 0, "slot": "1", "type": "t_bool"},
+		{"astId": 11, "contract": "Vault.sol:Vault", "label": "balances", "offset": 0, "slot": "2", "type": "t_mapping(t_address,t_uint256)"},
+		{"astId": 14, "contract": "Vault.sol:Vault", "label": "deposits", "offset": 0, "slot": "3", "type": "t_array(t_uint256)dyn_storage"},
+		{"astId": 16, "contract": "Vault.sol:Vault", "label": "name", "offset": 0, "slot": "4", "type": "t_string_storage"},
+		{"astId": 21, "contract": "Vault.sol:Vault", "label": "positions", "offset": 0, "slot": "5", "type": "t_mapping(t_address,t_struct(Position)1_storage)"},
+		{"astId": 25, "contract": "Vault.sol:Vault", "label": "rates", "offset": 0, "slot": "6", "type": "t_array(t_uint16)3_storage"}
+	],
+	"types": {
+		"t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+		"t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+		"t_uint16": {"encoding": "inplace", "label": "uint16", "numberOfBytes": "2"},
+		"t_uint64": {"encoding": "inplace", "label": "uint64", "numberOfBytes": "8"},
+		"t_uint96": {"encoding": "inplace", "label": "uint96", "numberOfBytes": "12"},
+		"t_uint128": {"encoding": "inplace", "label": "uint128", "numberOfBytes": "16"},
+		"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+		"t_string_storage": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+		"t_array(t_uint256)dyn_storage": {"encoding": "dynamic_array", "label": "uint256[]", "numberOfBytes": "32", "base": "t_uint256"},
+		"t_array(t_uint16)3_storage": {"encoding": "inplace", "label": "uint16[3]", "numberOfBytes": "32", "base": "t_uint16"},
+		"t_mapping(t_address,t_uint256)": {"encoding": "mapping", "label": "mapping(address => uint256)", "numberOfBytes": "32", "key": "t_address", "value": "t_uint256"},
+		"t_mapping(t_address,t_struct(Position)1_storage)": {"encoding": "mapping", "label": "mapping(address => struct Vault.Position)", "numberOfBytes": "32", "key": "t_address", "value": "t_struct(Position)1_storage"},
+		"t_struct(Position)1_storage": {"encoding": "inplace", "label": "struct Vault.Position", "numberOfBytes": "64", "members": [
+			{"astId": 18, "contract": "Vault.sol:Vault", "label": "amount", "offset": 0, "slot": "0", "type": "t_uint128"},
+			{"astId": 19, "contract": "Vault.sol:Vault", "label": "start", "offset": 16, "slot": "0", "type": "t_uint64"},
+			{"astId": 20, "contract": "Vault.sol:Vault", "label": "token", "offset": 0, "slot": "1", "type": "t_address"}
+		]}
+	}
+}`
+
+// slotAt returns the slot at given offset from base.
+func slotAt(base common.Hash, offset int64) common.Hash {
+	return common.BigToHash(new(big.Int).Add(base.Big(), big.NewInt(offset)))
+}
+
+// vaultStorage builds the raw storage of a Vault.
+func vaultStorage(owner, user, token common.Address) map[common.Hash]common.Hash {
+	var slot0, slot6, name, position0 common.Hash
+	copy(slot0[12:], owner[:])
+	copy(slot0[:12], common.LeftPadBytes(big.NewInt(30).Bytes(), 12))
+	slot6[31], slot6[29], slot6[27] = 100, 200, 44 // 100, 200 and 300 (0x012c)
+	slot6[26] = 0x01
+	copy(name[:], "Vault")
+	name[31] = 5 * 2
+	userKey := common.LeftPadBytes(user[:], 32)
+	copy(position0[16:], common.LeftPadBytes(big.NewInt(1e18).Bytes(), 16))
+	copy(position0[8:16], common.LeftPadBytes(big.NewInt(1700000000).Bytes(), 8))
+	positionBase := crypto.Keccak256Hash(userKey, common.BigToHash(big.NewInt(5)).Bytes())
+	depositsBase := crypto.Keccak256Hash(common.BigToHash(big.NewInt(3)).Bytes())
+
+	return map[common.Hash]common.Hash{
+		common.BigToHash(big.NewInt(0)):                                        slot0,
+		common.BigToHash(big.NewInt(1)):                                        common.BigToHash(big.NewInt(1)),
+		crypto.Keccak256Hash(userKey, common.BigToHash(big.NewInt(2)).Bytes()): common.BigToHash(big.NewInt(5000)),
+		common.BigToHash(big.NewInt(3)):                                        common.BigToHash(big.NewInt(2)),
+		slotAt(depositsBase, 0):                                                common.BigToHash(big.NewInt(7)),
+		slotAt(depositsBase, 1):                                                common.BigToHash(big.NewInt(8)),
+		common.BigToHash(big.NewInt(4)):                                        name,
+		slotAt(positionBase, 0):                                                position0,
+		slotAt(positionBase, 1):                                                common.BytesToHash(token[:]),
+		common.BigToHash(big.NewInt(6)):                                        slot6,
+	}
+}
+
+func ExampleLayout_Read() {
+	layout, err := storage.Load(strings.NewReader(vaultLayout))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	user := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	raw := vaultStorage(owner, user, token)
+
+	// Slots are usually read from a node with storage.At(client, vault, nil).
+	slots := func(_ context.Context, slot common.Hash) (common.Hash, error) {
+		return raw[slot], nil
+	}
+
+	ctx := context.Background()
+	var state struct {
+		Owner  common.Address
+		Fee    uint64
+		Paused bool
+	}
+	_ = layout.Read(ctx, slots, &state.Owner, "owner")
+	_ = layout.Read(ctx, slots, &state.Fee, "fee")
+	_ = layout.Read(ctx, slots, &state.Paused, "paused")
+	fmt.Println(state.Owner.Hex(), state.Fee, state.Paused)
+
+	var balance *big.Int
+	_ = layout.Read(ctx, slots, &balance, "balances", user)
+	fmt.Println(balance)
+
+	var deposits []*big.Int
+	_ = layout.Read(ctx, slots, &deposits, "deposits")
+	fmt.Println(deposits)
+
+	var name string
+	_ = layout.Read(ctx, slots, &name, "name")
+	fmt.Println(name)
+
+	var position struct {
+		Amount *big.Int       `abi:"amount"`
+		Start  uint64         `abi:"start"`
+		Token  common.Address `abi:"token"`
+	}
+	err = layout.Read(ctx, slots, &position, "positions", user)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(position.Amount, position.Start, position.Token.Hex())
+
+	var rates [3]uint16
+	_ = layout.Read(ctx, slots, &rates, "rates")
+	fmt.Println(rates)
+
+	start, _ := layout.Value(ctx, slots, "positions", user, "start")
+	fmt.Println(start)
+
+	loc, _ := layout.Locate("rates", 2)
+	fmt.Println(loc.Slot.Big(), loc.Offset)
+
+	_, err = layout.Value(ctx, slots, "balances")
+	fmt.Println(err)
+
+	// Output:
+	// 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 30 true
+	// 5000
+	// [7 8]
+	// Vault
+	// 1000000000000000000 1700000000 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48
+	// [100 200 300]
+	// 1700000000
+	// 6 4
+	// mapping(address => uint256) can only be read with a key
+}
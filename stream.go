@@ -0,0 +1,475 @@
+package abi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// StreamDecoder decodes ABI encoded values read from an io.Reader,
+// reading only the sections needed by each call instead of the whole
+// encoded data. It is meant for very large return data, whose dynamic
+// arrays can be iterated one element at a time with Elements.
+//
+// Readers implementing io.ReaderAt (i.e. *bytes.Reader or *os.File)
+// are read at random positions. Other readers are read sequentially,
+// which requires values and elements to be read in the order they are
+// encoded, as produced by Encode and by Solidity.
+type StreamDecoder struct {
+	src      streamSource
+	typeStrs []string
+	heads    []int // position of the head of each value
+}
+
+// NewStreamDecoder creates a decoder of values of given type strings
+// encoded in r.
+func NewStreamDecoder(r io.Reader, typeStrs []string) (*StreamDecoder, error) {
+	heads := make([]int, len(typeStrs))
+	var cursor int
+	for i, typeStr := range typeStrs {
+		size, err := headSize(typeStr)
+		if err != nil {
+			return nil, err
+		}
+		if cursor > math.MaxInt-size {
+			return nil, fmt.Errorf("size of head of %d values overflows", len(typeStrs))
+		}
+		heads[i] = cursor
+		cursor += size
+	}
+
+	var src streamSource
+	if readerAt, ok := r.(io.ReaderAt); ok {
+		src = &readerAtSource{r: readerAt}
+	} else {
+		// The head is kept buffered since values are located from it,
+		// and read without allocating it whole upfront since its size
+		// comes from the type strings rather than from the data.
+		head, err := io.ReadAll(io.LimitReader(r, int64(cursor)))
+		if err != nil {
+			return nil, fmt.Errorf("data too short to decode head: %w", err)
+		}
+		if len(head) < cursor {
+			return nil, fmt.Errorf("data too short to decode head: %w", io.ErrUnexpectedEOF)
+		}
+		src = &sequentialSource{r: r, head: head, start: cursor}
+	}
+
+	return &StreamDecoder{src: src, typeStrs: typeStrs, heads: heads}, nil
+}
+
+// Value decodes the whole value at given index, as Decode would.
+func (d *StreamDecoder) Value(index int) (any, error) {
+	if index < 0 || index >= len(d.typeStrs) {
+		return nil, fmt.Errorf("value index %d out of range for %d values", index, len(d.typeStrs))
+	}
+
+	typeStr := d.typeStrs[index]
+	start := d.heads[index]
+	if IsDynamic(typeStr, false) {
+		var err error
+		start, err = d.src.readOffset(d.heads[index], 0)
+		if err != nil {
+			return nil, fmt.Errorf("error reading offset of %s: %w", typeStr, err)
+		}
+	}
+
+	return decodeStreamed(d.src, typeStr, start)
+}
+
+// Elements returns an iterator over the elements of the array at given
+// index (i.e. a `(address,uint256)[]` value), decoding each element
+// only when reached.
+func (d *StreamDecoder) Elements(index int) (*ElementIterator, error) {
+	if index < 0 || index >= len(d.typeStrs) {
+		return nil, fmt.Errorf("value index %d out of range for %d values", index, len(d.typeStrs))
+	}
+
+	typeStr := d.typeStrs[index]
+	isTypeArray, arraySize, err := IsArray(typeStr)
+	if err != nil {
+		return nil, err
+	}
+	if !isTypeArray {
+		return nil, fmt.Errorf("value %d of type %s is not an array", index, typeStr)
+	}
+
+	base := d.heads[index]
+	if IsDynamic(typeStr, false) {
+		base, err = d.src.readOffset(d.heads[index], 0)
+		if err != nil {
+			return nil, fmt.Errorf("error reading offset of %s: %w", typeStr, err)
+		}
+	}
+
+	length := arraySize
+	if arraySize == 0 {
+		length, err = d.src.readOffset(base, 0)
+		if err != nil {
+			return nil, fmt.Errorf("error reading length of %s: %w", typeStr, err)
+		}
+		base += 32
+	}
+
+	elemTypeStr := typeStr[:strings.LastIndex(typeStr, "[")]
+	elemSize, err := headSize(elemTypeStr)
+	if err != nil {
+		return nil, err
+	}
+	if length > math.MaxInt32/max(elemSize, 1) {
+		return nil, fmt.Errorf("invalid length %d for %s", length, typeStr)
+	}
+
+	it := &ElementIterator{
+		src:         d.src,
+		elemTypeStr: elemTypeStr,
+		elemSize:    elemSize,
+		base:        base,
+		length:      length,
+		index:       -1,
+	}
+
+	// Offsets of dynamic elements precede all elements, so they are read
+	// before any element is released.
+	if IsDynamic(elemTypeStr, false) {
+		it.offsets = []int{}
+		for i := 0; i < length; i++ {
+			offset, err := d.src.readOffset(base+i*32, base)
+			if err != nil {
+				return nil, fmt.Errorf("error reading offset of element %d: %w", i, err)
+			}
+			it.offsets = append(it.offsets, offset)
+		}
+	}
+
+	return it, nil
+}
+
+// ElementIterator iterates over the elements of an array decoded by a
+// StreamDecoder. Elements are read by calling Next until it returns
+// false, then Err reports the error that stopped the iteration, if any:
+//
+//	for it.Next() {
+//		var item Item
+//		if err := it.Scan(&item); err != nil {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ElementIterator struct {
+	src         streamSource
+	elemTypeStr string
+	elemSize    int
+	offsets     []int // positions of dynamic elements, nil for static ones
+	base        int   // position of the first element head
+	length      int
+	index       int
+	value       any
+	err         error
+}
+
+// Len returns the number of elements of the array.
+func (it *ElementIterator) Len() int {
+	return it.length
+}
+
+// Next decodes the next element, returning false when there are no more
+// elements or an error occurred.
+func (it *ElementIterator) Next() bool {
+	if it.err != nil || it.index+1 >= it.length {
+		it.value = nil
+		return false
+	}
+	it.index++
+
+	start := it.base + it.index*it.elemSize
+	if it.offsets != nil {
+		start = it.offsets[it.index]
+	}
+
+	it.value, it.err = decodeStreamed(it.src, it.elemTypeStr, start)
+	if it.err != nil {
+		it.err = fmt.Errorf("error decoding element %d: %w", it.index, it.err)
+		it.value = nil
+		return false
+	}
+
+	return true
+}
+
+// NextCtx decodes the next element like Next, unless ctx is done, in
+// which case it returns false and Err returns ctx.Err().
+func (it *ElementIterator) NextCtx(ctx context.Context) bool {
+	if it.err == nil && it.index+1 < it.length {
+		it.err = ctx.Err()
+	}
+
+	return it.Next()
+}
+
+// Index returns the index of the current element.
+func (it *ElementIterator) Index() int {
+	return it.index
+}
+
+// Value returns the current element, in the same form as Decode.
+func (it *ElementIterator) Value() any {
+	return it.value
+}
+
+// Scan parses the current element into the value pointed by v, i.e. a
+// struct for tuple elements or a *big.Int for integer elements.
+func (it *ElementIterator) Scan(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("[Scan] v must be a pointer")
+	}
+	if it.value == nil {
+		return fmt.Errorf("[Scan] no current element")
+	}
+
+	target := rv.Elem()
+	return setterFor(target.Type())(&parseState{}, target, it.value, nil)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ElementIterator) Err() error {
+	return it.err
+}
+
+// decodeStreamed decodes the value of given type encoded at start.
+func decodeStreamed(src streamSource, typeStr string, start int) (any, error) {
+	size, err := encodedSize(src, typeStr, start)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := src.read(start, size)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", typeStr, err)
+	}
+
+	// Decode expects dynamic values after their offset in the head.
+	if IsDynamic(typeStr, false) {
+		head := make([]byte, 32, 32+len(data))
+		head[31] = 32
+		data = append(head, data...)
+	}
+
+	decoded, err := Decode([]string{typeStr}, data)
+	if err != nil {
+		return nil, err
+	}
+	src.release(start + size)
+
+	return decoded[0], nil
+}
+
+// encodedSize computes the number of bytes of the value of given type
+// encoded at start, reading only its lengths and offsets.
+func encodedSize(src streamSource, typeStr string, start int) (int, error) {
+	if !IsDynamic(typeStr, false) {
+		return headSize(typeStr)
+	}
+
+	if typeStr == "string" || typeStr == "bytes" {
+		length, err := src.readOffset(start, 0)
+		if err != nil {
+			return 0, fmt.Errorf("error reading length of %s: %w", typeStr, err)
+		}
+		return 32 + (length+31)/32*32, nil
+	}
+
+	var memberTypes []string
+	var base int
+	isTypeArray, arraySize, err := IsArray(typeStr)
+	if err != nil {
+		return 0, err
+	}
+	if isTypeArray {
+		base = start
+		length := arraySize
+		if arraySize == 0 {
+			length, err = src.readOffset(start, 0)
+			if err != nil {
+				return 0, fmt.Errorf("error reading length of %s: %w", typeStr, err)
+			}
+			base += 32
+		}
+
+		elemTypeStr := typeStr[:strings.LastIndex(typeStr, "[")]
+		if !IsDynamic(elemTypeStr, false) {
+			elemSize, err := headSize(elemTypeStr)
+			if err != nil {
+				return 0, err
+			}
+			if length > math.MaxInt32/max(elemSize, 1) {
+				return 0, fmt.Errorf("invalid length %d for %s", length, typeStr)
+			}
+			return base - start + length*elemSize, nil
+		}
+		if length > math.MaxInt32/32 {
+			return 0, fmt.Errorf("invalid length %d for %s", length, typeStr)
+		}
+
+		memberTypes = make([]string, length)
+		for i := range memberTypes {
+			memberTypes[i] = elemTypeStr
+		}
+	} else {
+		_, memberTypes, err = tupleType(typeStr)
+		if err != nil {
+			return 0, err
+		}
+		base = start
+	}
+
+	// The value ends with its head or with the end of its furthest
+	// dynamic member.
+	end := base
+	cursor := base
+	for _, memberType := range memberTypes {
+		size, err := headSize(memberType)
+		if err != nil {
+			return 0, err
+		}
+		end = max(end, cursor+size)
+
+		if IsDynamic(memberType, false) {
+			offset, err := src.readOffset(cursor, base)
+			if err != nil {
+				return 0, fmt.Errorf("error reading offset of %s: %w", memberType, err)
+			}
+			memberSize, err := encodedSize(src, memberType, offset)
+			if err != nil {
+				return 0, err
+			}
+			end = max(end, offset+memberSize)
+		}
+		cursor += size
+	}
+
+	return end - start, nil
+}
+
+// streamChunkSize is the size of the chunks read by stream sources.
+const streamChunkSize = 64 << 10
+
+// streamSource gives access to the encoded data of a StreamDecoder.
+type streamSource interface {
+	// read reads n bytes at given position.
+	read(pos int, n int) ([]byte, error)
+	// release reports that no data before given position will be read
+	// anymore.
+	release(pos int)
+	// readOffset reads the word at given position as an offset relative
+	// to base (or as a length when base is 0), returning its absolute
+	// position.
+	readOffset(pos int, base int) (int, error)
+}
+
+// readerAtSource reads encoded data at random positions.
+type readerAtSource struct {
+	r io.ReaderAt
+}
+
+func (s *readerAtSource) read(pos int, n int) ([]byte, error) {
+	// Data is read in chunks so that corrupt lengths don't allocate
+	// more than the available data.
+	data := make([]byte, 0, min(n, streamChunkSize))
+	for len(data) < n {
+		chunk := make([]byte, min(n-len(data), streamChunkSize))
+		read, err := s.r.ReadAt(chunk, int64(pos+len(data)))
+		data = append(data, chunk[:read]...)
+		if read < len(chunk) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+func (s *readerAtSource) release(int) {}
+
+func (s *readerAtSource) readOffset(pos int, base int) (int, error) {
+	return readOffset(s, pos, base)
+}
+
+// sequentialSource reads encoded data sequentially, buffering the head
+// and the data that is not released yet.
+type sequentialSource struct {
+	r      io.Reader
+	head   []byte
+	buffer []byte
+	start  int // position of the first buffered byte after the head
+	offset int // number of released bytes at the start of the buffer
+}
+
+func (s *sequentialSource) read(pos int, n int) ([]byte, error) {
+	if pos+n <= len(s.head) {
+		return s.head[pos : pos+n], nil
+	}
+	if pos < s.start+s.offset {
+		return nil, fmt.Errorf("position %d was already read from a sequential reader, use an io.ReaderAt for non-standard encodings", pos)
+	}
+
+	for s.start+len(s.buffer) < pos+n {
+		chunk := make([]byte, streamChunkSize)
+		read, err := s.r.Read(chunk)
+		s.buffer = append(s.buffer, chunk[:read]...)
+		if err == io.EOF && s.start+len(s.buffer) < pos+n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+
+	return s.buffer[pos-s.start : pos-s.start+n], nil
+}
+
+func (s *sequentialSource) release(pos int) {
+	if pos <= s.start+s.offset {
+		return
+	}
+	s.offset = min(pos-s.start, len(s.buffer))
+
+	// Released bytes are only dropped once they fill a chunk, so that
+	// releasing elements one by one doesn't move the buffer each time.
+	// Decoded values may share the buffer, which is resliced rather
+	// than compacted in place.
+	if s.offset >= streamChunkSize {
+		s.buffer = s.buffer[s.offset:]
+		s.start += s.offset
+		s.offset = 0
+	}
+}
+
+func (s *sequentialSource) readOffset(pos int, base int) (int, error) {
+	return readOffset(s, pos, base)
+}
+
+// readOffset reads the word at given position of src as an offset
+// relative to base.
+func readOffset(src streamSource, pos int, base int) (int, error) {
+	word, err := src.read(pos, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := new(big.Int).SetBytes(word)
+	if !offset.IsInt64() || offset.Int64() > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid offset %s", offset)
+	}
+
+	return base + int(offset.Int64()), nil
+}
@@ -0,0 +1,215 @@
+package abi_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+type exampleBlob struct {
+	Owner common.Address
+	Data  []byte
+}
+
+func ExampleStreamDecoder() {
+	owner := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	typeStrs := []string{"uint256", "(address,bytes)[]", "string"}
+	encoded, err := abi.Encode(
+		typeStrs,
+		big.NewInt(3),
+		[]any{
+			[]any{&owner, []byte("first")},
+			[]any{&owner, []byte("second")},
+			[]any{&owner, []byte("third")},
+		},
+		"done",
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Any io.Reader can be used, i.e. the body of an HTTP response.
+	decoder, err := abi.NewStreamDecoder(io.MultiReader(bytes.NewReader(encoded)), typeStrs)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	count, err := decoder.Value(0)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println("count:", count)
+
+	blobs, err := decoder.Elements(1)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for blobs.Next() {
+		var blob exampleBlob
+		err := blobs.Scan(&blob)
+		if err != nil {
+			fmt.Println(err)
+		}
+		fmt.Println(blobs.Index(), blob.Owner, string(blob.Data))
+	}
+	if err := blobs.Err(); err != nil {
+		fmt.Println(err)
+	}
+
+	status, err := decoder.Value(2)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println("status:", status)
+
+	// Output:
+	// count: 3
+	// 0 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 first
+	// 1 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 second
+	// 2 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 third
+	// status: done
+}
+
+func ExampleStreamDecoder_Elements() {
+	typeStrs := []string{"uint256[]", "string[2]"}
+	encoded, err := abi.Encode(
+		typeStrs,
+		[]any{big.NewInt(10), big.NewInt(20), big.NewInt(30)},
+		[]any{"a", "b"},
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Readers implementing io.ReaderAt are read in any order.
+	decoder, err := abi.NewStreamDecoder(bytes.NewReader(encoded), typeStrs)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	names, err := decoder.Elements(1)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for names.Next() {
+		fmt.Println(names.Value())
+	}
+
+	amounts, err := decoder.Elements(0)
+	if err != nil {
+		fmt.Println(err)
+	}
+	sum := new(big.Int)
+	for amounts.Next() {
+		var amount *big.Int
+		err := amounts.Scan(&amount)
+		if err != nil {
+			fmt.Println(err)
+		}
+		sum.Add(sum, amount)
+	}
+	fmt.Println(amounts.Len(), sum, amounts.Err())
+
+	// Output:
+	// a
+	// b
+	// 3 60 <nil>
+}
+
+func ExampleNewStreamDecoder_invalidTypes() {
+	typeStrs := [][]string{
+		{"uint256[-1]"},
+		{"uint256[288230376151711744]"},
+		{"uint256[144115188075855872]", "uint256[144115188075855872]"},
+	}
+	for _, typeStrs := range typeStrs {
+		_, err := abi.NewStreamDecoder(io.MultiReader(bytes.NewReader(make([]byte, 64))), typeStrs)
+		fmt.Println(err)
+	}
+
+	_, err := abi.NewStreamDecoder(io.MultiReader(bytes.NewReader(make([]byte, 64))), []string{"uint256[1000000000000]"})
+	fmt.Println(err)
+
+	// Output:
+	// invalid array definition
+	// size of uint256[288230376151711744] overflows
+	// size of head of 2 values overflows
+	// data too short to decode head: unexpected EOF
+}
+
+func FuzzStreamDecoder(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, typeIndex uint8, data []byte) {
+		typeStrs := []string{fuzzTypes[int(typeIndex)%len(fuzzTypes)]}
+		decoded, decodeErr := abi.Decode(typeStrs, data)
+
+		// Random access and sequential readers.
+		for _, r := range []io.Reader{bytes.NewReader(data), io.MultiReader(bytes.NewReader(data))} {
+			d, err := abi.NewStreamDecoder(r, typeStrs)
+			if err != nil {
+				continue
+			}
+			value, err := d.Value(0)
+			if err == nil && decodeErr == nil && !reflect.DeepEqual(value, decoded[0]) {
+				t.Fatalf("streamed %v, decoded %v", value, decoded[0])
+			}
+		}
+
+		d, err := abi.NewStreamDecoder(bytes.NewReader(data), typeStrs)
+		if err != nil {
+			return
+		}
+		it, err := d.Elements(0)
+		if err != nil {
+			return
+		}
+		for i := 0; i < 64 && it.Next(); i++ {
+			_ = it.Value()
+		}
+	})
+}
+
+func BenchmarkStreamDecoder(b *testing.B) {
+	values := make([]any, 320_000)
+	for i := range values {
+		values[i] = big.NewInt(int64(i))
+	}
+	typeStrs := []string{"uint256[]"}
+	encoded, err := abi.Encode(typeStrs, values)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	readers := map[string]func() io.Reader{
+		"readerAt": func() io.Reader { return bytes.NewReader(encoded) },
+		"reader":   func() io.Reader { return io.MultiReader(bytes.NewReader(encoded)) },
+	}
+	for name, newReader := range readers {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				decoder, err := abi.NewStreamDecoder(newReader(), typeStrs)
+				if err != nil {
+					b.Fatal(err)
+				}
+				elems, err := decoder.Elements(0)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for elems.Next() {
+					_ = elems.Value()
+				}
+				if err := elems.Err(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tagKey is the struct tag key read by the parser.
+const tagKey = "abi"
+
+// FieldTag holds the options given in an `abi` struct tag. Tags have
+// the form `abi:"name,key=value,..."`, where the name is optional
+// (i.e. `abi:"to"`, `abi:"index=1"` or `abi:"to,index=1"`). Enum members
+// are separated by `|` (i.e. `abi:"status,enum=Open|Filled|Cancelled"`).
+// Fields tagged with `abi:"-"` are skipped, fields tagged with
+// `abi:",span=N"` receive N consecutive decoded values at once, and map
+// fields tagged with `abi:",mapkey=key"` receive arrays of tuples keyed
+// by given member, decimal fields tagged with `abi:",decimals=N"`
+// receive integers divided by 10^N, and pointer fields tagged with
+// `abi:",omitzero"` are left nil when receiving zero values. It is
+// exported so that packages reading the same tags (i.e. eip712) follow
+// the same conventions.
+type FieldTag struct {
+	Name  string // ABI component name, empty when not set
+	Index int    // explicit position in the decoded values, -1 when not set
+	Type  string // explicit ABI type of the field, empty when not set
+	// Enum holds the member names of a Solidity enum (i.e.
+	// `abi:",enum=Open|Closed"`), nil when not set.
+	Enum []string
+	// Skip is set for fields tagged with `abi:"-"`, which do not map to
+	// any decoded value.
+	Skip bool
+	// Span is the number of consecutive decoded values the field
+	// receives, 1 unless set with `abi:",span=N"`.
+	Span int
+	// MapKey is the name, or the position, of the tuple member keying
+	// the elements of a map field (i.e. `abi:",mapkey=owner"`), empty
+	// when not set.
+	MapKey string
+	// Decimals is the number of decimals scaling the integer values of
+	// a decimal field (i.e. `abi:",decimals=18"`), -1 when not set.
+	Decimals int
+	// OmitZero is set for pointer fields tagged with `abi:",omitzero"`,
+	// which are left nil instead of pointing to zero values, so that
+	// absent values can be told apart from zero ones.
+	OmitZero bool
+}
+
+// ParseFieldTag parses the `abi` struct tag of given field, returning an
+// error for unknown options and invalid option values.
+func ParseFieldTag(field reflect.StructField) (FieldTag, error) {
+	tag := FieldTag{Index: -1, Span: 1, Decimals: -1}
+
+	raw, ok := field.Tag.Lookup(tagKey)
+	if !ok || raw == "" {
+		return tag, nil
+	}
+	if raw == "-" {
+		tag.Skip = true
+		return tag, nil
+	}
+
+	for i, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, isOption := strings.Cut(part, "=")
+		if !isOption {
+			if i != 0 && part == "omitzero" {
+				tag.OmitZero = true
+				continue
+			}
+			if i != 0 {
+				return tag, fmt.Errorf("invalid abi tag %q on field %s: unknown option %q", raw, field.Name, part)
+			}
+			tag.Name = part
+			continue
+		}
+
+		switch key {
+		case "index":
+			index, err := strconv.Atoi(value)
+			if err != nil || index < 0 {
+				return tag, fmt.Errorf("invalid abi tag %q on field %s: invalid index %q", raw, field.Name, value)
+			}
+			tag.Index = index
+		case "type":
+			tag.Type = value
+		case "span":
+			span, err := strconv.Atoi(value)
+			if err != nil || span < 1 {
+				return tag, fmt.Errorf("invalid abi tag %q on field %s: invalid span %q", raw, field.Name, value)
+			}
+			tag.Span = span
+		case "enum":
+			names := strings.Split(value, "|")
+			if len(names) > maxEnumMembers {
+				return tag, fmt.Errorf("invalid abi tag %q on field %s: enums have at most %d members", raw, field.Name, maxEnumMembers)
+			}
+			for _, name := range names {
+				if name == "" {
+					return tag, fmt.Errorf("invalid abi tag %q on field %s: empty enum member", raw, field.Name)
+				}
+			}
+			tag.Enum = names
+		case "mapkey":
+			if value == "" {
+				return tag, fmt.Errorf("invalid abi tag %q on field %s: empty mapkey", raw, field.Name)
+			}
+			tag.MapKey = value
+		case "decimals":
+			decimals, err := strconv.Atoi(value)
+			if err != nil || decimals < 0 || decimals > maxDecimals {
+				return tag, fmt.Errorf("invalid abi tag %q on field %s: invalid decimals %q", raw, field.Name, value)
+			}
+			tag.Decimals = decimals
+		default:
+			return tag, fmt.Errorf("invalid abi tag %q on field %s: unknown option %q", raw, field.Name, key)
+		}
+	}
+
+	if tag.Enum != nil && tag.Span != 1 {
+		return tag, fmt.Errorf("invalid abi tag %q on field %s: enum fields span a single value", raw, field.Name)
+	}
+	if tag.MapKey != "" && (tag.Enum != nil || tag.Span != 1) {
+		return tag, fmt.Errorf("invalid abi tag %q on field %s: map fields can't be enums or span several values", raw, field.Name)
+	}
+	if tag.Decimals != -1 && (tag.Enum != nil || tag.MapKey != "" || tag.Span != 1) {
+		return tag, fmt.Errorf("invalid abi tag %q on field %s: decimal fields can't be enums, maps or span several values", raw, field.Name)
+	}
+
+	if tag.OmitZero && field.Type.Kind() != reflect.Ptr {
+		return tag, fmt.Errorf("invalid abi tag %q on field %s: omitzero fields must be pointers, got %s", raw, field.Name, field.Type)
+	}
+
+	return tag, nil
+}
+
+// positions maps each planned field to the position of its (first)
+// value in decoded values. Fields tagged with an index are mapped to
+// that index, fields tagged with a name are mapped to the component
+// with the same name (when components are given), and all other fields
+// are mapped by their positional order. With a naming strategy, tagged
+// names match components loosely and untagged fields are mapped to the
+// component matching their field name, when any. Fields spanning
+// several values take the positions following their own.
+func (p *structPlan) positions(numDecoded int, components []Component, naming NamingStrategy) ([]int, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	positions := make([]int, len(p.fields))
+	assigned := make(map[int]string, p.numValues)
+
+	var cursor int
+	for i, field := range p.fields {
+		position := cursor
+		switch {
+		case field.tag.Index != -1:
+			if field.tag.Index+field.tag.Span > numDecoded {
+				return nil, fmt.Errorf("field %s tagged with index %d has no corresponding decoded value", field.name, field.tag.Index)
+			}
+			position = field.tag.Index
+		case field.tag.Name != "" && components != nil:
+			var err error
+			position, err = componentPosition(field.tag.Name, components, naming)
+			if err != nil {
+				return nil, fmt.Errorf("field %s tagged with name %q: %w", field.name, field.tag.Name, err)
+			}
+			if position == -1 || position+field.tag.Span > numDecoded {
+				return nil, fmt.Errorf("field %s tagged with name %q has no corresponding decoded value", field.name, field.tag.Name)
+			}
+		case naming != nil && components != nil:
+			matched, err := componentPosition(field.name, components, naming)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.name, err)
+			}
+			if matched != -1 && matched+field.tag.Span <= numDecoded {
+				position = matched
+			}
+		}
+
+		for j := position; j < position+field.tag.Span; j++ {
+			if other, ok := assigned[j]; ok {
+				return nil, fmt.Errorf("fields %s and %s are both mapped to decoded value %d", other, field.name, j)
+			}
+			assigned[j] = field.name
+		}
+		positions[i] = position
+		cursor += field.tag.Span
+	}
+
+	return positions, nil
+}
+
+// values returns the decoded value (or values, as a tuple) and the
+// components of given planned field at given position.
+func (f *fieldPlan) values(decoded []any, components []Component, position int) (any, []Component, *Component) {
+	if f.tag.Span == 1 {
+		if components == nil {
+			return decoded[position], nil, nil
+		}
+		return decoded[position], components[position].Components, &components[position]
+	}
+
+	value := decoded[position : position+f.tag.Span]
+	if components == nil {
+		return value, nil, nil
+	}
+
+	return value, components[position : position+f.tag.Span], nil
+}
@@ -0,0 +1,5 @@
+// Package trace decodes the call frames returned by the `callTracer` of
+// `debug_traceTransaction` (and `debug_traceCall`) into a call tree,
+// resolving the call data, return data and revert data of every frame
+// with the abi package.
+package trace
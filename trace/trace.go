@@ -0,0 +1,210 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/omnes-tech/abi"
+)
+
+// Frame is a call frame of the `callTracer` output, as returned by
+// `debug_traceTransaction` with `{"tracer": "callTracer"}`.
+type Frame struct {
+	Type         string          `json:"type"` // i.e. CALL, STATICCALL, DELEGATECALL or CREATE2
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []Frame         `json:"calls,omitempty"`
+	// Logs are only returned with the `withLog` tracer option.
+	Logs []Log `json:"logs,omitempty"`
+}
+
+// Log is a log emitted by a call frame.
+type Log struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	Position hexutil.Uint   `json:"position"`
+}
+
+// IsCreate checks whether the frame deploys a contract, in which case
+// its input is the init code of the contract.
+func (f *Frame) IsCreate() bool {
+	return strings.HasPrefix(f.Type, "CREATE")
+}
+
+// Reverted checks whether the frame failed.
+func (f *Frame) Reverted() bool {
+	return f.Error != ""
+}
+
+// Call is a decoded call frame along with its decoded sub-calls.
+type Call struct {
+	Frame *Frame
+	Depth int // 0 for the top-level call
+
+	// Method is the called function, nil for contract creations, calls
+	// without call data (i.e. plain transfers), unknown selectors and
+	// calls decoded without registry.
+	Method *abi.Fragment
+	// Args are the decoded arguments of the call.
+	Args []any
+	// Returns are the decoded return values of successful calls.
+	Returns []any
+
+	// RevertName and RevertArgs hold the decoded error of reverted calls
+	// returning revert data (i.e. Error with the revert reason).
+	RevertName string
+	RevertArgs []any
+
+	// Err is the error met decoding the frame (i.e. an unknown selector),
+	// which doesn't prevent its sub-calls from being decoded.
+	Err error
+
+	Calls []*Call
+}
+
+// Decode decodes the top-level call frame and all its sub-calls,
+// resolving their selectors in given registry, which may be nil to only
+// decode revert data. Revert data is decoded
+// as a built-in Error(string) or Panic(uint256) error, or as one of the
+// custom errors given as human-readable fragments in errorABI (see
+// abi.ParseRevert). Frames which can't be decoded hold the error in
+// their Err field.
+func Decode(frame *Frame, registry *abi.Registry, errorABI ...string) *Call {
+	return decodeFrame(frame, 0, registry, errorABI)
+}
+
+// DecodeJSON decodes the JSON result of a `callTracer` trace like
+// Decode.
+func DecodeJSON(data []byte, registry *abi.Registry, errorABI ...string) (*Call, error) {
+	var frame Frame
+	err := json.Unmarshal(data, &frame)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling call frame: %w", err)
+	}
+
+	return Decode(&frame, registry, errorABI...), nil
+}
+
+// decodeFrame decodes a call frame found at given depth.
+func decodeFrame(frame *Frame, depth int, registry *abi.Registry, errorABI []string) *Call {
+	call := &Call{Frame: frame, Depth: depth}
+	call.Err = call.decode(registry, errorABI)
+
+	call.Calls = make([]*Call, len(frame.Calls))
+	for i := range frame.Calls {
+		call.Calls[i] = decodeFrame(&frame.Calls[i], depth+1, registry, errorABI)
+	}
+
+	return call
+}
+
+// decode decodes the call data, return data and revert data of the
+// frame. Revert data is decoded even when the method is unknown, as
+// calls to unknown contracts are common in traces.
+func (c *Call) decode(registry *abi.Registry, errorABI []string) error {
+	frame := c.Frame
+	err := c.decodeInput(registry)
+
+	switch {
+	case frame.Reverted() && len(frame.Output) > 0:
+		name, revertArgs, revertErr := abi.ParseRevert(frame.Output, errorABI...)
+		if revertErr != nil {
+			if err == nil {
+				err = fmt.Errorf("error decoding revert data%s: %w", c.ofMethod(), revertErr)
+			}
+			break
+		}
+		c.RevertName, c.RevertArgs = name, revertArgs
+	case !frame.Reverted() && err == nil && c.Method != nil && len(c.Method.Outputs) > 0:
+		returns, returnErr := abi.Decode(c.Method.OutputTypes(), frame.Output)
+		if returnErr != nil {
+			return fmt.Errorf("error decoding return data%s: %w", c.ofMethod(), returnErr)
+		}
+		c.Returns = returns
+	}
+
+	return err
+}
+
+// decodeInput resolves the method of the frame in given registry, which
+// may be nil, and decodes its arguments.
+func (c *Call) decodeInput(registry *abi.Registry) error {
+	frame := c.Frame
+	if frame.IsCreate() || len(frame.Input) == 0 || registry == nil {
+		return nil
+	}
+
+	if len(frame.Input) < 4 {
+		return fmt.Errorf("call data is too short to contain a selector. Length: %d", len(frame.Input))
+	}
+	method, ok := registry.Lookup([4]byte(frame.Input[:4]))
+	if !ok {
+		return fmt.Errorf("unknown selector: 0x%s", common.Bytes2Hex(frame.Input[:4]))
+	}
+	c.Method = method
+
+	args, err := abi.Decode(method.InputTypes(), frame.Input[4:])
+	if err != nil {
+		return fmt.Errorf("error decoding call to %s: %w", method.Signature(), err)
+	}
+	c.Args = args
+
+	return nil
+}
+
+// ofMethod describes the called method in error messages.
+func (c *Call) ofMethod() string {
+	if c.Method == nil {
+		return ""
+	}
+
+	return " of " + c.Method.Signature()
+}
+
+// ParseArgs parses the decoded arguments of the call into the struct
+// pointed by v. Fields tagged with `abi:"name"` are mapped to the input
+// with the same name.
+func (c *Call) ParseArgs(v any) error {
+	if c.Method == nil {
+		return fmt.Errorf("call to unknown method")
+	}
+
+	return abi.ParseWithComponents(c.Args, c.Method.Inputs, v)
+}
+
+// ParseReturns parses the decoded return values of the call into the
+// struct pointed by v. Fields tagged with `abi:"name"` are mapped to the
+// output with the same name.
+func (c *Call) ParseReturns(v any) error {
+	if c.Method == nil {
+		return fmt.Errorf("call to unknown method")
+	}
+	if c.Frame.Reverted() {
+		return fmt.Errorf("call to %s reverted: %s", c.Method.Name, c.Frame.Error)
+	}
+
+	return abi.ParseWithComponents(c.Returns, c.Method.Outputs, v)
+}
+
+// Walk visits the call and its sub-calls depth-first, in execution
+// order. The sub-calls of a call are skipped when fn returns false.
+func (c *Call) Walk(fn func(call *Call) bool) {
+	if !fn(c) {
+		return
+	}
+
+	for _, call := range c.Calls {
+		call.Walk(fn)
+	}
+}
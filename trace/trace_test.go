@@ -0,0 +1,102 @@
+package trace_test
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/omnes-tech/abi"
+	"github.com/omnes-tech/abi/trace"
+)
+
+func ExampleDecodeJSON() {
+	registry, err := abi.NewRegistry(
+		"function deposit(address token, uint256 amount) returns (uint256 shares)",
+		"function transferFrom(address from, address to, uint256 amount) returns (bool)",
+		"function balanceOf(address owner) view returns (uint256)",
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	user := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	vault := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	deposit, _ := abi.EncodeWithSignature("deposit(address,uint256)", &token, big.NewInt(1000))
+	shares, _ := abi.Encode([]string{"uint256"}, big.NewInt(990))
+	transferFrom, _ := abi.EncodeWithSignature("transferFrom(address,address,uint256)", &user, &vault, big.NewInt(1000))
+	revert, _ := abi.EncodeWithSignature("Error(string)", "insufficient allowance")
+
+	// Result of debug_traceTransaction with {"tracer": "callTracer"}.
+	result := fmt.Sprintf(`{
+		"type": "CALL", "from": "%s", "to": "%s", "gas": "0x7a120", "gasUsed": "0x5208",
+		"input": "%s", "output": "%s",
+		"calls": [{
+			"type": "CALL", "from": "%s", "to": "%s", "gas": "0x6000", "gasUsed": "0x3000",
+			"input": "%s", "output": "%s", "error": "execution reverted"
+		}]
+	}`, user, vault, hexutil.Encode(deposit), hexutil.Encode(shares), vault, token, hexutil.Encode(transferFrom), hexutil.Encode(revert))
+
+	call, err := trace.DecodeJSON([]byte(result), registry)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// The vault catches the failed transfer.
+	call.Walk(func(call *trace.Call) bool {
+		indent := strings.Repeat("  ", call.Depth)
+		if call.Frame.Reverted() {
+			fmt.Println(indent+call.Method.Name, call.Args, "reverted:", call.RevertName, call.RevertArgs)
+		} else {
+			fmt.Println(indent+call.Method.Name, call.Args, "returned:", call.Returns)
+		}
+		return true
+	})
+
+	var out struct {
+		Shares *big.Int `abi:"shares"`
+	}
+	err = call.ParseReturns(&out)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(out.Shares)
+
+	// Output:
+	// deposit [0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 1000] returned: [990]
+	//   transferFrom [0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 0x000000000000000000000000000000000000dEaD 1000] reverted: Error [insufficient allowance]
+	// 990
+}
+
+func ExampleDecode() {
+	registry, err := abi.NewRegistry("function balanceOf(address owner) view returns (uint256)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	user := common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	pool := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	swap, _ := abi.EncodeWithSignature("swap(uint256,uint256)", big.NewInt(1000), big.NewInt(990))
+	revert, _ := abi.EncodeWithSignature("Error(string)", "slippage")
+	frame := &trace.Frame{
+		Type:   "CALL",
+		From:   user,
+		To:     &pool,
+		Input:  swap,
+		Output: revert,
+		Error:  "execution reverted",
+	}
+
+	// The revert reason of a call to an unknown method is decoded, with
+	// or without registry.
+	for _, registry := range []*abi.Registry{registry, nil} {
+		call := trace.Decode(frame, registry)
+		fmt.Println(call.Method == nil, call.Err, call.RevertName, call.RevertArgs)
+	}
+
+	// Output:
+	// true unknown selector: 0xd96073cf Error [slippage]
+	// true <nil> Error [slippage]
+}
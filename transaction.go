@@ -0,0 +1,70 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DecodedTx is a signed transaction decoded by DecodeTransaction.
+type DecodedTx struct {
+	Tx    *types.Transaction // the unmarshaled transaction
+	From  common.Address     // the sender recovered from the signature
+	To    *common.Address    // the recipient, nil for contract creations
+	Value *big.Int           // the amount of wei sent
+	Data  []byte             // the call data
+
+	// Method is the fragment of the called method, nil when the
+	// transaction carries no call data, creates a contract or calls a
+	// selector missing from the registry.
+	Method *Fragment
+	// Args holds the decoded arguments of the called method, which can
+	// be parsed into a struct with Parse.
+	Args []any
+}
+
+// DecodeTransaction unmarshals a signed transaction in its binary form
+// (i.e. as given to eth_sendRawTransaction): legacy, access list
+// (EIP-2930), dynamic fee (EIP-1559) and blob (EIP-4844) transactions
+// are supported. It recovers the sender and, when the selector of the
+// call data is found in given registry (which may be nil), decodes the
+// arguments of the called method.
+func DecodeTransaction(rawTx []byte, registry *Registry) (*DecodedTx, error) {
+	tx := new(types.Transaction)
+	err := tx.UnmarshalBinary(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("[DecodeTransaction] invalid transaction: %w", err)
+	}
+
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("[DecodeTransaction] error recovering sender: %w", err)
+	}
+
+	decoded := &DecodedTx{
+		Tx:    tx,
+		From:  from,
+		To:    tx.To(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	if registry == nil || decoded.To == nil || len(decoded.Data) < 4 {
+		return decoded, nil
+	}
+	if _, ok := registry.Lookup([4]byte(decoded.Data[:4])); !ok {
+		return decoded, nil
+	}
+
+	decoded.Method, decoded.Args, err = decodeCalldata(decoded.Data, registry)
+	if err != nil {
+		return nil, fmt.Errorf("[DecodeTransaction] %w", err)
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,79 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleDecodeTransaction() {
+	registry, err := abi.NewRegistry("function transfer(address to, uint256 amount) returns (bool)")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	data, err := abi.EncodeWithSignature("transfer(address,uint256)", common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"), big.NewInt(1_000_000))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       60_000,
+		To:        &usdc,
+		Data:      data,
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err := abi.DecodeTransaction(rawTx, registry)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	var transfer struct {
+		To     common.Address
+		Amount *big.Int
+	}
+	err = abi.Parse(decoded.Args, &transfer)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(decoded.From, decoded.Tx.Type(), decoded.Method.Name, transfer.To, transfer.Amount)
+
+	// Plain transfers have no method.
+	tx, err = types.SignTx(types.NewTransaction(8, usdc, big.NewInt(1e18), 21_000, big.NewInt(30_000_000_000), nil), types.HomesteadSigner{}, key)
+	if err != nil {
+		fmt.Println(err)
+	}
+	rawTx, err = tx.MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	decoded, err = abi.DecodeTransaction(rawTx, registry)
+	fmt.Println(decoded.From, decoded.Value, decoded.Method == nil, err)
+
+	// Output:
+	// 0x71562b71999873DB5b286dF957af199Ec94617F7 2 transfer 0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789 1000000
+	// 0x71562b71999873DB5b286dF957af199Ec94617F7 1000000000000000000 true <nil>
+}
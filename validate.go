@@ -0,0 +1,394 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists the mismatches found by Validate between a Go
+// type and ABI parameters. Each mismatch is a *ParseError locating the
+// field, without a value type.
+type ValidationError struct {
+	Mismatches []*ParseError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Mismatches))
+	for i, mismatch := range e.Mismatches {
+		var details []string
+		if mismatch.Type != nil {
+			details = append(details, "type "+mismatch.Type.String())
+		}
+		if mismatch.ABIType != "" {
+			details = append(details, "abi type "+mismatch.ABIType)
+		}
+
+		path := strings.TrimPrefix(mismatch.Path, ".")
+		if path == "" {
+			path = "value"
+		}
+		lines[i] = "\n  " + path
+		if len(details) > 0 {
+			lines[i] += " (" + strings.Join(details, ", ") + ")"
+		}
+		lines[i] += ": " + mismatch.Err.Error()
+	}
+
+	return fmt.Sprintf("[Validate] %d mismatches:%s", len(e.Mismatches), strings.Join(lines, ""))
+}
+
+// Validate checks, without any decoded data, that values of the
+// parameters of given signature can be parsed into target, which is a
+// struct (or a pointer to one, possibly nil). It is meant to be called
+// at startup to catch mismatches before the first decode.
+//
+// The signature is either a function, event or error signature, whose
+// inputs are checked, or a bare list of parameters (i.e.
+// `(address to, uint256 amount)`). Parameter names are matched with
+// fields tagged with `abi:"name"`. Fields count, kinds, integer widths,
+// bytes lengths and nesting are checked, and a *ValidationError listing
+// all mismatches is returned. Types with a registered decoder or
+// converter, or implementing ABIUnmarshaler, accept any value.
+func Validate(sig string, target any) error {
+	components, err := signatureComponents(sig)
+	if err != nil {
+		return err
+	}
+
+	t := reflect.TypeOf(target)
+	if t == nil {
+		return fmt.Errorf("[Validate] target must not be nil")
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	v := &validator{}
+	if t.Kind() == reflect.Struct && !isCoreStruct(t) {
+		v.validateStruct(t, components, "")
+	} else if len(components) == 1 {
+		v.validateValue(t, components[0], "")
+	} else {
+		return fmt.Errorf("[Validate] target must be a struct to hold %d parameters, got %s", len(components), t)
+	}
+
+	if len(v.mismatches) > 0 {
+		return &ValidationError{Mismatches: v.mismatches}
+	}
+
+	return nil
+}
+
+// signatureComponents returns the parameters of a signature, or of a
+// bare list of parameters.
+func signatureComponents(sig string) ([]Component, error) {
+	sig = strings.TrimSpace(sig)
+	if strings.HasPrefix(sig, "(") && matchingParenthesis(sig, 0) == len(sig)-1 {
+		return parseParams(sig[1 : len(sig)-1])
+	}
+
+	fragment, err := ParseFragment(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return fragment.Inputs, nil
+}
+
+// validator collects the mismatches found by Validate.
+type validator struct {
+	mismatches []*ParseError
+}
+
+// mismatch records a mismatch at given path.
+func (v *validator) mismatch(path string, t reflect.Type, abiType string, format string, args ...any) {
+	v.mismatches = append(v.mismatches, &ParseError{Path: path, Type: t, ABIType: abiType, Err: fmt.Errorf(format, args...)})
+}
+
+// validateStruct checks the fields of struct type t against the members
+// of a tuple, following the plan used by Parse.
+func (v *validator) validateStruct(t reflect.Type, components []Component, path string) {
+	plan := structPlanFor(t)
+	if plan.numValues != len(components) {
+		v.mismatch(path, t, "", "struct maps %d values, abi tuple has %d", plan.numValues, len(components))
+		return
+	}
+
+	positions, err := plan.positions(len(components), components, nil)
+	if err != nil {
+		v.mismatch(path, t, "", "%v", err)
+		return
+	}
+
+	for i, field := range plan.fields {
+		fieldType := t.FieldByIndex(field.index).Type
+		fieldPath := path + "." + field.name
+		position := positions[i]
+
+		switch {
+		case field.tag.Span > 1:
+			v.validateSpan(fieldType, components[position:position+field.tag.Span], fieldPath)
+		case field.tag.Enum != nil:
+			v.validateEnum(fieldType, field.tag.Enum, components[position], fieldPath)
+		case field.tag.MapKey != "":
+			v.validateMapKey(fieldType, field.tag.MapKey, components[position], fieldPath)
+		case field.tag.Decimals != -1:
+			v.validateDecimals(fieldType, components[position], fieldPath)
+		default:
+			v.validateValue(fieldType, components[position], fieldPath)
+		}
+	}
+}
+
+// validateSpan checks a field receiving several values at once, which
+// must be a struct holding them, or accept any value.
+func (v *validator) validateSpan(t reflect.Type, components []Component, path string) {
+	if acceptsAny(t) {
+		return
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		v.mismatch(path, t, "", "fields spanning %d values must be structs or implement ABIUnmarshaler", len(components))
+		return
+	}
+
+	v.validateStruct(t, components, path)
+}
+
+// validateEnum checks a field tagged with enum members.
+func (v *validator) validateEnum(t reflect.Type, names []string, component Component, path string) {
+	typeStr := component.CanonicalType()
+	bits, isUint := integerBits(typeStr)
+	if !isUint || bits == 0 {
+		v.mismatch(path, t, typeStr, "enum fields must map to unsigned integers")
+		return
+	}
+	if len(names) > maxEnumMembers {
+		v.mismatch(path, t, typeStr, "enums have at most %d members", maxEnumMembers)
+	}
+}
+
+// validateDecimals checks a decimal field tagged with
+// `abi:",decimals=N"`, which must map to an integer.
+func (v *validator) validateDecimals(t reflect.Type, component Component, path string) {
+	typeStr := component.CanonicalType()
+	if bits, _ := integerBits(typeStr); bits == 0 {
+		v.mismatch(path, t, typeStr, "decimal fields must map to integers")
+		return
+	}
+	if !isDecimalType(t) {
+		v.mismatch(path, t, typeStr, "decimal fields must be *big.Rat, *big.Float or implement encoding.TextMarshaler and encoding.TextUnmarshaler")
+	}
+}
+
+// validateMapKey checks a map field tagged with `abi:",mapkey=key"`,
+// which must map to an array of tuples holding the key.
+func (v *validator) validateMapKey(t reflect.Type, key string, component Component, path string) {
+	typeStr := component.CanonicalType()
+	isTypeArray, _, err := IsArray(typeStr)
+	if err != nil || !isTypeArray || strings.Count(component.Type, "[") != 1 || !strings.HasPrefix(component.Type, "tuple") {
+		v.mismatch(path, t, typeStr, "map fields must map to arrays of tuples")
+		return
+	}
+	position, err := mapKeyPosition(key, component.Components, len(component.Components), nil)
+	if err != nil {
+		v.mismatch(path, t, typeStr, "%v", err)
+		return
+	}
+
+	members := component.Components
+	v.validateValue(t.Key(), members[position], path+"."+key)
+
+	valueType := t.Elem()
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	switch {
+	case acceptsAny(t.Elem()):
+	case valueType.Kind() == reflect.Struct && !isCoreStruct(valueType):
+		v.validateStruct(valueType, members, path+"[]")
+	case len(members) != 2:
+		v.mismatch(path, t, typeStr, "map values which are not structs need tuples of 2 members, got %d", len(members))
+	default:
+		v.validateValue(t.Elem(), members[1-position], path+"[]")
+	}
+}
+
+// validateValue checks that values of given component can be parsed
+// into type t.
+func (v *validator) validateValue(t reflect.Type, component Component, path string) {
+	if acceptsAny(t) {
+		return
+	}
+	if t.Kind() == reflect.Ptr && t != bigIntPtrType && t != bigFloatPtrType && t != bigRatPtrType {
+		v.validateValue(t.Elem(), component, path)
+		return
+	}
+
+	typeStr := component.CanonicalType()
+	isTypeArray, arraySize, err := IsArray(typeStr)
+	if err != nil {
+		v.mismatch(path, t, typeStr, "%v", err)
+		return
+	}
+
+	if isTypeArray {
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			v.mismatch(path, t, typeStr, "arrays must be parsed into slices or arrays")
+			return
+		}
+		if t.Kind() == reflect.Array && arraySize != 0 && t.Len() != arraySize {
+			v.mismatch(path, t, typeStr, "array length %d does not match abi length %d", t.Len(), arraySize)
+			return
+		}
+
+		elem := component
+		elem.Type = component.Type[:strings.LastIndex(component.Type, "[")]
+		v.validateValue(t.Elem(), elem, path+"[]")
+		return
+	}
+
+	if strings.HasPrefix(component.Type, "tuple") {
+		switch {
+		case t == anySliceType:
+		case t.Kind() == reflect.Struct && !isCoreStruct(t):
+			v.validateStruct(t, component.Components, path)
+		default:
+			v.mismatch(path, t, typeStr, "tuples must be parsed into structs or []any")
+		}
+		return
+	}
+
+	if reason := coreMismatch(t, typeStr); reason != "" {
+		v.mismatch(path, t, typeStr, "%s", reason)
+	}
+}
+
+// coreMismatch describes why values of given non-array and non-tuple
+// type can't be parsed into type t, or returns an empty string.
+func coreMismatch(t reflect.Type, typeStr string) string {
+	kind := t.Kind()
+	isByteArray := kind == reflect.Array && t.Elem().Kind() == reflect.Uint8
+	isBytes := kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+
+	switch {
+	case typeStr == "address":
+		if t == addressType || kind == reflect.String {
+			return ""
+		}
+		return "addresses must be parsed into common.Address or string"
+
+	case typeStr == "bool":
+		if kind == reflect.Bool {
+			return ""
+		}
+		return "booleans must be parsed into bool"
+
+	case typeStr == "string":
+		if kind == reflect.String || isBytes {
+			return ""
+		}
+		return "strings must be parsed into string or []byte"
+
+	case typeStr == "function":
+		if t == functionPointerType {
+			return ""
+		}
+		return "functions must be parsed into FunctionPointer"
+
+	case typeStr == "bytes":
+		if isBytes || kind == reflect.String {
+			return ""
+		}
+		return "bytes must be parsed into []byte or string"
+
+	case strings.HasPrefix(typeStr, "bytes"):
+		size, _ := strconv.Atoi(typeStr[len("bytes"):])
+		switch {
+		case isBytes || kind == reflect.String || t == bigIntPtrType || t == uint256Type:
+			return ""
+		case t == functionPointerType && size == functionPointerLength:
+			return ""
+		case isByteArray && t.Len() >= size && t.Len() <= 32:
+			return ""
+		case isByteArray:
+			return fmt.Sprintf("byte arrays must hold at least %d bytes", size)
+		}
+		return "fixed bytes must be parsed into []byte, byte arrays, string or *big.Int"
+
+	case isFixedType(typeStr):
+		if t == bigFloatPtrType || t == bigRatPtrType {
+			return ""
+		}
+		return "fixed point numbers must be parsed into *big.Float or *big.Rat"
+	}
+
+	bits, isUint := integerBits(typeStr)
+	if bits == 0 {
+		return "unsupported abi type"
+	}
+
+	switch {
+	case t == bigIntPtrType || t == bigRatPtrType:
+		return ""
+	case t == uint256Type:
+		if isUint {
+			return ""
+		}
+		return "signed integers can't be parsed into uint256.Int"
+	case isIntKind(kind):
+		if t.Bits() > bits || (!isUint && t.Bits() == bits) {
+			return ""
+		}
+		return fmt.Sprintf("%d bits can't hold all values", t.Bits())
+	case isUintKind(kind):
+		if !isUint {
+			return "signed integers must be parsed into signed types"
+		}
+		if t.Bits() >= bits {
+			return ""
+		}
+		return fmt.Sprintf("%d bits can't hold all values", t.Bits())
+	}
+
+	return "integers must be parsed into *big.Int, uint256.Int or integer types"
+}
+
+// integerBits returns the bit size of an intN or uintN type and whether
+// it is unsigned. The size is 0 when the type is not an integer.
+func integerBits(typeStr string) (int, bool) {
+	isUint := strings.HasPrefix(typeStr, "uint")
+	digits := strings.TrimPrefix(strings.TrimPrefix(typeStr, "u"), "int")
+	if !strings.HasPrefix(typeStr, "int") && !isUint {
+		return 0, false
+	}
+	if digits == "" {
+		return 256, isUint
+	}
+
+	bits, err := strconv.Atoi(digits)
+	if err != nil || bits%8 != 0 || bits < 8 || bits > 256 {
+		return 0, false
+	}
+
+	return bits, isUint
+}
+
+// acceptsAny checks whether type t accepts any decoded value, which is
+// the case of interfaces and of types parsed with a hook.
+func acceptsAny(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface || isUnmarshaler(t) {
+		return true
+	}
+	if _, ok := lookupDecoder(t); ok {
+		return true
+	}
+
+	return convertersTo(t) != nil
+}
@@ -0,0 +1,45 @@
+package abi_test
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/omnes-tech/abi"
+)
+
+func ExampleValidate() {
+	type item struct {
+		Owner  common.Address
+		Amount *big.Int
+	}
+	type order struct {
+		Maker  common.Address `abi:"maker"`
+		Items  []item         `abi:"items"`
+		Expiry uint64         `abi:"expiry"`
+	}
+
+	err := abi.Validate("function fill(address maker, (address owner, uint256 amount)[] items, uint64 expiry)", order{})
+	fmt.Println(err)
+
+	type brokenItem struct {
+		Owner  string
+		Amount uint32
+		Extra  bool
+	}
+	type brokenOrder struct {
+		Maker  [20]byte
+		Items  []brokenItem
+		Expiry int64
+	}
+
+	err = abi.Validate("(address maker, (address owner, uint256 amount)[] items, uint64 expiry)", (*brokenOrder)(nil))
+	fmt.Println(err)
+
+	// Output:
+	// <nil>
+	// [Validate] 3 mismatches:
+	//   Maker (type [20]uint8, abi type address): addresses must be parsed into common.Address or string
+	//   Items[] (type abi_test.brokenItem): struct maps 3 values, abi tuple has 2
+	//   Expiry (type int64, abi type uint64): 64 bits can't hold all values
+}